@@ -0,0 +1,129 @@
+// Package cache stores typed values in a key-value store such as Redis. Values are encoded
+// by a pluggable codec under keys prefixed by the schema version of their type, so changing
+// a cached type leaves the entries of its previous shape unread instead of failing to
+// decode them.
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+// ErrCorrupted is returned for the entries that could not be decoded, which are deleted.
+var ErrCorrupted = errors.New("cache: corrupted entry")
+
+// Store holds encoded values by key.
+type Store interface {
+	// Get returns the value of key, found being false when it is missing or expired.
+	Get(ctx context.Context, key string) (value []byte, found bool, err error)
+	// Set stores the value of key for ttl, forever when zero.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, keys ...string) error
+	// Tag attaches key to tags for ttl, forever when zero, so invalidating any of the tags
+	// deletes it.
+	Tag(ctx context.Context, key string, ttl time.Duration, tags ...string) error
+	// InvalidateTags deletes the keys attached to tags, returning how many were attached.
+	InvalidateTags(ctx context.Context, tags ...string) (int, error)
+}
+
+// Cache stores values of type T in a store.
+type Cache[T any] struct {
+	store   Store
+	codec   Codec
+	version string
+	tags    func(T) []string
+}
+
+// New creates a cache of values of type T encoded by codec, JSON when nil.
+func New[T any](store Store, codec Codec) *Cache[T] {
+	if codec == nil {
+		codec = JSONCodec{}
+	}
+	return &Cache[T]{store: store, codec: codec, version: SchemaVersion[T]()}
+}
+
+// TaggedBy attaches every value set from now on to the tags returned by tags, such as the
+// ids of the records it was built from, so invalidating them deletes it.
+func (c *Cache[T]) TaggedBy(tags func(T) []string) *Cache[T] {
+	c.tags = tags
+	return c
+}
+
+// Key returns the key of the store holding the value of key.
+func (c *Cache[T]) Key(key string) string {
+	return "v" + c.version + ":" + key
+}
+
+// Get returns the value of key, found being false when it is missing. Entries that could
+// not be decoded are deleted and reported with ErrCorrupted.
+func (c *Cache[T]) Get(ctx context.Context, key string) (value T, found bool, err error) {
+	data, found, err := c.store.Get(ctx, c.Key(key))
+	if err != nil || !found {
+		return value, false, err
+	}
+	if err := c.codec.Unmarshal(data, &value); err != nil {
+		if err := c.store.Delete(ctx, c.Key(key)); err != nil {
+			log.Printf("Error deleting corrupted cache key %s: %v", key, err)
+		}
+		var zero T
+		return zero, false, fmt.Errorf("%w: %s: %w", ErrCorrupted, key, err)
+	}
+	return value, true, nil
+}
+
+// Set stores the value of key for ttl, forever when zero.
+func (c *Cache[T]) Set(ctx context.Context, key string, value T, ttl time.Duration) error {
+	data, err := c.codec.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode cache key %s: %w", key, err)
+	}
+	if err := c.store.Set(ctx, c.Key(key), data, ttl); err != nil {
+		return err
+	}
+	if c.tags == nil {
+		return nil
+	}
+	if tags := c.tags(value); len(tags) > 0 {
+		if err := c.store.Tag(ctx, c.Key(key), ttl, tags...); err != nil {
+			return fmt.Errorf("failed to tag cache key %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// Delete deletes the values of keys.
+func (c *Cache[T]) Delete(ctx context.Context, keys ...string) error {
+	storeKeys := make([]string, len(keys))
+	for i, key := range keys {
+		storeKeys[i] = c.Key(key)
+	}
+	return c.store.Delete(ctx, storeKeys...)
+}
+
+// GetOrLoad returns the value of key, loading it on a miss. load returns the value along
+// with how long to cache it, a zero ttl leaving it out of the cache. Cache errors are
+// logged and treated as misses, so values keep being loaded while the store is down.
+func (c *Cache[T]) GetOrLoad(ctx context.Context, key string, load func(ctx context.Context) (T, time.Duration, error)) (T, error) {
+	value, found, err := c.Get(ctx, key)
+	if err != nil {
+		log.Printf("Error fetching from cache for key %s, treating it as a miss: %v", key, err)
+	}
+	if found {
+		log.Printf("Cache hit for key %s", key)
+		return value, nil
+	}
+	log.Printf("Cache miss for key %s", key)
+	value, ttl, err := load(ctx)
+	if err != nil || ttl <= 0 {
+		return value, err
+	}
+	if err := c.Set(ctx, key, value, ttl); err != nil {
+		log.Printf("Error setting cache for key %s: %v", key, err)
+		return value, nil
+	}
+	log.Printf("Successfully cached key %s with TTL %v", key, ttl)
+	return value, nil
+}
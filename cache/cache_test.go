@@ -0,0 +1,211 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type gameV1 struct {
+	Title string `json:"title"`
+}
+
+type gameV2 struct {
+	Title string   `json:"title"`
+	Tags  []string `json:"tags"`
+}
+
+func TestCache(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("TestRoundTrips", func(t *testing.T) {
+		games := New[gameV2](NewMemoryStore(), nil)
+
+		assert.NoError(t, games.Set(ctx, "game:1", gameV2{Title: "Celeste", Tags: []string{"indie"}}, time.Minute))
+		game, found, err := games.Get(ctx, "game:1")
+		assert.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, gameV2{Title: "Celeste", Tags: []string{"indie"}}, game)
+
+		assert.NoError(t, games.Delete(ctx, "game:1"))
+		_, found, err = games.Get(ctx, "game:1")
+		assert.NoError(t, err)
+		assert.False(t, found)
+	})
+
+	t.Run("TestVersionsKeysBySchema", func(t *testing.T) {
+		store := NewMemoryStore()
+		assert.NoError(t, New[gameV1](store, nil).Set(ctx, "game:1", gameV1{Title: "Celeste"}, time.Minute))
+
+		// the entry of the previous shape is left unread
+		_, found, err := New[gameV2](store, nil).Get(ctx, "game:1")
+		assert.NoError(t, err)
+		assert.False(t, found)
+		assert.NotEqual(t, SchemaVersion[gameV1](), SchemaVersion[gameV2]())
+		assert.Equal(t, SchemaVersion[gameV2](), SchemaVersion[gameV2]())
+	})
+
+	t.Run("TestDeletesCorruptedEntries", func(t *testing.T) {
+		store := NewMemoryStore()
+		games := New[gameV1](store, nil)
+		store.Set(ctx, games.Key("game:1"), []byte("{"), time.Minute)
+
+		_, found, err := games.Get(ctx, "game:1")
+		assert.ErrorIs(t, err, ErrCorrupted)
+		assert.False(t, found)
+		_, found, _ = store.Get(ctx, games.Key("game:1"))
+		assert.False(t, found, "The corrupted entry should be deleted")
+	})
+
+	t.Run("TestExpiresEntries", func(t *testing.T) {
+		store := NewMemoryStore()
+		now := time.Now()
+		store.now = func() time.Time { return now }
+		games := New[gameV1](store, nil)
+		games.Set(ctx, "game:1", gameV1{Title: "Celeste"}, time.Minute)
+
+		now = now.Add(time.Minute)
+		_, found, err := games.Get(ctx, "game:1")
+		assert.NoError(t, err)
+		assert.False(t, found)
+	})
+
+	t.Run("TestGetOrLoad", func(t *testing.T) {
+		games := New[gameV1](NewMemoryStore(), nil)
+		loads := 0
+		load := func(ttl time.Duration) func(context.Context) (gameV1, time.Duration, error) {
+			return func(context.Context) (gameV1, time.Duration, error) {
+				loads++
+				return gameV1{Title: "Celeste"}, ttl, nil
+			}
+		}
+
+		// values loaded with a zero ttl are not cached
+		games.GetOrLoad(ctx, "game:1", load(0))
+		games.GetOrLoad(ctx, "game:1", load(time.Minute))
+		game, err := games.GetOrLoad(ctx, "game:1", load(time.Minute))
+		assert.NoError(t, err)
+		assert.Equal(t, gameV1{Title: "Celeste"}, game)
+		assert.Equal(t, 2, loads)
+
+		_, err = games.GetOrLoad(ctx, "game:2", func(context.Context) (gameV1, time.Duration, error) {
+			return gameV1{}, time.Minute, errors.New("database down")
+		})
+		assert.Error(t, err)
+		_, found, _ := games.Get(ctx, "game:2")
+		assert.False(t, found, "Failed loads should not be cached")
+	})
+
+	t.Run("TestInvalidatesTaggedEntries", func(t *testing.T) {
+		store := NewMemoryStore()
+		games := New[gameV2](store, nil).TaggedBy(func(game gameV2) []string { return game.Tags })
+		games.Set(ctx, "game:1", gameV2{Title: "Celeste", Tags: []string{"indie", "platformer"}}, time.Minute)
+		games.Set(ctx, "game:2", gameV2{Title: "Hades", Tags: []string{"indie"}}, time.Minute)
+		games.Set(ctx, "game:3", gameV2{Title: "Doom", Tags: []string{"shooter"}}, time.Minute)
+
+		invalidated, err := store.InvalidateTags(ctx, "indie", "strategy")
+		assert.NoError(t, err)
+		assert.Equal(t, 2, invalidated)
+		for key, want := range map[string]bool{"game:1": false, "game:2": false, "game:3": true} {
+			_, found, _ := games.Get(ctx, key)
+			assert.Equal(t, want, found, key)
+		}
+
+		// the tags are dropped along with their keys
+		invalidated, _ = store.InvalidateTags(ctx, "platformer")
+		assert.Equal(t, 1, invalidated)
+		invalidated, _ = store.InvalidateTags(ctx, "indie")
+		assert.Zero(t, invalidated)
+	})
+}
+
+func TestInspector(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	now := time.Now()
+	store.now = func() time.Time { return now }
+	games := New[gameV1](store, nil)
+	games.Set(ctx, "search:game:celeste", gameV1{Title: "Celeste"}, time.Minute)
+	games.Set(ctx, "search:game:hades", gameV1{Title: "Hades"}, 0)
+	games.Set(ctx, "game:id:1", gameV1{Title: "Doom"}, time.Minute)
+	store.Set(ctx, "search:game:celeste", []byte("unversioned"), time.Minute)
+
+	t.Run("TestListsKeysByPrefixOfAnyVersion", func(t *testing.T) {
+		keys, err := store.Keys(ctx, KeyPattern("search:game:"), 0)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{games.Key("search:game:celeste"), games.Key("search:game:hades")}, keys)
+
+		keys, err = store.Keys(ctx, "*", 2)
+		assert.NoError(t, err)
+		all, _ := store.Keys(ctx, "*", 0)
+		assert.Equal(t, all[:2], keys, "The first matching keys should be returned")
+	})
+
+	t.Run("TestInspectsEntries", func(t *testing.T) {
+		entry, found, err := store.Inspect(ctx, games.Key("search:game:celeste"))
+		assert.NoError(t, err)
+		assert.True(t, found)
+		assert.JSONEq(t, `{"title":"Celeste"}`, string(entry.Value))
+		assert.Equal(t, time.Minute, entry.TTL)
+		version, key := SplitKey(entry.Key)
+		assert.Equal(t, SchemaVersion[gameV1](), version)
+		assert.Equal(t, "search:game:celeste", key)
+
+		entry, _, _ = store.Inspect(ctx, games.Key("search:game:hades"))
+		assert.Zero(t, entry.TTL, "Entries that never expire should have no ttl")
+
+		version, key = SplitKey("search:game:celeste")
+		assert.Empty(t, version)
+		assert.Equal(t, "search:game:celeste", key)
+	})
+
+	t.Run("TestPurgesByPattern", func(t *testing.T) {
+		purged, err := store.Purge(ctx, KeyPattern("search:"))
+		assert.NoError(t, err)
+		assert.Equal(t, 2, purged)
+
+		keys, _ := store.Keys(ctx, "*", 0)
+		assert.ElementsMatch(t, []string{games.Key("game:id:1"), "search:game:celeste"}, keys)
+	})
+}
+
+func TestOwnsPattern(t *testing.T) {
+	for pattern, owned := range map[string]bool{
+		"v*:search:*":          true,
+		"v1a2b3c4d:game:id:*":  true,
+		"v????????:*":          true,
+		"tag:game:rawg:*":      true,
+		"*":                    false,
+		"v*":                   false,
+		"rawg:quota:*":         false,
+		"lock:*":               false,
+		"v[0-9]*:search:*":     false,
+		"v1a2b3c4:search:*":    false,
+		"vendor:search:*":      false,
+		"?1a2b3c4d:search:*":   false,
+		"*:search:game:zelda*": false,
+	} {
+		assert.Equal(t, owned, OwnsPattern(pattern), pattern)
+	}
+}
+
+func TestSchemaVersion(t *testing.T) {
+	type node struct {
+		Name     string  `json:"name"`
+		Children []*node `json:"children"`
+		At       time.Time
+		internal int
+	}
+	type renamed struct {
+		Name     string  `json:"label"`
+		Children []*node `json:"children"`
+		At       time.Time
+	}
+
+	assert.Len(t, SchemaVersion[node](), 8)
+	assert.NotEqual(t, SchemaVersion[node](), SchemaVersion[renamed](), "Changing a tag should change the version")
+	assert.NotEqual(t, SchemaVersion[[]node](), SchemaVersion[node]())
+}
@@ -0,0 +1,62 @@
+package cache
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// Inspector browses and purges the entries of a store, for administration.
+type Inspector interface {
+	// Keys returns up to limit keys of the store matching a glob pattern, all when limit
+	// is zero. Which keys are returned when more match, and their order, are not
+	// guaranteed: Redis returns them in the order of SCAN.
+	Keys(ctx context.Context, pattern string, limit int) ([]string, error)
+	// Inspect returns the entry of a key of the store.
+	Inspect(ctx context.Context, key string) (Entry, bool, error)
+	// Purge deletes the keys of the store matching a glob pattern, returning how many.
+	Purge(ctx context.Context, pattern string) (int, error)
+}
+
+// Entry is an encoded value of a store.
+type Entry struct {
+	// Key is the key of the store, prefixed by the schema version of the value
+	Key   string
+	Value []byte
+	// TTL is the time left before the entry expires, zero when it never does
+	TTL time.Duration
+}
+
+// SplitKey returns the schema version and the key of a cache given the key of the store
+// holding it. The version is empty for keys outside of caches, such as those of tags.
+func SplitKey(storeKey string) (version, key string) {
+	prefix, key, ok := strings.Cut(storeKey, ":")
+	if !ok || len(prefix) != 9 || prefix[0] != 'v' {
+		return "", storeKey
+	}
+	return prefix[1:], key
+}
+
+// KeyPattern returns the glob pattern matching the keys of the store holding the cache
+// keys starting with prefix, whatever their schema version.
+func KeyPattern(prefix string) string {
+	escaped := strings.NewReplacer(`\`, `\\`, "*", `\*`, "?", `\?`, "[", `\[`, "]", `\]`).Replace(prefix)
+	return "v*:" + escaped + "*"
+}
+
+// OwnsPattern reports whether a glob pattern can only match keys of the store owned by
+// caches: the cache keys prefixed by a schema version, or by any version with v*, and the
+// tag sets. Other keys, such as locks and quotas, may live in the same Redis database.
+func OwnsPattern(pattern string) bool {
+	if strings.HasPrefix(pattern, "tag:") {
+		return true
+	}
+	prefix, _, ok := strings.Cut(pattern, ":")
+	if !ok || prefix == "" || prefix[0] != 'v' {
+		return false
+	}
+	if prefix == "v*" {
+		return true
+	}
+	return len(prefix) == 9 && strings.Trim(prefix[1:], "0123456789abcdef?") == ""
+}
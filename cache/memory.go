@@ -0,0 +1,160 @@
+package cache
+
+import (
+	"context"
+	"path"
+	"slices"
+	"sync"
+	"time"
+)
+
+// MemoryStore keeps the values of a cache in process, such as in tests.
+type MemoryStore struct {
+	now     func() time.Time
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+	tags    map[string]*memoryTag
+}
+
+// memoryTag is the set of keys attached to a tag, which expires like the Redis sets of a
+// RedisStore.
+type memoryTag struct {
+	keys      map[string]bool
+	expiresAt time.Time // zero when the set never expires
+}
+
+type memoryEntry struct {
+	value     []byte
+	expiresAt time.Time // zero when the entry never expires
+}
+
+// NewMemoryStore creates an empty in-process store.
+func NewMemoryStore() *MemoryStore {
+	return NewMemoryStoreWithClock(time.Now)
+}
+
+// NewMemoryStoreWithClock creates an empty in-process store expiring its values by the time
+// returned by now, such as a fake clock in tests.
+func NewMemoryStoreWithClock(now func() time.Time) *MemoryStore {
+	return &MemoryStore{now: now, entries: map[string]memoryEntry{}, tags: map[string]*memoryTag{}}
+}
+
+func (s *MemoryStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, ok := s.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	if s.expired(entry) {
+		delete(s.entries, key)
+		return nil, false, nil
+	}
+	return entry.value, true, nil
+}
+
+func (s *MemoryStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry := memoryEntry{value: value}
+	if ttl > 0 {
+		entry.expiresAt = s.now().Add(ttl)
+	}
+	s.entries[key] = entry
+	return nil
+}
+
+func (s *MemoryStore) Delete(ctx context.Context, keys ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, key := range keys {
+		delete(s.entries, key)
+	}
+	return nil
+}
+
+func (s *MemoryStore) Tag(ctx context.Context, key string, ttl time.Duration, tags ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, tag := range tags {
+		set := s.tags[tag]
+		if set == nil || s.tagExpired(set) {
+			set = &memoryTag{keys: map[string]bool{}}
+			s.tags[tag] = set
+		}
+		set.keys[key] = true
+		// only ever lengthened, as by RedisStore
+		if expiresAt := s.now().Add(ttl); ttl > 0 && (set.expiresAt.IsZero() || expiresAt.After(set.expiresAt)) {
+			set.expiresAt = expiresAt
+		}
+	}
+	return nil
+}
+
+func (s *MemoryStore) InvalidateTags(ctx context.Context, tags ...string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	deleted := map[string]bool{}
+	for _, tag := range tags {
+		if set := s.tags[tag]; set != nil && !s.tagExpired(set) {
+			for key := range set.keys {
+				delete(s.entries, key)
+				deleted[key] = true
+			}
+		}
+		delete(s.tags, tag)
+	}
+	return len(deleted), nil
+}
+
+// Keys returns the first keys matching pattern in order, so listings are stable in tests.
+func (s *MemoryStore) Keys(ctx context.Context, pattern string, limit int) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	keys := []string{}
+	for key, entry := range s.entries {
+		if s.expired(entry) {
+			continue
+		}
+		if matched, err := path.Match(pattern, key); err != nil {
+			return nil, err
+		} else if matched {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	if limit > 0 && len(keys) > limit {
+		keys = keys[:limit]
+	}
+	return keys, nil
+}
+
+func (s *MemoryStore) Inspect(ctx context.Context, key string) (Entry, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, ok := s.entries[key]
+	if !ok || s.expired(entry) {
+		return Entry{}, false, nil
+	}
+	inspected := Entry{Key: key, Value: entry.value}
+	if !entry.expiresAt.IsZero() {
+		inspected.TTL = entry.expiresAt.Sub(s.now())
+	}
+	return inspected, true, nil
+}
+
+func (s *MemoryStore) Purge(ctx context.Context, pattern string) (int, error) {
+	keys, err := s.Keys(ctx, pattern, 0)
+	if err != nil {
+		return 0, err
+	}
+	return len(keys), s.Delete(ctx, keys...)
+}
+
+func (s *MemoryStore) expired(entry memoryEntry) bool {
+	return !entry.expiresAt.IsZero() && !s.now().Before(entry.expiresAt)
+}
+
+func (s *MemoryStore) tagExpired(set *memoryTag) bool {
+	return !set.expiresAt.IsZero() && !s.now().Before(set.expiresAt)
+}
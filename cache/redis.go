@@ -0,0 +1,152 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisClient is the part of a Redis client used by RedisStore, such as a *redis.Client or
+// a database.TieredCache.
+type RedisClient interface {
+	Get(ctx context.Context, key string) *redis.StringCmd
+	Set(ctx context.Context, key string, value any, expiration time.Duration) *redis.StatusCmd
+	Del(ctx context.Context, keys ...string) *redis.IntCmd
+	TTL(ctx context.Context, key string) *redis.DurationCmd
+	Scan(ctx context.Context, cursor uint64, match string, count int64) *redis.ScanCmd
+	Pipelined(ctx context.Context, fn func(redis.Pipeliner) error) ([]redis.Cmder, error)
+}
+
+// tagKey is the key of the set of keys attached to tag.
+func tagKey(tag string) string {
+	return "tag:" + tag
+}
+
+// RedisStore keeps the values of a cache in Redis.
+type RedisStore struct {
+	client RedisClient
+}
+
+// NewRedisStore creates a store keeping its values in client.
+func NewRedisStore(client RedisClient) *RedisStore {
+	return &RedisStore{client: client}
+}
+
+func (s *RedisStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := s.client.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+func (s *RedisStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	// stored as a string, which the in-process tier of a TieredCache keeps
+	return s.client.Set(ctx, key, string(value), ttl).Err()
+}
+
+func (s *RedisStore) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	return s.client.Del(ctx, keys...).Err()
+}
+
+func (s *RedisStore) Tag(ctx context.Context, key string, ttl time.Duration, tags ...string) error {
+	_, err := s.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, tag := range tags {
+			pipe.SAdd(ctx, tagKey(tag), key)
+			// the set lives as long as the longest-lived key attached to it: its TTL is set
+			// when it has none and otherwise only ever lengthened
+			if ttl > 0 {
+				pipe.ExpireNX(ctx, tagKey(tag), ttl)
+				pipe.ExpireGT(ctx, tagKey(tag), ttl)
+			}
+		}
+		return nil
+	})
+	return err
+}
+
+func (s *RedisStore) InvalidateTags(ctx context.Context, tags ...string) (int, error) {
+	if len(tags) == 0 {
+		return 0, nil
+	}
+	members := make([]*redis.StringSliceCmd, len(tags))
+	if _, err := s.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, tag := range tags {
+			members[i] = pipe.SMembers(ctx, tagKey(tag))
+		}
+		return nil
+	}); err != nil {
+		return 0, err
+	}
+	var keys []string
+	for _, cmd := range members {
+		keys = append(keys, cmd.Val()...)
+	}
+	slices.Sort(keys)
+	keys = slices.Compact(keys)
+	// deleted through the client, which evicts them from the in-process tiers too
+	for batch := range slices.Chunk(keys, 100) {
+		if err := s.Delete(ctx, batch...); err != nil {
+			return 0, err
+		}
+	}
+	tagKeys := make([]string, len(tags))
+	for i, tag := range tags {
+		tagKeys[i] = tagKey(tag)
+	}
+	return len(keys), s.Delete(ctx, tagKeys...)
+}
+
+func (s *RedisStore) Keys(ctx context.Context, pattern string, limit int) ([]string, error) {
+	keys := []string{}
+	var cursor uint64
+	for {
+		page, next, err := s.client.Scan(ctx, cursor, pattern, 100).Result()
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, page...)
+		if limit > 0 && len(keys) >= limit {
+			return keys[:limit], nil
+		}
+		if next == 0 {
+			return keys, nil
+		}
+		cursor = next
+	}
+}
+
+func (s *RedisStore) Inspect(ctx context.Context, key string) (Entry, bool, error) {
+	value, found, err := s.Get(ctx, key)
+	if err != nil || !found {
+		return Entry{}, false, err
+	}
+	ttl, err := s.client.TTL(ctx, key).Result()
+	if err != nil {
+		return Entry{}, false, err
+	}
+	// negative when the key never expires
+	return Entry{Key: key, Value: value, TTL: max(ttl, 0)}, true, nil
+}
+
+func (s *RedisStore) Purge(ctx context.Context, pattern string) (int, error) {
+	keys, err := s.Keys(ctx, pattern, 0)
+	if err != nil {
+		return 0, err
+	}
+	for batch := range slices.Chunk(keys, 100) {
+		if err := s.Delete(ctx, batch...); err != nil {
+			return 0, err
+		}
+	}
+	return len(keys), nil
+}
@@ -0,0 +1,69 @@
+package cache
+
+import (
+	"crypto/sha1"
+	"encoding"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+var (
+	jsonMarshalerType = reflect.TypeFor[json.Marshaler]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+// SchemaVersion returns a digest of the shape of T: the names, tags and types of the
+// exported fields of its structs, through pointers, slices and maps. It changes whenever
+// a field is added, removed, renamed or retyped. Types encoding themselves, such as
+// time.Time, are identified by their name.
+func SchemaVersion[T any]() string {
+	var shape strings.Builder
+	describe(&shape, reflect.TypeFor[T](), map[reflect.Type]bool{})
+	digest := sha1.Sum([]byte(shape.String()))
+	return hex.EncodeToString(digest[:4])
+}
+
+func describe(shape *strings.Builder, t reflect.Type, seen map[reflect.Type]bool) {
+	if t.Implements(jsonMarshalerType) || t.Implements(textMarshalerType) {
+		shape.WriteString(t.String())
+		return
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		shape.WriteString("*")
+		describe(shape, t.Elem(), seen)
+	case reflect.Slice:
+		shape.WriteString("[]")
+		describe(shape, t.Elem(), seen)
+	case reflect.Array:
+		fmt.Fprintf(shape, "[%d]", t.Len())
+		describe(shape, t.Elem(), seen)
+	case reflect.Map:
+		shape.WriteString("map[")
+		describe(shape, t.Key(), seen)
+		shape.WriteString("]")
+		describe(shape, t.Elem(), seen)
+	case reflect.Struct:
+		if seen[t] {
+			// a recursive type, already described
+			shape.WriteString(t.String())
+			return
+		}
+		seen[t] = true
+		shape.WriteString("struct{")
+		for _, field := range reflect.VisibleFields(t) {
+			if !field.IsExported() || field.Anonymous {
+				continue
+			}
+			fmt.Fprintf(shape, "%s %q ", field.Name, field.Tag)
+			describe(shape, field.Type, seen)
+			shape.WriteString(";")
+		}
+		shape.WriteString("}")
+	default:
+		shape.WriteString(t.Kind().String())
+	}
+}
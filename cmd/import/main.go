@@ -0,0 +1,74 @@
+// Command import bulk loads games from RAWG game list responses saved as JSON files,
+// for seeding a database or syncing a large catalog without going through searches.
+//
+//	go run ./cmd/import -file games.json [-file more-games.json]
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/joho/godotenv"
+	"github.com/melkdesousa/gamgo/cache"
+	"github.com/melkdesousa/gamgo/dao"
+	"github.com/melkdesousa/gamgo/dao/models"
+	"github.com/melkdesousa/gamgo/database"
+	"github.com/melkdesousa/gamgo/external/providers"
+	"github.com/melkdesousa/gamgo/external/rawg"
+	"github.com/melkdesousa/gamgo/mappers"
+	"github.com/melkdesousa/gamgo/services"
+)
+
+// fileList collects the repeated -file flag.
+type fileList []string
+
+func (f *fileList) String() string { return strings.Join(*f, ",") }
+
+func (f *fileList) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
+func main() {
+	var files fileList
+	flag.Var(&files, "file", "RAWG game list response to import, can be repeated")
+	flag.Parse()
+	if len(files) == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err := godotenv.Load(); err != nil {
+		log.Println("Warning: No .env file found or error loading .env file")
+	}
+
+	var games []models.Game
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			log.Fatalf("Failed to read %s: %v", file, err)
+		}
+		var response rawg.GameListResponse
+		if err := json.Unmarshal(content, &response); err != nil {
+			log.Fatalf("Failed to parse %s as a RAWG game list: %v", file, err)
+		}
+		games = append(games, mappers.MapGamesJSONToModel(response.Results)...)
+	}
+
+	dbPool := database.GetDBConnection()
+	defer dbPool.Close()
+	cacheClient := database.GetCacheConnection()
+	defer cacheClient.Close()
+	// the imported games are evicted from the cache, and from the in-process caches of the
+	// running instances
+	cacheStore := cache.NewRedisStore(database.NewTieredCache(cacheClient))
+	gameService := services.NewGameService(dao.NewGameDAO(dbPool), cacheStore, nil, nil, providers.NewRegistry())
+	result, err := gameService.ImportGames(context.Background(), games)
+	if err != nil {
+		log.Fatalf("Failed to import %d games: %v", len(games), err)
+	}
+	log.Printf("Imported %d games: %d inserted, %d updated, %d skipped", len(games), result.Inserted, result.Updated, result.Skipped)
+}
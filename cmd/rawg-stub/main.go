@@ -0,0 +1,40 @@
+// Command rawg-stub fakes the RAWG API for local development, serving deterministic
+// responses from a directory of JSON fixtures, the catalog of the rawgtest package by
+// default. Point RAWG_BASE_URL at it.
+//
+//	go run ./cmd/rawg-stub [-addr :3100] [-fixtures dir] [-latency 200ms] [-error-every 10] [-rate-limit-every 5 -retry-after 2s]
+package main
+
+import (
+	"flag"
+	"io/fs"
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/melkdesousa/gamgo/external/rawg/rawgtest"
+)
+
+func main() {
+	addr := flag.String("addr", ":3100", "address to listen on")
+	dir := flag.String("fixtures", "", "directory of the JSON fixtures, the rawgtest catalog when empty")
+	var faults rawgtest.Faults
+	flag.DurationVar(&faults.Latency, "latency", 0, "delay of every response")
+	flag.IntVar(&faults.ErrorEvery, "error-every", 0, "answer every nth request with -error-status")
+	flag.IntVar(&faults.ErrorStatus, "error-status", http.StatusInternalServerError, "status of the injected errors")
+	flag.IntVar(&faults.RateLimitEvery, "rate-limit-every", 0, "answer every nth request with a 429")
+	flag.DurationVar(&faults.RetryAfter, "retry-after", 0, "Retry-After of the injected 429s")
+	flag.Parse()
+
+	fixtures := rawgtest.Fixtures()
+	if *dir != "" {
+		fixtures = os.DirFS(*dir)
+		if _, err := fs.Stat(fixtures, "."); err != nil {
+			log.Fatalf("Failed to open fixtures directory %s: %v", *dir, err)
+		}
+	}
+	log.Printf("Serving the RAWG stub on %s", *addr)
+	if err := http.ListenAndServe(*addr, rawgtest.NewHandler(fixtures, faults)); err != nil {
+		log.Fatalf("Failed to start RAWG stub: %v", err)
+	}
+}
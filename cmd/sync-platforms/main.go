@@ -0,0 +1,28 @@
+// Command sync-platforms stores the platform catalog of RAWG, with platform families and
+// their platforms, so game listings can filter by family.
+//
+//	go run ./cmd/sync-platforms
+package main
+
+import (
+	"context"
+	"log"
+
+	"github.com/joho/godotenv"
+	"github.com/melkdesousa/gamgo/dao"
+	"github.com/melkdesousa/gamgo/database"
+	"github.com/melkdesousa/gamgo/external/rawg"
+	"github.com/melkdesousa/gamgo/services"
+)
+
+func main() {
+	if err := godotenv.Load(); err != nil {
+		log.Println("Warning: No .env file found or error loading .env file")
+	}
+	dbPool := database.GetDBConnection()
+	defer dbPool.Close()
+	platformService := services.NewPlatformService(dao.NewPlatformDAO(dbPool), rawg.NewRawgAPI(rawg.NewRedisUsageStore(database.GetCacheConnection())))
+	if _, err := platformService.SyncPlatforms(context.Background()); err != nil {
+		log.Fatalf("Failed to sync platforms: %v", err)
+	}
+}
@@ -0,0 +1,45 @@
+// Command warmup fills the cache with the first pages of the most popular searches and game
+// listings, such as after Redis was flushed, replaying them like the server does at startup
+// or on schedule.
+//
+//	go run ./cmd/warmup
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/melkdesousa/gamgo/cache"
+	"github.com/melkdesousa/gamgo/config"
+	"github.com/melkdesousa/gamgo/dao"
+	"github.com/melkdesousa/gamgo/database"
+	"github.com/melkdesousa/gamgo/external/providers"
+	"github.com/melkdesousa/gamgo/external/rawg"
+	"github.com/melkdesousa/gamgo/services"
+)
+
+func main() {
+	if err := godotenv.Load(); err != nil {
+		log.Println("Warning: No .env file found or error loading .env file")
+	}
+	dbPool := database.GetDBConnection()
+	defer dbPool.Close()
+	cacheClient := database.GetCacheConnection()
+	defer cacheClient.Close()
+	gameProviders, err := providers.NewRegistryFromEnv(rawg.NewRawgAPI(rawg.NewRedisUsageStore(cacheClient)))
+	if err != nil {
+		log.Fatalf("Failed to configure game providers: %v", err)
+	}
+	// writes are announced to the in-process caches of the running instances
+	cacheStore := cache.NewRedisStore(database.NewTieredCache(cacheClient))
+	locker := database.NewRedisLocker(cacheClient)
+	popularity := dao.NewPopularityDAO(dbPool, config.GetEnvOrDefault("WARMUP_TRACKED_REQUESTS", 1000), time.Duration(config.GetEnvOrDefault("POPULARITY_HALF_LIFE_HOURS", 24))*time.Hour)
+	gameService := services.NewGameService(dao.NewGameDAO(dbPool), cacheStore, locker, popularity, gameProviders)
+	report, err := services.NewWarmer(gameService, popularity, locker).Warm(context.Background())
+	if err != nil {
+		log.Fatalf("Failed to warm the cache up: %v", err)
+	}
+	log.Printf("Warmed %d searches and %d catalogue pages, %d failed", report.Searches, report.CatalogPages, report.Failed)
+}
@@ -1,7 +1,9 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"log"
 	"os"
 	"strconv"
 	"strings"
@@ -85,3 +87,17 @@ func MustGetEnvAs[T any](key string) T {
 	}
 	return value
 }
+
+// GetEnvOrDefault retrieves and converts an environment variable to the specified type,
+// returning the fallback when it is not set or cannot be converted
+func GetEnvOrDefault[T any](key string, fallback T) T {
+	value, err := GetEnvAs[T](key)
+	if err != nil {
+		var notFound EnvNotFoundError
+		if !errors.As(err, &notFound) {
+			log.Printf("Warning: %v. Defaulting to %v.", err, fallback)
+		}
+		return fallback
+	}
+	return value
+}
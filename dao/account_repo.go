@@ -7,16 +7,22 @@ import (
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/melkdesousa/gamgo/dao/models"
+	"github.com/melkdesousa/gamgo/database"
 )
 
 type AccountDAO struct {
-	connection *pgx.Conn
+	connection database.Querier
 }
 
-func NewAccountDAO(connection *pgx.Conn) *AccountDAO {
+func NewAccountDAO(connection database.Querier) *AccountDAO {
 	return &AccountDAO{connection: connection}
 }
 
+// WithTx returns a copy of the DAO bound to the given transaction.
+func (dao *AccountDAO) WithTx(tx pgx.Tx) *AccountDAO {
+	return &AccountDAO{connection: tx}
+}
+
 func (dao *AccountDAO) GetUserByEmail(email string) (*models.Account, error) {
 	account := &models.Account{}
 	var (
@@ -28,16 +34,15 @@ func (dao *AccountDAO) GetUserByEmail(email string) (*models.Account, error) {
 		rawDeletedAt    *time.Time
 		rawIsActive     bool
 	)
-	stmt, err := dao.connection.Prepare(context.Background(), "get_user_by_email", `
+	ctx, cancel := database.WithQueryTimeout(context.Background())
+	defer cancel()
+	// pgx prepares and caches the statement per pooled connection
+	err := dao.connection.
+		QueryRow(ctx, `
 		SELECT
 			id, name, email, passwordHash, createdAt, deletedAt, isActive
 		FROM accounts WHERE email = $1 AND deletedAt IS NULL AND isActive = true
-	`)
-	if err != nil {
-		return nil, err
-	}
-	err = dao.connection.
-		QueryRow(context.Background(), stmt.Name, email).
+	`, email).
 		Scan(
 			&rawID,
 			&rawName,
@@ -0,0 +1,95 @@
+package dao
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/melkdesousa/gamgo/dao/models"
+)
+
+const (
+	cursorNext = "next"
+	cursorPrev = "prev"
+)
+
+// cursor is the decoded form of the opaque pagination token. It holds the sort
+// key and id of the boundary row and a fingerprint of the query it belongs to.
+type cursor struct {
+	Direction   string `json:"d"`
+	Key         string `json:"k"`
+	ID          string `json:"i"`
+	Fingerprint string `json:"f"`
+}
+
+// queryFingerprint identifies a filter and sort, so a cursor can't be replayed
+// against a different query.
+func queryFingerprint(filter models.GameFilter, sort models.GameSort) string {
+	// JSON follows the pointers of optional fields, so equal filters encode equally
+	raw, _ := json.Marshal(filter)
+	sum := sha256.Sum256(append(raw, sort.String()...))
+	return hex.EncodeToString(sum[:8])
+}
+
+func encodeCursor(c cursor) string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeCursor(token, fingerprint string) (cursor, error) {
+	var c cursor
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return c, models.ErrInvalidCursor
+	}
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return c, models.ErrInvalidCursor
+	}
+	if (c.Direction != cursorNext && c.Direction != cursorPrev) || c.ID == "" {
+		return c, models.ErrInvalidCursor
+	}
+	if c.Fingerprint != fingerprint {
+		return c, fmt.Errorf("%w: it was issued for another filter or sort", models.ErrInvalidCursor)
+	}
+	return c, nil
+}
+
+// sortKey renders the value of the sort column of a game as stored in a cursor.
+func sortKey(game models.Game, field models.SortField) string {
+	switch field {
+	case models.SortRelevance:
+		return strconv.FormatFloat(float64(game.Rank), 'g', -1, 32)
+	case models.SortRating:
+		return strconv.Itoa(game.Rating)
+	case models.SortReleaseDate:
+		return game.ReleaseDate.Format(time.DateOnly)
+	default:
+		return game.Title
+	}
+}
+
+// sortKeyType is the SQL type a cursor key is cast to before comparing it.
+var sortKeyType = map[models.SortField]string{
+	models.SortRelevance:   "real",
+	models.SortRating:      "int",
+	models.SortReleaseDate: "date",
+	models.SortTitle:       "text",
+}
+
+// validSortKey checks a cursor key can be cast to the type of the sort column.
+func validSortKey(key string, field models.SortField) bool {
+	var err error
+	switch field {
+	case models.SortRelevance:
+		_, err = strconv.ParseFloat(key, 32)
+	case models.SortRating:
+		_, err = strconv.Atoi(key)
+	case models.SortReleaseDate:
+		_, err = time.Parse(time.DateOnly, key)
+	}
+	return err == nil
+}
@@ -0,0 +1,46 @@
+package dao
+
+import (
+	"testing"
+	"time"
+
+	"github.com/melkdesousa/gamgo/dao/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCursor(t *testing.T) {
+	minRating := 4.0
+	filter := models.GameFilter{Title: "zelda", MinRating: &minRating}
+	sort := models.GameSort{Field: models.SortReleaseDate, Desc: true}
+	fingerprint := queryFingerprint(filter, sort)
+
+	t.Run("RoundTrip", func(t *testing.T) {
+		game := models.Game{ID: "4c6f2d1e-96b4-4b8e-8f0a-2f5b3f0c1a7e", ReleaseDate: time.Date(2017, 3, 3, 0, 0, 0, 0, time.UTC)}
+		token := encodeCursor(cursor{Direction: cursorNext, Key: sortKey(game, sort.Field), ID: game.ID, Fingerprint: fingerprint})
+		decoded, err := decodeCursor(token, fingerprint)
+		assert.NoError(t, err)
+		assert.Equal(t, cursorNext, decoded.Direction)
+		assert.Equal(t, "2017-03-03", decoded.Key)
+		assert.Equal(t, game.ID, decoded.ID)
+		assert.True(t, validSortKey(decoded.Key, sort.Field))
+	})
+
+	t.Run("FingerprintIgnoresPointerIdentity", func(t *testing.T) {
+		sameRating := 4.0
+		assert.Equal(t, fingerprint, queryFingerprint(models.GameFilter{Title: "zelda", MinRating: &sameRating}, sort))
+	})
+
+	t.Run("RejectsCursorOfAnotherQuery", func(t *testing.T) {
+		token := encodeCursor(cursor{Direction: cursorNext, Key: "2017-03-03", ID: "id", Fingerprint: fingerprint})
+		other := queryFingerprint(filter, models.GameSort{Field: models.SortReleaseDate})
+		_, err := decodeCursor(token, other)
+		assert.ErrorIs(t, err, models.ErrInvalidCursor)
+	})
+
+	t.Run("RejectsMalformedCursor", func(t *testing.T) {
+		_, err := decodeCursor("not a cursor", fingerprint)
+		assert.ErrorIs(t, err, models.ErrInvalidCursor)
+		assert.False(t, validSortKey("yesterday", models.SortReleaseDate))
+		assert.False(t, validSortKey("4.5", models.SortRating))
+	})
+}
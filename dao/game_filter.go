@@ -0,0 +1,75 @@
+package dao
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/melkdesousa/gamgo/dao/models"
+)
+
+// whereBuilder collects SQL predicates joined with AND and their positional arguments.
+type whereBuilder struct {
+	clauses []string
+	args    []any
+}
+
+// arg registers a query argument and returns its placeholder.
+func (b *whereBuilder) arg(value any) string {
+	b.args = append(b.args, value)
+	return fmt.Sprintf("$%d", len(b.args))
+}
+
+func (b *whereBuilder) add(clause string) {
+	b.clauses = append(b.clauses, clause)
+}
+
+func (b *whereBuilder) where() string {
+	if len(b.clauses) == 0 {
+		return ""
+	}
+	return "WHERE " + strings.Join(b.clauses, "\n\t\t\tAND ")
+}
+
+// buildGameFilter turns a filter into parameterized predicates. The first
+// argument is always the compiled tsquery, bound in the FROM clause as query.q,
+// so it must be called with the result of compileSearchTerm.
+func buildGameFilter(filter models.GameFilter, tsquery *string) *whereBuilder {
+	b := &whereBuilder{}
+	b.arg(tsquery)
+	if tsquery != nil {
+		b.add("games.search_vector @@ query.q")
+	}
+	// platforms are expanded through the catalog, so a parent matches any of its children
+	if len(filter.Platforms) > 0 {
+		if filter.PlatformMatch == models.PlatformMatchAll {
+			for _, platform := range filter.Platforms {
+				b.add("games.platforms && expand_platforms(" + b.arg([]string{platform}) + "::text[])")
+			}
+		} else {
+			b.add("games.platforms && expand_platforms(" + b.arg(filter.Platforms) + "::text[])")
+		}
+	}
+	if filter.ReleasedFrom != nil {
+		b.add("games.releaseDate >= " + b.arg(*filter.ReleasedFrom) + "::date")
+	}
+	if filter.ReleasedTo != nil {
+		b.add("games.releaseDate <= " + b.arg(*filter.ReleasedTo) + "::date")
+	}
+	// ratings are stored multiplied by 100
+	if filter.MinRating != nil {
+		b.add("games.rating >= " + b.arg(int(math.Round(*filter.MinRating*100))) + "::int")
+	}
+	if filter.MaxRating != nil {
+		b.add("games.rating <= " + b.arg(int(math.Round(*filter.MaxRating*100))) + "::int")
+	}
+	if filter.ExternalSource != "" {
+		b.add("games.externalSource = " + b.arg(filter.ExternalSource))
+	}
+	for _, kind := range models.TaxonomyKinds {
+		if slugs := filter.TaxonomySlugs(kind); len(slugs) > 0 {
+			b.add(taxonomyTables[kind].hasAnyClause(b.arg(slugs)))
+		}
+	}
+	return b
+}
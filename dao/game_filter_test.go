@@ -0,0 +1,67 @@
+package dao
+
+import (
+	"testing"
+	"time"
+
+	"github.com/melkdesousa/gamgo/dao/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildGameFilter(t *testing.T) {
+	t.Run("EmptyFilter", func(t *testing.T) {
+		conditions := buildGameFilter(models.GameFilter{}, nil)
+		assert.Equal(t, "", conditions.where())
+		assert.Equal(t, []any{(*string)(nil)}, conditions.args)
+	})
+
+	t.Run("AllConditionsAreJoinedWithAnd", func(t *testing.T) {
+		tsquery := "'witcher'"
+		from := time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC)
+		to := time.Date(2020, 12, 31, 0, 0, 0, 0, time.UTC)
+		minRating, maxRating := 4.35, 5.0
+		filter := models.GameFilter{
+			Title:          "witcher",
+			Platforms:      []string{"PC"},
+			PlatformMatch:  models.PlatformMatchAll,
+			ReleasedFrom:   &from,
+			ReleasedTo:     &to,
+			MinRating:      &minRating,
+			MaxRating:      &maxRating,
+			ExternalSource: "rawg",
+		}
+		conditions := buildGameFilter(filter, &tsquery)
+		assert.Equal(t, "WHERE games.search_vector @@ query.q\n\t\t\t"+
+			"AND games.platforms && expand_platforms($2::text[])\n\t\t\t"+
+			"AND games.releaseDate >= $3::date\n\t\t\t"+
+			"AND games.releaseDate <= $4::date\n\t\t\t"+
+			"AND games.rating >= $5::int\n\t\t\t"+
+			"AND games.rating <= $6::int\n\t\t\t"+
+			"AND games.externalSource = $7", conditions.where())
+		assert.Equal(t, []any{&tsquery, []string{"PC"}, from, to, 435, 500, "rawg"}, conditions.args)
+	})
+
+	t.Run("TaxonomiesMatchAnySlugOfEachKind", func(t *testing.T) {
+		filter := models.GameFilter{Genres: []string{"rpg", "action"}, Developers: []string{"cd-projekt-red"}}
+		conditions := buildGameFilter(filter, nil)
+		assert.Equal(t, "WHERE EXISTS (SELECT 1 FROM game_genres JOIN genres ON genres.id = game_genres.genreId "+
+			"WHERE game_genres.gameId = games.id AND genres.slug = ANY($2::text[]))\n\t\t\t"+
+			"AND EXISTS (SELECT 1 FROM game_developers JOIN developers ON developers.id = game_developers.developerId "+
+			"WHERE game_developers.gameId = games.id AND developers.slug = ANY($3::text[]))", conditions.where())
+		assert.Equal(t, []any{(*string)(nil), []string{"rpg", "action"}, []string{"cd-projekt-red"}}, conditions.args)
+	})
+
+	t.Run("AnyPlatformOverlaps", func(t *testing.T) {
+		filter := models.GameFilter{Platforms: []string{"PC", "Xbox One"}, PlatformMatch: models.PlatformMatchAny}
+		conditions := buildGameFilter(filter, nil)
+		assert.Equal(t, "WHERE games.platforms && expand_platforms($2::text[])", conditions.where())
+	})
+
+	t.Run("AllPlatformsMatchEachExpandedPlatform", func(t *testing.T) {
+		filter := models.GameFilter{Platforms: []string{"PlayStation", "PC"}, PlatformMatch: models.PlatformMatchAll}
+		conditions := buildGameFilter(filter, nil)
+		assert.Equal(t, "WHERE games.platforms && expand_platforms($2::text[])\n\t\t\t"+
+			"AND games.platforms && expand_platforms($3::text[])", conditions.where())
+		assert.Equal(t, []any{(*string)(nil), []string{"PlayStation"}, []string{"PC"}}, conditions.args)
+	})
+}
@@ -0,0 +1,195 @@
+package dao
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/melkdesousa/gamgo/dao/models"
+	"github.com/melkdesousa/gamgo/database"
+)
+
+// stagingColumns are the columns copied into the staging table of a bulk import. They
+// are followed by a column per kind of taxonomy holding the slugs of the game.
+var stagingColumns = []string{
+	"position", "title", "platforms", "releasedate", "rating", "coverimage", "externalid", "externalsource", "externalslug", "dedupekey",
+	string(models.TaxonomyGenre), string(models.TaxonomyTag), string(models.TaxonomyDeveloper), string(models.TaxonomyPublisher),
+}
+
+// taxonomyStagingColumns are the columns copied into the staging table of the taxonomies
+// of a bulk import.
+var taxonomyStagingColumns = []string{"kind", "slug", "name"}
+
+const createGamesStagingTable = `
+	CREATE TEMPORARY TABLE games_staging (
+		position INTEGER NOT NULL,
+		title TEXT NOT NULL,
+		platforms TEXT [] NOT NULL,
+		releaseDate DATE NOT NULL,
+		rating INTEGER NOT NULL,
+		coverImage TEXT NOT NULL,
+		externalId VARCHAR(255) NOT NULL,
+		externalSource VARCHAR(255) NOT NULL,
+		externalSlug VARCHAR(255),
+		dedupeKey TEXT,
+		genres TEXT [],
+		tags TEXT [],
+		developers TEXT [],
+		publishers TEXT []
+	) ON COMMIT DROP`
+
+const createTaxonomiesStagingTable = `
+	CREATE TEMPORARY TABLE taxonomies_staging (
+		kind TEXT NOT NULL,
+		slug VARCHAR(255) NOT NULL,
+		name TEXT NOT NULL
+	) ON COMMIT DROP`
+
+// mergeStagingTable upserts the staged games, keeping the last copy of games repeated
+// in the batch. Like UpsertManyGames, games already stored from another source by their
+// dedupe key are skipped, as are those staged earlier in the batch from another source.
+// xmax is 0 only for rows inserted by the statement.
+const mergeStagingTable = `
+	WITH merged AS (
+		INSERT INTO games (title, platforms, releaseDate, rating, coverImage, externalId, externalSource, externalSlug, dedupeKey)
+		SELECT title, platforms, releaseDate, rating, coverImage, externalId, externalSource, externalSlug, dedupeKey
+		FROM (
+			SELECT DISTINCT ON (externalSource, externalId) *
+			FROM games_staging
+			ORDER BY externalSource, externalId, position DESC
+		) AS staged
+		WHERE NOT EXISTS (
+			SELECT 1 FROM games
+			WHERE games.dedupeKey = staged.dedupeKey AND games.externalSource <> staged.externalSource
+		) AND NOT EXISTS (
+			SELECT 1 FROM games_staging AS earlier
+			WHERE earlier.dedupeKey = staged.dedupeKey AND earlier.externalSource <> staged.externalSource
+				AND earlier.position < staged.position
+		)
+		ON CONFLICT (externalSource, externalId) DO UPDATE
+		SET title = EXCLUDED.title,
+			platforms = EXCLUDED.platforms,
+			releaseDate = EXCLUDED.releaseDate,
+			rating = EXCLUDED.rating,
+			coverImage = EXCLUDED.coverImage,
+			externalSlug = EXCLUDED.externalSlug,
+			dedupeKey = EXCLUDED.dedupeKey,
+			updatedAt = CURRENT_TIMESTAMP
+		WHERE (games.title, games.platforms, games.releaseDate, games.rating, games.coverImage, games.externalSlug, games.dedupeKey)
+			IS DISTINCT FROM (EXCLUDED.title, EXCLUDED.platforms, EXCLUDED.releaseDate, EXCLUDED.rating, EXCLUDED.coverImage, EXCLUDED.externalSlug, EXCLUDED.dedupeKey)
+		RETURNING xmax = 0 AS inserted
+	)
+	SELECT COUNT(*) FILTER (WHERE inserted), COUNT(*) FILTER (WHERE NOT inserted) FROM merged`
+
+// stagedTaxonomyTargets selects the stored games of the last staged copy of each game
+// along with their slugs of a kind, for the games whose source told them.
+const stagedTaxonomyTargets = `
+	WITH targets AS (
+		SELECT games.id, staged.slugs
+		FROM (
+			SELECT DISTINCT ON (externalSource, externalId) externalSource, externalId, %[4]s AS slugs
+			FROM games_staging
+			ORDER BY externalSource, externalId, position DESC
+		) AS staged
+		JOIN games ON games.externalSource = staged.externalSource AND games.externalId = staged.externalId
+		WHERE staged.slugs IS NOT NULL
+	)`
+
+// mergeStagedTaxonomies queues the statements merging the staged taxonomies of a kind
+// and linking them to the imported games, with the same semantics as UpsertManyGames.
+func mergeStagedTaxonomies(batch *pgx.Batch, kind models.TaxonomyKind) {
+	t := taxonomyTables[kind]
+	batch.Queue(fmt.Sprintf(`
+		INSERT INTO %[1]s (slug, name)
+		SELECT DISTINCT ON (slug) slug, name FROM taxonomies_staging WHERE kind = $1 ORDER BY slug
+		ON CONFLICT (slug) DO UPDATE SET name = EXCLUDED.name
+		WHERE %[1]s.name IS DISTINCT FROM EXCLUDED.name`, t.table), string(kind))
+	batch.Queue(fmt.Sprintf(stagedTaxonomyTargets+`
+		DELETE FROM %[1]s USING targets, %[2]s
+		WHERE %[1]s.gameId = targets.id AND %[2]s.id = %[1]s.%[3]s AND NOT (%[2]s.slug = ANY(targets.slugs))`,
+		t.joinTable, t.table, t.joinColumn, kind))
+	batch.Queue(fmt.Sprintf(stagedTaxonomyTargets+`
+		INSERT INTO %[1]s (gameId, %[3]s)
+		SELECT targets.id, %[2]s.id FROM targets JOIN %[2]s ON %[2]s.slug = ANY(targets.slugs)
+		ON CONFLICT DO NOTHING`,
+		t.joinTable, t.table, t.joinColumn, kind))
+}
+
+// stagingRows renders games as rows of the staging table.
+func stagingRows(games []models.Game) [][]any {
+	rows := make([][]any, len(games))
+	for i, game := range games {
+		rows[i] = []any{
+			i, game.Title, game.Platforms, game.ReleaseDate, game.Rating, game.CoverImage,
+			game.ExternalID, game.ExternalSource, game.ExternalSlug, nullIfEmpty(game.DedupeKey()),
+		}
+		for _, kind := range models.TaxonomyKinds {
+			var slugs []string // NULL when the source did not tell
+			if items := game.Taxonomies(kind); items != nil {
+				slugs, _ = uniqueTaxonomies(items)
+			}
+			rows[i] = append(rows[i], slugs)
+		}
+	}
+	return rows
+}
+
+// taxonomyStagingRows renders the taxonomies of games as rows of the taxonomy staging table.
+func taxonomyStagingRows(games []models.Game) [][]any {
+	var rows [][]any
+	for _, game := range games {
+		for _, kind := range models.TaxonomyKinds {
+			for _, item := range game.Taxonomies(kind) {
+				if item.Slug != "" {
+					rows = append(rows, []any{string(kind), item.Slug, item.Name})
+				}
+			}
+		}
+	}
+	return rows
+}
+
+// BulkImportGames stores a large batch of external games at once. The games and their
+// taxonomies are copied into temporary staging tables and merged with the same upsert
+// semantics as UpsertManyGames, all in one transaction. Unlike UpsertManyGames, ids are
+// not written back. It is not bound by the query timeout, as large imports can take a while.
+func (dao *GameDAO) BulkImportGames(ctx context.Context, games []models.Game) (models.ImportResult, error) {
+	var result models.ImportResult
+	if len(games) == 0 {
+		return result, nil
+	}
+	err := database.InTx(ctx, dao.connection, func(tx pgx.Tx) error {
+		for _, statement := range []string{createGamesStagingTable, createTaxonomiesStagingTable} {
+			if _, err := tx.Exec(ctx, statement); err != nil {
+				return err
+			}
+		}
+		if _, err := tx.CopyFrom(ctx, pgx.Identifier{"games_staging"}, stagingColumns, pgx.CopyFromRows(stagingRows(games))); err != nil {
+			return err
+		}
+		if _, err := tx.CopyFrom(ctx, pgx.Identifier{"taxonomies_staging"}, taxonomyStagingColumns, pgx.CopyFromRows(taxonomyStagingRows(games))); err != nil {
+			return err
+		}
+		if err := tx.QueryRow(ctx, mergeStagingTable).Scan(&result.Inserted, &result.Updated); err != nil {
+			return err
+		}
+		batch := &pgx.Batch{}
+		for _, kind := range models.TaxonomyKinds {
+			mergeStagedTaxonomies(batch, kind)
+		}
+		return tx.SendBatch(ctx, batch).Close()
+	})
+	if err != nil {
+		return models.ImportResult{}, err
+	}
+	result.Skipped = len(games) - result.Inserted - result.Updated
+	return result, nil
+}
+
+// nullIfEmpty maps empty strings to NULL.
+func nullIfEmpty(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
@@ -0,0 +1,50 @@
+package dao
+
+import (
+	"testing"
+	"time"
+
+	"github.com/melkdesousa/gamgo/dao/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStagingRows(t *testing.T) {
+	released := time.Date(2017, 3, 3, 0, 0, 0, 0, time.UTC)
+	games := []models.Game{
+		{
+			Title: "Breath of the Wild", Platforms: []string{"Switch"}, ReleaseDate: released, Rating: 450,
+			ExternalID: "22511", ExternalSource: "rawg", ExternalSlug: "the-legend-of-zelda-breath-of-the-wild",
+			Genres:     []models.Taxonomy{{Slug: "action", Name: "Action"}, {Slug: "adventure", Name: "Adventure"}, {Slug: "action", Name: "Action"}},
+			Developers: []models.Taxonomy{},
+		},
+		{Title: "Celeste", Platforms: []string{"PC"}, ExternalID: "28", ExternalSource: "rawg"},
+	}
+
+	rows := stagingRows(games)
+
+	assert.Len(t, rows, 2)
+	dedupeKey := "breath of the wild:2017"
+	assert.Equal(t, []any{
+		0, "Breath of the Wild", []string{"Switch"}, released, 450, "", "22511", "rawg", "the-legend-of-zelda-breath-of-the-wild", &dedupeKey,
+		[]string{"action", "adventure"}, []string(nil), []string{}, []string(nil),
+	}, rows[0], "Slugs should be unique and unknown taxonomies NULL")
+	assert.Equal(t, 1, rows[1][0], "Rows should keep the position of the game in the batch")
+	assert.Equal(t, (*string)(nil), rows[1][9], "Games without a release date should have no dedupe key")
+	for _, row := range rows {
+		assert.Len(t, row, len(stagingColumns), "Every row should have a value per staging column")
+	}
+}
+
+func TestTaxonomyStagingRows(t *testing.T) {
+	games := []models.Game{
+		{Genres: []models.Taxonomy{{Slug: "indie", Name: "Indie"}}, Tags: []models.Taxonomy{{Slug: "", Name: "Unnamed"}}},
+		{Publishers: []models.Taxonomy{{Slug: "nintendo", Name: "Nintendo"}}},
+	}
+
+	rows := taxonomyStagingRows(games)
+
+	assert.Equal(t, [][]any{
+		{"genres", "indie", "Indie"},
+		{"publishers", "nintendo", "Nintendo"},
+	}, rows, "Entries without a slug should be dropped")
+}
@@ -2,33 +2,106 @@ package dao
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
+	"slices"
+	"strings"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/melkdesousa/gamgo/dao/models"
+	"github.com/melkdesousa/gamgo/dao/search"
+	"github.com/melkdesousa/gamgo/database"
 )
 
 type GameDAO struct {
-	connection *pgx.Conn
+	connection database.Querier
 }
 
-func NewGameDAO(connection *pgx.Conn) *GameDAO {
+func NewGameDAO(connection database.Querier) *GameDAO {
 	return &GameDAO{
 		connection: connection,
 	}
 }
 
-func (dao *GameDAO) SearchGames(ctx context.Context, term string) ([]models.Game, error) {
-	var games []models.Game
-	query := `
-		SELECT id, title, platforms, releaseDate, rating, coverImage, externalId, externalSource FROM games
-		WHERE search_vector @@ to_tsquery('english', $1)`
-	rows, err := dao.connection.Query(ctx, query, term)
+// WithTx returns a copy of the DAO bound to the given transaction, so writes from
+// several repositories can be committed together through database.InTx.
+func (dao *GameDAO) WithTx(tx pgx.Tx) *GameDAO {
+	return &GameDAO{connection: tx}
+}
+
+// compileSearchTerm parses a user search expression into to_tsquery text.
+// An empty term compiles to nil so it can be bound as a NULL "no filter" parameter.
+func compileSearchTerm(term string) (*string, error) {
+	if strings.TrimSpace(term) == "" {
+		return nil, nil
+	}
+	query, err := search.Parse(term)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
+	tsquery := query.TSQuery()
+	return &tsquery, nil
+}
+
+// rankExpression scores a game against the tsquery bound as query.q.
+const rankExpression = `COALESCE(ts_rank_cd(games.search_vector, query.q), 0)`
+
+// gameSearchColumns selects the game columns plus the search rank and snippet.
+// It expects the compiled tsquery to be available as query.q, which is NULL when
+// there is no search term.
+const gameSearchColumns = `
+	games.id, games.title, games.platforms, games.releaseDate, games.rating, games.coverImage,
+	games.externalId, games.externalSource, COALESCE(games.externalSlug, ''),
+	` + rankExpression + ` AS rank,
+	CASE WHEN query.q IS NULL THEN '' ELSE ts_headline(
+		'english',
+		COALESCE(NULLIF(games.description, ''), games.title),
+		query.q,
+		'StartSel=<mark>, StopSel=</mark>, MaxWords=25, MinWords=8, MaxFragments=2'
+	) END AS snippet`
+
+// gameOrderColumns maps sort fields to their SQL expressions. Only these
+// whitelisted expressions are ever interpolated into queries.
+var gameOrderColumns = map[models.SortField]string{
+	models.SortRelevance:   rankExpression,
+	models.SortRating:      "games.rating",
+	models.SortReleaseDate: "games.releaseDate",
+	models.SortTitle:       "games.title",
+}
+
+func orderColumn(sort models.GameSort) string {
+	if column, ok := gameOrderColumns[sort.Field]; ok {
+		return column
+	}
+	return gameOrderColumns[models.SortTitle]
+}
+
+// orderBy renders the ORDER BY clause for a sort, using the id as tie-breaker
+// so the order is deterministic across pages. Reversed pages are read backwards
+// from a prev cursor.
+func orderBy(sort models.GameSort, reversed bool) string {
+	direction := "ASC"
+	if sort.Desc != reversed {
+		direction = "DESC"
+	}
+	return fmt.Sprintf("ORDER BY %s %s, games.id %s", orderColumn(sort), direction, direction)
+}
 
+// addKeyset restricts the rows to those after (or before, for prev cursors) the
+// boundary row of the cursor, in the order of sort.
+func addKeyset(conditions *whereBuilder, sort models.GameSort, c cursor) {
+	operator := ">"
+	if sort.Desc != (c.Direction == cursorPrev) {
+		operator = "<"
+	}
+	key := conditions.arg(c.Key) + "::" + sortKeyType[sort.Field]
+	id := conditions.arg(c.ID) + "::uuid"
+	conditions.add(fmt.Sprintf("(%s, games.id) %s (%s, %s)", orderColumn(sort), operator, key, id))
+}
+
+func scanGames(rows pgx.Rows) ([]models.Game, error) {
+	var games []models.Game
 	for rows.Next() {
 		var game models.Game
 		if err := rows.Scan(
@@ -40,104 +113,298 @@ func (dao *GameDAO) SearchGames(ctx context.Context, term string) ([]models.Game
 			&game.CoverImage,
 			&game.ExternalID,
 			&game.ExternalSource,
+			&game.ExternalSlug,
+			&game.Rank,
+			&game.Snippet,
 		); err != nil {
+			log.Printf("Error scanning game row: %v", err)
 			return nil, err
 		}
 		games = append(games, game)
 	}
+	return games, rows.Err()
+}
 
-	return games, nil
+// SearchGames returns a page of the games matching a search expression.
+func (dao *GameDAO) SearchGames(ctx context.Context, term string, sort models.GameSort, page models.PageRequest) (models.GamePage, error) {
+	return dao.ListGames(ctx, models.GameFilter{Title: term}, sort, page)
 }
 
-func (dao *GameDAO) HasGame(ctx context.Context, term string) (bool, error) {
-	var count int
-	query := `
-		SELECT COUNT(*) FROM games
-		WHERE search_vector @@ to_tsquery('english', $1)`
-	err := dao.connection.QueryRow(ctx, query, term).Scan(&count)
-	if err != nil {
-		return false, err
-	}
-	return count > 0, nil
+// upsertGameQuery inserts a game or refreshes the stored copy of the same external game.
+// Rows whose fields did not change are left untouched, in which case the id is read
+// from the existing row since the upsert returns nothing. Games already stored from
+// another source under the same dedupe key are skipped and return no row.
+const upsertGameQuery = `
+	WITH upserted AS (
+		INSERT INTO games (title, platforms, releaseDate, rating, coverImage, externalId, externalSource, externalSlug, dedupeKey)
+		SELECT $1::text, $2::text[], $3::date, $4::int, $5::text, $6::text, $7::text, $8::text, NULLIF($9::text, '')
+		WHERE NOT EXISTS (
+			SELECT 1 FROM games
+			WHERE dedupeKey = NULLIF($9::text, '') AND externalSource <> $7::text
+		)
+		ON CONFLICT (externalSource, externalId) DO UPDATE
+		SET title = EXCLUDED.title,
+			platforms = EXCLUDED.platforms,
+			releaseDate = EXCLUDED.releaseDate,
+			rating = EXCLUDED.rating,
+			coverImage = EXCLUDED.coverImage,
+			externalSlug = EXCLUDED.externalSlug,
+			dedupeKey = EXCLUDED.dedupeKey,
+			updatedAt = CURRENT_TIMESTAMP
+		WHERE (games.title, games.platforms, games.releaseDate, games.rating, games.coverImage, games.externalSlug, games.dedupeKey)
+			IS DISTINCT FROM (EXCLUDED.title, EXCLUDED.platforms, EXCLUDED.releaseDate, EXCLUDED.rating, EXCLUDED.coverImage, EXCLUDED.externalSlug, EXCLUDED.dedupeKey)
+		RETURNING id
+	)
+	SELECT id FROM upserted
+	UNION ALL
+	SELECT id FROM games
+	WHERE externalSource = $7::text AND externalId = $6::text AND NOT EXISTS (SELECT 1 FROM upserted)`
+
+// UpsertManyGames stores games imported from an external source along with their
+// taxonomies, updating the games already stored for the same source and external id.
+// The ids of the stored rows are written back into games, while games already stored
+// from another source, by their dedupe key, are skipped and left without an id.
+func (dao *GameDAO) UpsertManyGames(ctx context.Context, games []models.Game) error {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+	return database.InTx(ctx, dao.connection, func(tx pgx.Tx) error {
+		stored := make([]models.Game, 0, len(games))
+		for i, game := range games {
+			err := tx.QueryRow(ctx, upsertGameQuery, game.Title, game.Platforms, game.ReleaseDate, game.Rating,
+				game.CoverImage, game.ExternalID, game.ExternalSource, game.ExternalSlug, game.DedupeKey()).Scan(&games[i].ID)
+			if errors.Is(err, pgx.ErrNoRows) {
+				games[i].ID = ""
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			stored = append(stored, games[i])
+		}
+		return saveGamesTaxonomies(ctx, tx, stored)
+	})
 }
 
-func (dao *GameDAO) InsertManyGames(ctx context.Context, games []models.Game) error {
-	tx, err := dao.connection.Begin(ctx)
+// ListGames returns a page of games matching every condition of the filter, along with
+// the total number of matches. Pages are read by offset from page.Number, or by keyset
+// from page.Cursor when it is set, which stays stable while rows are being inserted.
+func (dao *GameDAO) ListGames(ctx context.Context, filter models.GameFilter, sort models.GameSort, page models.PageRequest) (models.GamePage, error) {
+	result := models.GamePage{}
+	tsquery, err := compileSearchTerm(filter.Title)
 	if err != nil {
-		return err
-	}
-	defer tx.Rollback(ctx)
-	for _, game := range games {
-		query := `
-			INSERT INTO games (title, platforms, releaseDate, rating, coverImage, externalId, externalSource)
-			VALUES ($1, $2, $3, $4, $5, $6, $7)`
-		_, err := tx.Exec(ctx, query, game.Title, game.Platforms, game.ReleaseDate, game.Rating, game.CoverImage, game.ExternalID, game.ExternalSource)
+		return result, err
+	}
+	fingerprint := queryFingerprint(filter, sort)
+	var after *cursor
+	if page.Cursor != "" {
+		c, err := decodeCursor(page.Cursor, fingerprint)
 		if err != nil {
-			return err
+			return result, err
 		}
+		if !validSortKey(c.Key, sort.Field) {
+			return result, models.ErrInvalidCursor
+		}
+		after = &c
 	}
-	if err := tx.Commit(ctx); err != nil {
-		return err
-	}
-	return nil
-}
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+	conditions := buildGameFilter(filter, tsquery)
+	countWhere := conditions.where()
+	countArgs := slices.Clone(conditions.args)
 
-func (dao *GameDAO) ListGames(ctx context.Context, page int, platforms []string, title string) ([]models.Game, int, error) {
-	var games []models.Game
-	var total int
-
-	// Query for paginated results
+	// Query for the page, reading one extra row to know whether more rows follow
+	reversed := after != nil && after.Direction == cursorPrev
+	limit := conditions.arg(page.Limit+1) + "::int"
+	pagination := "LIMIT " + limit
+	if after != nil {
+		addKeyset(conditions, sort, *after)
+	} else {
+		pagination += " OFFSET " + conditions.arg((page.Number-1)*page.Limit) + "::int"
+	}
 	query := `
-		SELECT id, title, platforms, releaseDate, rating, coverImage, externalId, externalSource
-		FROM games
-		WHERE (
-			$1::text IS NULL
-			OR search_vector @@ plainto_tsquery($1::text)
-		)
-		OR (
-			$2::text[] IS NULL
-			OR platforms @> $2::text[]
-		)
-		LIMIT 10 OFFSET $3::int`
-	rows, err := dao.connection.Query(ctx, query, title, platforms, (page-1)*10)
+		SELECT ` + gameSearchColumns + `
+		FROM games, to_tsquery('english', $1::text) AS query(q)
+		` + conditions.where() + `
+		` + orderBy(sort, reversed) + `
+		` + pagination
+	rows, err := dao.connection.Query(ctx, query, conditions.args...)
 	if err != nil {
-		return nil, 0, err
+		return result, err
 	}
-	defer rows.Close()
+	games, err := scanGames(rows)
+	rows.Close()
+	if err != nil {
+		return result, err
+	}
+	hasMore := len(games) > page.Limit
+	if hasMore {
+		games = games[:page.Limit]
+	}
+	if reversed {
+		slices.Reverse(games)
+	}
+	result.Games = games
 
-	for rows.Next() {
-		var game models.Game
-		if err := rows.Scan(
-			&game.ID,
-			&game.Title,
-			&game.Platforms,
-			&game.ReleaseDate,
-			&game.Rating,
-			&game.CoverImage,
-			&game.ExternalID,
-			&game.ExternalSource,
-		); err != nil {
-			log.Printf("Error scanning game row: %v", err)
-			return nil, 0, err
+	// A page reached from a next cursor always has a previous page and one
+	// reached from a prev cursor always has a next page.
+	var hasNext, hasPrev bool
+	switch {
+	case after == nil:
+		hasNext, hasPrev = hasMore, page.Number > 1
+	case reversed:
+		hasNext, hasPrev = true, hasMore
+	default:
+		hasNext, hasPrev = hasMore, true
+	}
+	if len(games) > 0 {
+		first, last := games[0], games[len(games)-1]
+		if hasNext {
+			result.Next = encodeCursor(cursor{Direction: cursorNext, Key: sortKey(last, sort.Field), ID: last.ID, Fingerprint: fingerprint})
+		}
+		if hasPrev {
+			result.Prev = encodeCursor(cursor{Direction: cursorPrev, Key: sortKey(first, sort.Field), ID: first.ID, Fingerprint: fingerprint})
 		}
-		games = append(games, game)
 	}
 
-	// Query for total count (without LIMIT/OFFSET)
+	// Query for total count (without pagination)
 	countQuery := `
-		SELECT COUNT(*) FROM games
-		WHERE (
-			$1::text IS NULL
-			OR search_vector @@ plainto_tsquery($1::text)
-		)
-		OR (
-			$2::text[] IS NULL
-			OR platforms @> $2::text[]
-		)`
-	err = dao.connection.QueryRow(ctx, countQuery, title, platforms).Scan(&total)
+		SELECT COUNT(*)
+		FROM games, to_tsquery('english', $1::text) AS query(q)
+		` + countWhere
+	if err := dao.connection.QueryRow(ctx, countQuery, countArgs...).Scan(&result.Total); err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+// gameDetailColumns selects every stored field of a single game.
+const gameDetailColumns = `
+	id, title, COALESCE(description, ''), platforms, releaseDate, rating, coverImage,
+	COALESCE(externalId, ''), COALESCE(externalSource, ''), COALESCE(externalSlug, ''),
+	COALESCE(website, ''), COALESCE(metacritic, 0), COALESCE(playtime, 0), detailsFetchedAt`
+
+func scanGameDetail(row pgx.Row) (models.Game, error) {
+	var game models.Game
+	err := row.Scan(
+		&game.ID,
+		&game.Title,
+		&game.Description,
+		&game.Platforms,
+		&game.ReleaseDate,
+		&game.Rating,
+		&game.CoverImage,
+		&game.ExternalID,
+		&game.ExternalSource,
+		&game.ExternalSlug,
+		&game.Website,
+		&game.Metacritic,
+		&game.Playtime,
+		&game.DetailsFetchedAt,
+	)
+	if err == pgx.ErrNoRows {
+		return game, models.ErrGameNotFound
+	}
+	return game, err
+}
+
+// GetGameByID returns a game with its details and taxonomies, or models.ErrGameNotFound.
+func (dao *GameDAO) GetGameByID(ctx context.Context, id string) (models.Game, error) {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+	query := `SELECT ` + gameDetailColumns + ` FROM games WHERE id = $1`
+	game, err := scanGameDetail(dao.connection.QueryRow(ctx, query, id))
 	if err != nil {
-		return nil, 0, err
+		return game, err
 	}
+	return game, dao.loadGameTaxonomies(ctx, &game)
+}
 
-	return games, total, nil
+// GetGameByExternalID returns the game imported from an external source, or models.ErrGameNotFound.
+func (dao *GameDAO) GetGameByExternalID(ctx context.Context, source, externalID string) (models.Game, error) {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+	query := `SELECT ` + gameDetailColumns + ` FROM games WHERE externalSource = $1 AND externalId = $2`
+	game, err := scanGameDetail(dao.connection.QueryRow(ctx, query, source, externalID))
+	if err != nil {
+		return game, err
+	}
+	return game, dao.loadGameTaxonomies(ctx, &game)
+}
+
+// SaveGameDetails stores an external game along with its details and taxonomies, inserting
+// it when it was never imported. The id of the stored row is written back into game. Games
+// already stored from another source, by their dedupe key, are not stored and
+// models.ErrDuplicateGame is returned, as UpsertManyGames skips them.
+func (dao *GameDAO) SaveGameDetails(ctx context.Context, game *models.Game) error {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+	query := `
+		INSERT INTO games (
+			title, description, platforms, releaseDate, rating, coverImage, externalId, externalSource,
+			externalSlug, website, metacritic, playtime, detailsFetchedAt, dedupeKey
+		)
+		SELECT $1::text, $2::text, $3::text[], $4::date, $5::int, $6::text, $7::text, $8::text,
+			$9::text, $10::text, $11::int, $12::int, $13::timestamp, NULLIF($14::text, '')
+		WHERE NOT EXISTS (
+			SELECT 1 FROM games
+			WHERE dedupeKey = NULLIF($14::text, '') AND externalSource <> $8::text
+		)
+		ON CONFLICT (externalSource, externalId) DO UPDATE
+		SET title = EXCLUDED.title,
+			description = EXCLUDED.description,
+			platforms = EXCLUDED.platforms,
+			releaseDate = EXCLUDED.releaseDate,
+			rating = EXCLUDED.rating,
+			coverImage = EXCLUDED.coverImage,
+			externalSlug = EXCLUDED.externalSlug,
+			website = EXCLUDED.website,
+			metacritic = EXCLUDED.metacritic,
+			playtime = EXCLUDED.playtime,
+			detailsFetchedAt = EXCLUDED.detailsFetchedAt,
+			dedupeKey = EXCLUDED.dedupeKey,
+			updatedAt = CURRENT_TIMESTAMP
+		RETURNING id`
+	return database.InTx(ctx, dao.connection, func(tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, query, game.Title, game.Description, game.Platforms, game.ReleaseDate,
+			game.Rating, game.CoverImage, game.ExternalID, game.ExternalSource, game.ExternalSlug, game.Website,
+			game.Metacritic, game.Playtime, game.DetailsFetchedAt, game.DedupeKey()).Scan(&game.ID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.ErrDuplicateGame
+		}
+		if err != nil {
+			return err
+		}
+		return saveGamesTaxonomies(ctx, tx, []models.Game{*game})
+	})
+}
+
+// GetSearchIngestion returns the import progress of a search term for an external source.
+func (dao *GameDAO) GetSearchIngestion(ctx context.Context, term, source string) (models.SearchIngestion, error) {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+	ingestion := models.SearchIngestion{Term: term, ExternalSource: source}
+	query := `
+		SELECT page, remoteCount, hasNext FROM search_ingestions
+		WHERE term = $1 AND externalSource = $2
+		ORDER BY page DESC
+		LIMIT 1`
+	err := dao.connection.QueryRow(ctx, query, term, source).Scan(&ingestion.LastPage, &ingestion.RemoteCount, &ingestion.HasNext)
+	if err != nil && err != pgx.ErrNoRows {
+		return ingestion, err
+	}
+	return ingestion, nil
+}
+
+// RecordSearchIngestion marks a result page of a search term as imported.
+func (dao *GameDAO) RecordSearchIngestion(ctx context.Context, ingestion models.SearchIngestion) error {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+	query := `
+		INSERT INTO search_ingestions (term, externalSource, page, remoteCount, hasNext)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (term, externalSource, page) DO UPDATE
+		SET remoteCount = EXCLUDED.remoteCount, hasNext = EXCLUDED.hasNext, ingestedAt = CURRENT_TIMESTAMP`
+	_, err := dao.connection.Exec(ctx, query, ingestion.Term, ingestion.ExternalSource, ingestion.LastPage, ingestion.RemoteCount, ingestion.HasNext)
+	return err
 }
@@ -1,15 +1,90 @@
 package models
 
-import "time"
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/melkdesousa/gamgo/utils"
+)
+
+// ErrGameNotFound is returned when no game matches the requested id.
+var ErrGameNotFound = errors.New("game not found")
+
+// ErrDuplicateGame is returned when storing a game already stored from another source.
+var ErrDuplicateGame = errors.New("game already stored from another source")
 
 type Game struct {
-	ID    string `json:"id"`
-	Title string `json:"title"`
-	// Description    string    `json:"description,omitempty"`
+	ID             string    `json:"id"`
+	Title          string    `json:"title"`
+	Description    string    `json:"description,omitempty"`
 	ReleaseDate    time.Time `json:"releaseDate,omitempty"`
 	Platforms      []string  `json:"platforms"`
 	Rating         int       `json:"rating"`
 	ExternalID     string    `json:"externalId"`
 	ExternalSource string    `json:"externalSource"`
 	CoverImage     string    `json:"coverImage"`
+	// ExternalSlug is the human readable identifier of the game in its external source
+	ExternalSlug string `json:"externalSlug,omitempty"`
+	// Rank is the search relevance of the game, only set by searches
+	Rank float32 `json:"-"`
+	// Snippet holds the matched text with terms wrapped in <mark> tags, only set by searches
+	Snippet string `json:"snippet,omitempty"`
+	// The following details are only loaded for a single game
+	Website    string `json:"website,omitempty"`
+	Metacritic int    `json:"metacritic,omitempty"`
+	Playtime   int    `json:"playtime,omitempty"` // average hours
+	// DetailsFetchedAt is when the details were last fetched from the external source, nil if never
+	DetailsFetchedAt *time.Time `json:"detailsFetchedAt,omitempty"`
+	// Taxonomies of the game. A nil slice means the source did not tell and is left
+	// untouched when storing the game, while an empty one clears the stored entries.
+	Genres     []Taxonomy `json:"genres,omitempty"`
+	Tags       []Taxonomy `json:"tags,omitempty"`
+	Developers []Taxonomy `json:"developers,omitempty"`
+	Publishers []Taxonomy `json:"publishers,omitempty"`
+}
+
+// Taxonomies returns the entries of a kind of taxonomy of the game.
+func (g Game) Taxonomies(kind TaxonomyKind) []Taxonomy {
+	switch kind {
+	case TaxonomyGenre:
+		return g.Genres
+	case TaxonomyTag:
+		return g.Tags
+	case TaxonomyDeveloper:
+		return g.Developers
+	case TaxonomyPublisher:
+		return g.Publishers
+	}
+	return nil
+}
+
+// SetTaxonomies replaces the entries of a kind of taxonomy of the game.
+func (g *Game) SetTaxonomies(kind TaxonomyKind, items []Taxonomy) {
+	switch kind {
+	case TaxonomyGenre:
+		g.Genres = items
+	case TaxonomyTag:
+		g.Tags = items
+	case TaxonomyDeveloper:
+		g.Developers = items
+	case TaxonomyPublisher:
+		g.Publishers = items
+	}
+}
+
+// DetailsStale reports whether the details of the game were never fetched or are older than ttl.
+func (g Game) DetailsStale(ttl time.Duration, now time.Time) bool {
+	return g.DetailsFetchedAt == nil || now.Sub(*g.DetailsFetchedAt) > ttl
+}
+
+// DedupeKey identifies the same game across external sources by its normalized title and
+// release year. It is empty when the release date is unknown, as the title alone is not
+// enough to tell games apart.
+func (g Game) DedupeKey() string {
+	title := utils.NormalizeTitle(g.Title)
+	if g.ReleaseDate.IsZero() || title == "" {
+		return ""
+	}
+	return title + ":" + strconv.Itoa(g.ReleaseDate.Year())
 }
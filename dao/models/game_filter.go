@@ -0,0 +1,50 @@
+package models
+
+import "time"
+
+type PlatformMatch string
+
+const (
+	PlatformMatchAny PlatformMatch = "any"
+	PlatformMatchAll PlatformMatch = "all"
+)
+
+// GameFilter narrows game listings. Every non-empty field must hold for a game
+// to match, empty fields do not filter at all.
+type GameFilter struct {
+	// Title is a search expression, see the dao/search package
+	Title     string
+	Platforms []string
+	// PlatformMatch tells whether games must run on any or on all of Platforms
+	PlatformMatch  PlatformMatch
+	ReleasedFrom   *time.Time
+	ReleasedTo     *time.Time
+	MinRating      *float64
+	MaxRating      *float64
+	ExternalSource string
+	// Slugs of taxonomies, games must have any of the slugs of each kind
+	Genres     []string
+	Tags       []string
+	Developers []string
+	Publishers []string
+}
+
+// TaxonomySlugs returns the slugs the filter requires for a kind of taxonomy.
+func (f GameFilter) TaxonomySlugs(kind TaxonomyKind) []string {
+	switch kind {
+	case TaxonomyGenre:
+		return f.Genres
+	case TaxonomyTag:
+		return f.Tags
+	case TaxonomyDeveloper:
+		return f.Developers
+	case TaxonomyPublisher:
+		return f.Publishers
+	}
+	return nil
+}
+
+// HasTitle reports whether the filter carries a search expression.
+func (f GameFilter) HasTitle() bool {
+	return f.Title != ""
+}
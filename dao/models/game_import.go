@@ -0,0 +1,10 @@
+package models
+
+// ImportResult counts what a bulk import did with the games it received.
+type ImportResult struct {
+	Inserted int `json:"inserted"`
+	Updated  int `json:"updated"`
+	// Skipped counts games already stored with the same fields or from another source, and
+	// repeated games of the batch
+	Skipped int `json:"skipped"`
+}
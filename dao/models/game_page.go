@@ -0,0 +1,44 @@
+package models
+
+import "errors"
+
+const (
+	DefaultPageSize = 10
+	MaxPageSize     = 50
+)
+
+// ErrInvalidCursor is returned when a pagination cursor is malformed or was
+// issued for a different filter or sort.
+var ErrInvalidCursor = errors.New("invalid pagination cursor")
+
+// PageRequest selects a page either by number or, when Cursor is set, by the
+// opaque next/prev cursor returned with a previous page.
+type PageRequest struct {
+	Number int
+	Limit  int
+	Cursor string
+}
+
+// NewPageRequest builds a page request, falling back to the first page and the
+// default size and capping the size to MaxPageSize.
+func NewPageRequest(number, limit int, cursor string) PageRequest {
+	if number < 1 {
+		number = 1
+	}
+	if limit < 1 {
+		limit = DefaultPageSize
+	}
+	if limit > MaxPageSize {
+		limit = MaxPageSize
+	}
+	return PageRequest{Number: number, Limit: limit, Cursor: cursor}
+}
+
+// GamePage is a page of games along with the total number of matches and the
+// cursors of the adjacent pages, empty when there is no such page.
+type GamePage struct {
+	Games []Game `json:"games"`
+	Total int    `json:"total"`
+	Next  string `json:"next,omitempty"`
+	Prev  string `json:"prev,omitempty"`
+}
@@ -0,0 +1,58 @@
+package models
+
+import "fmt"
+
+type SortField string
+
+const (
+	SortRelevance   SortField = "relevance"
+	SortRating      SortField = "rating"
+	SortReleaseDate SortField = "releaseDate"
+	SortTitle       SortField = "title"
+)
+
+// GameSort is the ordering requested for game listings and searches.
+type GameSort struct {
+	Field SortField `json:"sort"`
+	Desc  bool      `json:"-"`
+}
+
+// ParseGameSort validates the sort and order query parameters. An empty field
+// defaults to relevance when a search term is present and to title otherwise;
+// an empty order defaults to the natural direction of the field.
+func ParseGameSort(field, order string, hasTerm bool) (GameSort, error) {
+	sort := GameSort{Field: SortField(field)}
+	switch sort.Field {
+	case "":
+		sort.Field = SortTitle
+		if hasTerm {
+			sort.Field = SortRelevance
+		}
+	case SortRelevance, SortRating, SortReleaseDate, SortTitle:
+	default:
+		return GameSort{}, fmt.Errorf("invalid sort %q, expected one of relevance, rating, releaseDate or title", field)
+	}
+	switch order {
+	case "":
+		sort.Desc = sort.Field != SortTitle
+	case "asc":
+		sort.Desc = false
+	case "desc":
+		sort.Desc = true
+	default:
+		return GameSort{}, fmt.Errorf("invalid order %q, expected asc or desc", order)
+	}
+	return sort, nil
+}
+
+// Order returns "asc" or "desc".
+func (s GameSort) Order() string {
+	if s.Desc {
+		return "desc"
+	}
+	return "asc"
+}
+
+func (s GameSort) String() string {
+	return string(s.Field) + ":" + s.Order()
+}
@@ -0,0 +1,16 @@
+package models
+
+// Platform is a gaming platform or, when IsParent is set, a family of platforms.
+type Platform struct {
+	ID       string `json:"id"`
+	Slug     string `json:"slug"`
+	Name     string `json:"name"`
+	IsParent bool   `json:"isParent"`
+	// ParentID is the id of the family of the platform, empty when it has none
+	ParentID string `json:"parentId,omitempty"`
+	// ParentSlug identifies the family of the platform when syncing, empty when it has none
+	ParentSlug string `json:"-"`
+	// GamesCount is the number of stored games running on the platform or any of its children
+	GamesCount int        `json:"gamesCount"`
+	Children   []Platform `json:"children,omitempty"`
+}
@@ -0,0 +1,17 @@
+package models
+
+// SearchIngestion tracks how many result pages of an external source have been
+// imported for a search term.
+type SearchIngestion struct {
+	Term           string
+	ExternalSource string
+	// LastPage is the last imported page, 0 when the term was never imported
+	LastPage    int
+	RemoteCount int
+	HasNext     bool
+}
+
+// Started reports whether at least one page was imported.
+func (i SearchIngestion) Started() bool {
+	return i.LastPage > 0
+}
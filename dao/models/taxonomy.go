@@ -0,0 +1,28 @@
+package models
+
+// TaxonomyKind names a way of classifying games. Games can have several entries of each kind.
+type TaxonomyKind string
+
+const (
+	TaxonomyGenre     TaxonomyKind = "genres"
+	TaxonomyTag       TaxonomyKind = "tags"
+	TaxonomyDeveloper TaxonomyKind = "developers"
+	TaxonomyPublisher TaxonomyKind = "publishers"
+)
+
+// TaxonomyKinds lists every kind of taxonomy.
+var TaxonomyKinds = []TaxonomyKind{TaxonomyGenre, TaxonomyTag, TaxonomyDeveloper, TaxonomyPublisher}
+
+// Taxonomy is a genre, tag, developer or publisher, identified by its slug.
+type Taxonomy struct {
+	Slug string `json:"slug"`
+	Name string `json:"name"`
+	// GamesCount is the number of stored games classified under it, only set by listings
+	GamesCount int `json:"gamesCount,omitempty"`
+}
+
+// TaxonomyPage is a page of a taxonomy listing along with the total number of entries.
+type TaxonomyPage struct {
+	Items []Taxonomy `json:"items"`
+	Total int        `json:"total"`
+}
@@ -0,0 +1,79 @@
+package dao
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/melkdesousa/gamgo/dao/models"
+	"github.com/melkdesousa/gamgo/database"
+)
+
+type PlatformDAO struct {
+	connection database.Querier
+}
+
+func NewPlatformDAO(connection database.Querier) *PlatformDAO {
+	return &PlatformDAO{
+		connection: connection,
+	}
+}
+
+// WithTx returns a copy of the DAO bound to the given transaction.
+func (dao *PlatformDAO) WithTx(tx pgx.Tx) *PlatformDAO {
+	return &PlatformDAO{connection: tx}
+}
+
+const upsertPlatformQuery = `
+	INSERT INTO platforms (slug, name, isParent, parentId)
+	VALUES ($1, $2, $3, (SELECT id FROM platforms WHERE slug = $4 AND isParent))
+	ON CONFLICT (slug, isParent) DO UPDATE
+	SET name = EXCLUDED.name, parentId = EXCLUDED.parentId`
+
+// UpsertPlatforms stores synced platforms by slug. Parents are stored before their
+// children, which reference them through ParentSlug.
+func (dao *PlatformDAO) UpsertPlatforms(ctx context.Context, platforms []models.Platform) error {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+	return database.InTx(ctx, dao.connection, func(tx pgx.Tx) error {
+		batch := &pgx.Batch{}
+		for _, parents := range []bool{true, false} {
+			for _, platform := range platforms {
+				if platform.IsParent == parents {
+					batch.Queue(upsertPlatformQuery, platform.Slug, platform.Name, platform.IsParent, platform.ParentSlug)
+				}
+			}
+		}
+		return tx.SendBatch(ctx, batch).Close()
+	})
+}
+
+// ListPlatforms returns every platform and parent with their number of games. A parent
+// counts the games running on any of its children.
+func (dao *PlatformDAO) ListPlatforms(ctx context.Context) ([]models.Platform, error) {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+	query := `
+		SELECT platforms.id, platforms.slug, platforms.name, platforms.isParent, COALESCE(platforms.parentId::text, ''),
+			CASE WHEN platforms.isParent THEN (
+				SELECT COUNT(*) FROM games
+				WHERE games.platforms && ARRAY(SELECT child.name FROM platforms AS child WHERE child.parentId = platforms.id)
+			) ELSE (
+				SELECT COUNT(*) FROM games WHERE games.platforms @> ARRAY[platforms.name]
+			) END
+		FROM platforms
+		ORDER BY platforms.isParent DESC, platforms.name`
+	rows, err := dao.connection.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	platforms := []models.Platform{}
+	for rows.Next() {
+		var platform models.Platform
+		if err := rows.Scan(&platform.ID, &platform.Slug, &platform.Name, &platform.IsParent, &platform.ParentID, &platform.GamesCount); err != nil {
+			return nil, err
+		}
+		platforms = append(platforms, platform)
+	}
+	return platforms, rows.Err()
+}
@@ -0,0 +1,106 @@
+package dao
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/melkdesousa/gamgo/database"
+)
+
+// decayedHits returns the expression of the hits of the popular requests of table decayed
+// to the current time, by the half-life in seconds given by the halfLife parameter.
+func decayedHits(table, halfLife string) string {
+	return fmt.Sprintf(`(%[1]s.hits * power(0.5, extract(epoch FROM CURRENT_TIMESTAMP - %[1]s.lastRequestedAt) / %[2]s))`, table, halfLife)
+}
+
+// PopularityDAO counts requests in the database, shared by every instance and kept across
+// flushes of the cache, to find the most popular ones. Hits decay with a half-life, so the
+// requests popular now outrank those that were popular once.
+type PopularityDAO struct {
+	connection database.Querier
+	// maxRequests is the number of requests kept by kind, the least popular being dropped
+	// beyond twice as many
+	maxRequests int
+	halfLife    time.Duration
+}
+
+// NewPopularityDAO creates a popularity counter keeping up to maxRequests requests of each
+// kind, whose hits are halved every halfLife.
+func NewPopularityDAO(connection database.Querier, maxRequests int, halfLife time.Duration) *PopularityDAO {
+	return &PopularityDAO{connection: connection, maxRequests: maxRequests, halfLife: max(halfLife, time.Minute)}
+}
+
+// Add counts the hits of requests in the set of their kind at once, decaying the hits
+// counted before.
+func (dao *PopularityDAO) Add(ctx context.Context, set string, hits map[string]int) error {
+	if len(hits) == 0 {
+		return nil
+	}
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+	requests := make([]string, 0, len(hits))
+	counts := make([]float64, 0, len(hits))
+	for request, count := range hits {
+		requests = append(requests, request)
+		counts = append(counts, float64(count))
+	}
+	query := fmt.Sprintf(`
+		INSERT INTO popular_requests (kind, request, hits)
+		SELECT $1, batch.request, batch.hits
+		FROM unnest($2::text[], $3::float8[]) AS batch(request, hits)
+		ON CONFLICT (kind, request) DO UPDATE
+		SET hits = %s + EXCLUDED.hits, lastRequestedAt = CURRENT_TIMESTAMP`,
+		decayedHits("popular_requests", "$4"))
+	_, err := dao.connection.Exec(ctx, query, set, requests, counts, dao.halfLife.Seconds())
+	if err != nil || dao.maxRequests <= 0 {
+		return err
+	}
+	// the set grows up to twice its bound before being trimmed, so new requests have room
+	// to climb instead of being dropped right away
+	var size int
+	if err := dao.connection.QueryRow(ctx, `SELECT COUNT(*) FROM popular_requests WHERE kind = $1`, set).Scan(&size); err != nil || size <= 2*dao.maxRequests {
+		return err
+	}
+	trim := fmt.Sprintf(`
+		DELETE FROM popular_requests
+		WHERE kind = $1 AND request IN (
+			SELECT request FROM popular_requests
+			WHERE kind = $1
+			ORDER BY %s DESC, request
+			OFFSET $2
+		)`, decayedHits("popular_requests", "$3"))
+	if _, err := dao.connection.Exec(ctx, trim, set, dao.maxRequests, dao.halfLife.Seconds()); err != nil {
+		log.Printf("Error trimming popular requests of %s: %v", set, err)
+	}
+	return nil
+}
+
+// Top returns up to n requests of set, the most popular now first.
+func (dao *PopularityDAO) Top(ctx context.Context, set string, n int) ([]string, error) {
+	if n <= 0 {
+		return nil, nil
+	}
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+	query := fmt.Sprintf(`
+		SELECT request FROM popular_requests
+		WHERE kind = $1
+		ORDER BY %s DESC, request
+		LIMIT $2`, decayedHits("popular_requests", "$3"))
+	rows, err := dao.connection.Query(ctx, query, set, n, dao.halfLife.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	requests := []string{}
+	for rows.Next() {
+		var request string
+		if err := rows.Scan(&request); err != nil {
+			return nil, err
+		}
+		requests = append(requests, request)
+	}
+	return requests, rows.Err()
+}
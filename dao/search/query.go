@@ -0,0 +1,316 @@
+// Package search parses user supplied game search expressions and compiles them
+// into PostgreSQL tsquery text that is safe to bind as a to_tsquery parameter.
+//
+// Supported syntax:
+//
+//	super mario       both terms must match
+//	"super mario"     phrase, terms must be adjacent
+//	zelda OR metroid  either side may match (| is accepted as well)
+//	-dlc              term must not match
+//	zeld*             prefix match
+package search
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// maxTerms bounds the size of the compiled query.
+const maxTerms = 32
+
+// SyntaxError describes malformed search input.
+type SyntaxError struct {
+	Input    string
+	Position int
+	Message  string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("invalid search query at position %d: %s", e.Position, e.Message)
+}
+
+// Term is a single word or quoted phrase of a query.
+type Term struct {
+	Words   []string
+	Phrase  bool
+	Prefix  bool
+	Negated bool
+}
+
+// Query is a parsed search expression in disjunctive form: the query matches
+// when every term of at least one group matches.
+type Query struct {
+	Groups [][]Term
+}
+
+// Parse parses a search expression.
+func Parse(input string) (*Query, error) {
+	tokens, err := tokenize(input)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, &SyntaxError{Input: input, Message: "query is empty"}
+	}
+	query := &Query{}
+	group := []Term{}
+	terms := 0
+	for i, tok := range tokens {
+		if tok.or {
+			if len(group) == 0 || i == len(tokens)-1 {
+				return nil, &SyntaxError{Input: input, Position: tok.pos, Message: "OR must be placed between two terms"}
+			}
+			query.Groups = append(query.Groups, group)
+			group = []Term{}
+			continue
+		}
+		terms++
+		group = append(group, tok.term)
+	}
+	query.Groups = append(query.Groups, group)
+	if terms > maxTerms {
+		return nil, &SyntaxError{Input: input, Message: fmt.Sprintf("query has more than %d terms", maxTerms)}
+	}
+	for _, group := range query.Groups {
+		if !hasPositive(group) {
+			return nil, &SyntaxError{Input: input, Message: "query needs at least one term that is not excluded"}
+		}
+	}
+	return query, nil
+}
+
+// TSQuery renders the query in to_tsquery syntax. Every lexeme is quoted, so the
+// result can be passed to to_tsquery('english', $1) without further escaping.
+func (q *Query) TSQuery() string {
+	groups := make([]string, len(q.Groups))
+	for i, group := range q.Groups {
+		parts := make([]string, len(group))
+		for j, term := range group {
+			parts[j] = term.tsquery()
+		}
+		groups[i] = strings.Join(parts, " & ")
+		if len(q.Groups) > 1 && len(group) > 1 {
+			groups[i] = "(" + groups[i] + ")"
+		}
+	}
+	return strings.Join(groups, " | ")
+}
+
+// Plain returns the positive words of the query separated by spaces, suitable
+// for external APIs that only understand free text.
+func (q *Query) Plain() string {
+	words := []string{}
+	for _, group := range q.Groups {
+		for _, term := range group {
+			if !term.Negated {
+				words = append(words, term.Words...)
+			}
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// String returns the canonical form of the query, equal for equivalent inputs
+// such as "Super  Mario" and "super mario". It is used to build cache keys.
+func (q *Query) String() string {
+	groups := make([]string, len(q.Groups))
+	for i, group := range q.Groups {
+		parts := make([]string, len(group))
+		for j, term := range group {
+			parts[j] = term.String()
+		}
+		groups[i] = strings.Join(parts, " ")
+	}
+	return strings.Join(groups, " OR ")
+}
+
+// wordPrefixLength is the length of the word prefixes relating queries to the titles they
+// match, short enough for most words sharing an English stem to share it.
+const wordPrefixLength = 3
+
+// TitlePrefixes returns, for each group of the query, the prefix of a word that the titles
+// matched by the group contain, as returned by WordPrefixes. ok is false when a group only
+// has prefix terms shorter than the prefixes, which titles may match by any word.
+func (q *Query) TitlePrefixes() (prefixes []string, ok bool) {
+	for _, group := range q.Groups {
+		prefix := ""
+		for _, term := range group {
+			if term.Negated {
+				continue
+			}
+			for i, word := range term.Words {
+				if term.Prefix && i == len(term.Words)-1 && len([]rune(word)) < wordPrefixLength {
+					continue
+				}
+				prefix = wordPrefix(word)
+				break
+			}
+			if prefix != "" {
+				break
+			}
+		}
+		if prefix == "" {
+			return nil, false
+		}
+		prefixes = append(prefixes, prefix)
+	}
+	return prefixes, true
+}
+
+// WordPrefixes returns the distinct prefixes of the words of a title. They include a prefix
+// returned by TitlePrefixes for every query matching the title, unless stemming changes
+// the first letters of a word.
+func WordPrefixes(title string) []string {
+	prefixes := []string{}
+	for _, word := range splitWords([]rune(title)) {
+		if prefix := wordPrefix(word); !slices.Contains(prefixes, prefix) {
+			prefixes = append(prefixes, prefix)
+		}
+	}
+	return prefixes
+}
+
+func wordPrefix(word string) string {
+	runes := []rune(word)
+	return string(runes[:min(len(runes), wordPrefixLength)])
+}
+
+func (t Term) String() string {
+	var b strings.Builder
+	if t.Negated {
+		b.WriteByte('-')
+	}
+	if t.Phrase {
+		b.WriteString(`"` + strings.Join(t.Words, " ") + `"`)
+	} else {
+		b.WriteString(t.Words[0])
+	}
+	if t.Prefix {
+		b.WriteByte('*')
+	}
+	return b.String()
+}
+
+func (t Term) tsquery() string {
+	lexemes := make([]string, len(t.Words))
+	for i, word := range t.Words {
+		lexemes[i] = quote(word)
+	}
+	if t.Prefix {
+		lexemes[len(lexemes)-1] += ":*"
+	}
+	expr := strings.Join(lexemes, " <-> ")
+	if len(lexemes) > 1 {
+		expr = "(" + expr + ")"
+	}
+	if t.Negated {
+		expr = "!" + expr
+	}
+	return expr
+}
+
+// quote wraps a word as a tsquery lexeme. Words only hold letters and digits,
+// quotes and backslashes are escaped anyway to keep the output safe.
+func quote(word string) string {
+	word = strings.ReplaceAll(word, `\`, `\\`)
+	word = strings.ReplaceAll(word, `'`, `''`)
+	return "'" + word + "'"
+}
+
+func hasPositive(group []Term) bool {
+	for _, term := range group {
+		if !term.Negated {
+			return true
+		}
+	}
+	return false
+}
+
+type token struct {
+	term Term
+	or   bool
+	pos  int
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// tokenize splits the input into terms and OR operators. Characters that are
+// neither part of a word nor an operator act as separators.
+func tokenize(input string) ([]token, error) {
+	runes := []rune(input)
+	tokens := []token{}
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case r == '|':
+			tokens = append(tokens, token{or: true, pos: i})
+			i++
+		case r == '"' || (r == '-' && i+1 < len(runes) && runes[i+1] == '"'):
+			start := i
+			negated := r == '-'
+			if negated {
+				i++
+			}
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' {
+				end++
+			}
+			if end == len(runes) {
+				return nil, &SyntaxError{Input: input, Position: start, Message: "unterminated quoted phrase"}
+			}
+			words := splitWords(runes[i+1 : end])
+			i = end + 1
+			prefix := i < len(runes) && runes[i] == '*'
+			if prefix {
+				i++
+			}
+			if len(words) == 0 {
+				continue
+			}
+			tokens = append(tokens, token{
+				term: Term{Words: words, Phrase: len(words) > 1, Prefix: prefix, Negated: negated},
+				pos:  start,
+			})
+		case isWordRune(r) || (r == '-' && i+1 < len(runes) && isWordRune(runes[i+1])):
+			start := i
+			negated := r == '-'
+			if negated {
+				i++
+			}
+			// hyphenated words such as "spider-man" are searched as a phrase
+			end := i
+			for end < len(runes) && (isWordRune(runes[end]) ||
+				(runes[end] == '-' && end+1 < len(runes) && isWordRune(runes[end+1]))) {
+				end++
+			}
+			text := string(runes[i:end])
+			i = end
+			prefix := i < len(runes) && runes[i] == '*'
+			if prefix {
+				i++
+			}
+			if !negated && text == "OR" {
+				tokens = append(tokens, token{or: true, pos: start})
+				continue
+			}
+			words := splitWords(runes[start:end])
+			tokens = append(tokens, token{
+				term: Term{Words: words, Phrase: len(words) > 1, Prefix: prefix, Negated: negated},
+				pos:  start,
+			})
+		default:
+			i++
+		}
+	}
+	return tokens, nil
+}
+
+func splitWords(runes []rune) []string {
+	return strings.FieldsFunc(strings.ToLower(string(runes)), func(r rune) bool {
+		return !isWordRune(r)
+	})
+}
@@ -0,0 +1,95 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		name      string
+		input     string
+		tsquery   string
+		canonical string
+		plain     string
+	}{
+		{"SingleWord", "zelda", "'zelda'", "zelda", "zelda"},
+		{"MultipleWords", "Super  Mario", "'super' & 'mario'", "super mario", "super mario"},
+		{"Phrase", `"super mario" galaxy`, "('super' <-> 'mario') & 'galaxy'", `"super mario" galaxy`, "super mario galaxy"},
+		{"Or", "zelda OR metroid", "'zelda' | 'metroid'", "zelda OR metroid", "zelda metroid"},
+		{"PipeOr", "mario kart | zelda", "('mario' & 'kart') | 'zelda'", "mario kart OR zelda", "mario kart zelda"},
+		{"Negation", "zelda -dlc", "'zelda' & !'dlc'", "zelda -dlc", "zelda"},
+		{"NegatedPhrase", `mario -"party games"`, "'mario' & !('party' <-> 'games')", `mario -"party games"`, "mario"},
+		{"Prefix", "zeld*", "'zeld':*", "zeld*", "zeld"},
+		{"LowercaseOrIsAWord", "zelda or metroid", "'zelda' & 'or' & 'metroid'", "zelda or metroid", "zelda or metroid"},
+		{"StripsOperators", `mario's & kart:!`, "'mario' & 's' & 'kart'", "mario s kart", "mario s kart"},
+		{"HyphenatedWord", "spider-man -demo", "('spider' <-> 'man') & !'demo'", `"spider man" -demo`, "spider man"},
+		{"Unicode", "pokémon", "'pokémon'", "pokémon", "pokémon"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			query, err := Parse(tc.input)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.tsquery, query.TSQuery())
+			assert.Equal(t, tc.canonical, query.String())
+			assert.Equal(t, tc.plain, query.Plain())
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	testCases := []struct {
+		name  string
+		input string
+	}{
+		{"Empty", ""},
+		{"OnlySymbols", "&|!:"},
+		{"UnterminatedPhrase", `"super mario`},
+		{"LeadingOr", "OR zelda"},
+		{"TrailingOr", "zelda OR"},
+		{"DoubleOr", "zelda OR OR metroid"},
+		{"OnlyNegations", "-dlc -demo"},
+		{"NegatedGroup", "zelda OR -dlc"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			query, err := Parse(tc.input)
+			assert.Nil(t, query)
+			var syntaxErr *SyntaxError
+			assert.ErrorAs(t, err, &syntaxErr)
+		})
+	}
+}
+
+func TestTitlePrefixes(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    string
+		prefixes []string
+		ok       bool
+	}{
+		{"SingleWord", "zelda", []string{"zel"}, true},
+		{"ShortWord", "go", []string{"go"}, true},
+		{"FirstPositiveTerm", "-dlc mario kart", []string{"mar"}, true},
+		{"OneByGroup", "zelda OR metroid", []string{"zel", "met"}, true},
+		{"Prefix", "zeld*", []string{"zel"}, true},
+		{"ShortPrefixSkipped", "z* mario", []string{"mar"}, true},
+		{"OnlyShortPrefixes", "zelda OR m*", nil, false},
+		{"Unicode", "pokémon", []string{"pok"}, true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			query, err := Parse(tc.input)
+			assert.NoError(t, err)
+			prefixes, ok := query.TitlePrefixes()
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.prefixes, prefixes)
+		})
+	}
+}
+
+func TestWordPrefixes(t *testing.T) {
+	assert.Equal(t, []string{"the", "leg", "of", "zel", "lin", "s"}, WordPrefixes("The Legend of Zelda: Link's Legacy"))
+	assert.Equal(t, []string{}, WordPrefixes("!!"))
+}
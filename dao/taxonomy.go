@@ -0,0 +1,178 @@
+package dao
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/melkdesousa/gamgo/dao/models"
+	"github.com/melkdesousa/gamgo/database"
+)
+
+// taxonomyTable describes the tables of a kind of taxonomy. Only these whitelisted
+// names are ever interpolated into queries.
+type taxonomyTable struct {
+	table     string
+	joinTable string
+	// joinColumn references the taxonomy from the join table
+	joinColumn string
+}
+
+var taxonomyTables = map[models.TaxonomyKind]taxonomyTable{
+	models.TaxonomyGenre:     {table: "genres", joinTable: "game_genres", joinColumn: "genreId"},
+	models.TaxonomyTag:       {table: "tags", joinTable: "game_tags", joinColumn: "tagId"},
+	models.TaxonomyDeveloper: {table: "developers", joinTable: "game_developers", joinColumn: "developerId"},
+	models.TaxonomyPublisher: {table: "publishers", joinTable: "game_publishers", joinColumn: "publisherId"},
+}
+
+func lookupTaxonomyTable(kind models.TaxonomyKind) (taxonomyTable, error) {
+	t, ok := taxonomyTables[kind]
+	if !ok {
+		return t, fmt.Errorf("unknown taxonomy %q", kind)
+	}
+	return t, nil
+}
+
+// hasAnyClause matches games classified under any of the slugs bound to placeholder.
+func (t taxonomyTable) hasAnyClause(placeholder string) string {
+	return fmt.Sprintf(
+		"EXISTS (SELECT 1 FROM %[1]s JOIN %[2]s ON %[2]s.id = %[1]s.%[3]s WHERE %[1]s.gameId = games.id AND %[2]s.slug = ANY(%[4]s::text[]))",
+		t.joinTable, t.table, t.joinColumn, placeholder)
+}
+
+// upsertQuery stores taxonomies given as parallel arrays of slugs ($1) and names ($2).
+func (t taxonomyTable) upsertQuery() string {
+	return fmt.Sprintf(`
+		INSERT INTO %[1]s (slug, name)
+		SELECT * FROM unnest($1::text[], $2::text[])
+		ON CONFLICT (slug) DO UPDATE SET name = EXCLUDED.name
+		WHERE %[1]s.name IS DISTINCT FROM EXCLUDED.name`, t.table)
+}
+
+// unlinkQuery removes the entries of a game ($1) whose slugs are not in $2.
+func (t taxonomyTable) unlinkQuery() string {
+	return fmt.Sprintf(`
+		DELETE FROM %[1]s USING %[2]s
+		WHERE %[2]s.id = %[1]s.%[3]s AND %[1]s.gameId = $1 AND NOT (%[2]s.slug = ANY($2::text[]))`,
+		t.joinTable, t.table, t.joinColumn)
+}
+
+// linkQuery classifies a game ($1) under the slugs in $2.
+func (t taxonomyTable) linkQuery() string {
+	return fmt.Sprintf(`
+		INSERT INTO %[1]s (gameId, %[3]s)
+		SELECT $1, id FROM %[2]s WHERE slug = ANY($2::text[])
+		ON CONFLICT DO NOTHING`, t.joinTable, t.table, t.joinColumn)
+}
+
+// uniqueTaxonomies splits taxonomies into slugs and names, dropping repeated slugs
+// so upserts never touch the same row twice.
+func uniqueTaxonomies(items []models.Taxonomy) (slugs, names []string) {
+	slugs, names = []string{}, []string{}
+	seen := make(map[string]bool, len(items))
+	for _, item := range items {
+		if item.Slug == "" || seen[item.Slug] {
+			continue
+		}
+		seen[item.Slug] = true
+		slugs = append(slugs, item.Slug)
+		names = append(names, item.Name)
+	}
+	return slugs, names
+}
+
+// queueGameTaxonomies queues the statements storing the taxonomies of a stored game.
+// Kinds the game has no information about (nil slices) are left untouched.
+func queueGameTaxonomies(batch *pgx.Batch, game models.Game) {
+	for _, kind := range models.TaxonomyKinds {
+		items := game.Taxonomies(kind)
+		if items == nil {
+			continue
+		}
+		t := taxonomyTables[kind]
+		slugs, names := uniqueTaxonomies(items)
+		batch.Queue(t.upsertQuery(), slugs, names)
+		batch.Queue(t.unlinkQuery(), game.ID, slugs)
+		batch.Queue(t.linkQuery(), game.ID, slugs)
+	}
+}
+
+// saveGamesTaxonomies stores the taxonomies of stored games in a single round trip.
+func saveGamesTaxonomies(ctx context.Context, tx pgx.Tx, games []models.Game) error {
+	batch := &pgx.Batch{}
+	for _, game := range games {
+		queueGameTaxonomies(batch, game)
+	}
+	if batch.Len() == 0 {
+		return nil
+	}
+	return tx.SendBatch(ctx, batch).Close()
+}
+
+// loadGameTaxonomies fills the taxonomies of a game with every stored entry.
+func (dao *GameDAO) loadGameTaxonomies(ctx context.Context, game *models.Game) error {
+	selects := make([]string, len(models.TaxonomyKinds))
+	for i, kind := range models.TaxonomyKinds {
+		t := taxonomyTables[kind]
+		selects[i] = fmt.Sprintf(
+			"SELECT '%[4]s', %[2]s.slug, %[2]s.name FROM %[1]s JOIN %[2]s ON %[2]s.id = %[1]s.%[3]s WHERE %[1]s.gameId = $1",
+			t.joinTable, t.table, t.joinColumn, kind)
+	}
+	query := strings.Join(selects, "\n\t\tUNION ALL ") + "\n\t\tORDER BY 1, 3"
+	rows, err := dao.connection.Query(ctx, query, game.ID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for _, kind := range models.TaxonomyKinds {
+		game.SetTaxonomies(kind, []models.Taxonomy{})
+	}
+	for rows.Next() {
+		var kind models.TaxonomyKind
+		var item models.Taxonomy
+		if err := rows.Scan(&kind, &item.Slug, &item.Name); err != nil {
+			return err
+		}
+		game.SetTaxonomies(kind, append(game.Taxonomies(kind), item))
+	}
+	return rows.Err()
+}
+
+// ListTaxonomies returns a page of the entries of a kind of taxonomy with their number of
+// games, the most used first.
+func (dao *GameDAO) ListTaxonomies(ctx context.Context, kind models.TaxonomyKind, page models.PageRequest) (models.TaxonomyPage, error) {
+	result := models.TaxonomyPage{Items: []models.Taxonomy{}}
+	t, err := lookupTaxonomyTable(kind)
+	if err != nil {
+		return result, err
+	}
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+	query := fmt.Sprintf(`
+		SELECT %[2]s.slug, %[2]s.name, COUNT(%[1]s.gameId)
+		FROM %[2]s LEFT JOIN %[1]s ON %[1]s.%[3]s = %[2]s.id
+		GROUP BY %[2]s.id
+		ORDER BY COUNT(%[1]s.gameId) DESC, %[2]s.name, %[2]s.id
+		LIMIT $1 OFFSET $2`, t.joinTable, t.table, t.joinColumn)
+	rows, err := dao.connection.Query(ctx, query, page.Limit, (page.Number-1)*page.Limit)
+	if err != nil {
+		return result, err
+	}
+	for rows.Next() {
+		var item models.Taxonomy
+		if err := rows.Scan(&item.Slug, &item.Name, &item.GamesCount); err != nil {
+			rows.Close()
+			return result, err
+		}
+		result.Items = append(result.Items, item)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return result, err
+	}
+	if err := dao.connection.QueryRow(ctx, "SELECT COUNT(*) FROM "+t.table).Scan(&result.Total); err != nil {
+		return result, err
+	}
+	return result, nil
+}
@@ -3,6 +3,10 @@ package database
 import "strings"
 
 const CACHE_SEARCH_GAME_KEY_PREFIX = "search:game"
+const CACHE_SEARCH_EMPTY_KEY_PREFIX = "search:empty"
+const CACHE_GAME_KEY_PREFIX = "game"
+const CACHE_CATALOG_KEY_PREFIX = "catalog"
+const CACHE_RAWG_QUOTA_KEY_PREFIX = "rawg:quota"
 
 func GetCacheKey(key ...string) string {
 	return strings.Join(key, ":")
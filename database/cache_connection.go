@@ -30,10 +30,13 @@ func GetCacheConnection() *redis.Client {
 	return cache
 }
 
-// startCacheHealthCheck runs a periodic health check on the Redis connection
+// startCacheHealthCheck runs a periodic health check on the Redis connection. The client is
+// kept as it is on failures: it redials on its own, and the cache wrappers, such as
+// ResilientCache and TieredCache, hold on to it.
 func startCacheHealthCheck() {
 	ticker := time.NewTicker(30 * time.Second)
 	defer ticker.Stop()
+	healthy := true
 	for range ticker.C {
 		if cache == nil {
 			continue
@@ -42,32 +45,11 @@ func startCacheHealthCheck() {
 		_, err := cache.Ping(ctx).Result()
 		cancel()
 		if err != nil {
-			// Log the error
 			log.Printf("Cache health check failed: %v", err)
-			// Try to reconnect
-			reconnectCache()
+		} else if !healthy {
+			log.Println("Cache is reachable again")
 		}
-	}
-}
-
-// reconnectCache attempts to reconnect to Redis
-func reconnectCache() {
-	if cache != nil {
-		// Close existing connection
-		_ = cache.Close()
-	}
-	cache = redis.NewClient(&redis.Options{
-		Addr:     config.MustGetEnv("CACHE_ADDR"),
-		Password: config.MustGetEnv("CACHE_PASSWORD"),
-		DB:       config.MustGetEnvAs[int]("CACHE_DB"),
-	})
-	// Test the new connection
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-	if _, err := cache.Ping(ctx).Result(); err != nil {
-		log.Printf("Failed to reconnect to cache: %v", err)
-	} else {
-		log.Println("Successfully reconnected to cache")
+		healthy = err == nil
 	}
 }
 
@@ -7,64 +7,70 @@ import (
 	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/melkdesousa/gamgo/config"
 )
 
 var (
-	db     *pgx.Conn
+	db     *pgxpool.Pool
 	dbOnce sync.Once
+
+	queryTimeout = 5 * time.Second
 )
 
-func GetDBConnection() *pgx.Conn {
+// Querier is the subset of the pgx API shared by pools, connections and transactions.
+// Repositories depend on it so they can run either on the pool or inside a transaction.
+type Querier interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Begin(ctx context.Context) (pgx.Tx, error)
+	CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error)
+}
+
+// GetDBConnection returns a singleton connection pool. The pool checks the health of
+// its connections on its own and replaces broken ones, so callers never reconnect.
+func GetDBConnection() *pgxpool.Pool {
 	dbOnce.Do(func() {
-		var err error
-		db, err = pgx.Connect(context.Background(), config.MustGetEnv("DB_STRING"))
+		poolConfig, err := pgxpool.ParseConfig(config.MustGetEnv("DB_STRING"))
 		if err != nil {
 			panic(err)
 		}
+		poolConfig.MaxConns = int32(config.GetEnvOrDefault("DB_POOL_MAX_CONNS", 10))
+		poolConfig.MinConns = int32(config.GetEnvOrDefault("DB_POOL_MIN_CONNS", 1))
+		poolConfig.MaxConnIdleTime = time.Duration(config.GetEnvOrDefault("DB_POOL_MAX_CONN_IDLE_SECONDS", 300)) * time.Second
+		poolConfig.HealthCheckPeriod = time.Duration(config.GetEnvOrDefault("DB_POOL_HEALTH_CHECK_SECONDS", 30)) * time.Second
+		queryTimeout = time.Duration(config.GetEnvOrDefault("DB_QUERY_TIMEOUT_SECONDS", 5)) * time.Second
 
-		// Start a goroutine to periodically check database health
-		go startHealthCheck()
-	})
-	return db
-}
-
-// startHealthCheck runs a periodic health check on the database connection
-func startHealthCheck() {
-	ticker := time.NewTicker(30 * time.Second)
-	defer ticker.Stop()
-
-	for range ticker.C {
-		if db == nil {
-			continue
+		db, err = pgxpool.NewWithConfig(context.Background(), poolConfig)
+		if err != nil {
+			panic(err)
 		}
-
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		err := db.Ping(ctx)
-		cancel()
-
-		if err != nil {
-			// Log the error
-			log.Printf("Database health check failed: %v", err)
-
-			// Try to reconnect
-			reconnectDB()
+		defer cancel()
+		if err := db.Ping(ctx); err != nil {
+			log.Printf("Warning: database is not reachable yet: %v", err)
 		}
-	}
+	})
+	return db
 }
 
-// reconnectDB attempts to reconnect to the database
-func reconnectDB() {
-	if db != nil {
-		// Close existing connection
-		_ = db.Close(context.Background())
-	}
+// WithQueryTimeout bounds a single query with the configured DB_QUERY_TIMEOUT_SECONDS.
+func WithQueryTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, queryTimeout)
+}
 
-	var err error
-	db, err = pgx.Connect(context.Background(), config.MustGetEnv("DB_STRING"))
+// InTx runs fn inside a transaction started on q, committing when fn succeeds
+// and rolling back otherwise. When q is already a transaction a savepoint is used.
+func InTx(ctx context.Context, q Querier, fn func(tx pgx.Tx) error) error {
+	tx, err := q.Begin(ctx)
 	if err != nil {
-		log.Printf("Failed to reconnect to database: %v", err)
-	} else {
-		log.Println("Successfully reconnected to database")
+		return err
+	}
+	defer tx.Rollback(ctx)
+	if err := fn(tx); err != nil {
+		return err
 	}
+	return tx.Commit(ctx)
 }
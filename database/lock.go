@@ -0,0 +1,49 @@
+package database
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const CACHE_LOCK_KEY_PREFIX = "lock"
+
+// unlockScript deletes a lock only while it is held by the token, so a holder whose lock
+// expired does not release the lock of the next one.
+var unlockScript = redis.NewScript(`
+if redis.call("get", KEYS[1]) == ARGV[1] then
+	return redis.call("del", KEYS[1])
+end
+return 0
+`)
+
+// RedisLocker holds locks in Redis, shared by every instance using the same server.
+type RedisLocker struct {
+	client redis.Cmdable
+}
+
+// NewRedisLocker creates a locker keeping its locks in client.
+func NewRedisLocker(client redis.Cmdable) *RedisLocker {
+	return &RedisLocker{client: client}
+}
+
+// TryLock acquires the lock of key for ttl unless another holder has it. The lock expires
+// after ttl even if it is never released, such as when its holder crashes.
+func (l *RedisLocker) TryLock(ctx context.Context, key string, ttl time.Duration) (func(), bool, error) {
+	token := uuid.NewString()
+	locked, err := l.client.SetNX(ctx, key, token, ttl).Result()
+	if err != nil || !locked {
+		return nil, false, err
+	}
+	return func() {
+		// released even when the context of the holder is done
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := unlockScript.Run(ctx, l.client, []string{key}, token).Err(); err != nil {
+			log.Printf("Error releasing lock %s: %v", key, err)
+		}
+	}, true, nil
+}
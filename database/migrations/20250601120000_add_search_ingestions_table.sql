@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+-- remote result pages already imported for a search term
+CREATE TABLE IF NOT EXISTS search_ingestions (
+    term TEXT NOT NULL,
+    externalSource VARCHAR(255) NOT NULL,
+    page INTEGER NOT NULL,
+    remoteCount INTEGER NOT NULL,
+    hasNext BOOLEAN NOT NULL,
+    ingestedAt TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (term, externalSource, page)
+);
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS search_ingestions;
+-- +goose StatementEnd
@@ -0,0 +1,62 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE games
+ADD COLUMN IF NOT EXISTS externalSlug VARCHAR(255) DEFAULT NULL,
+ADD COLUMN IF NOT EXISTS updatedAt TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP;
+-- games imported so far stored the RAWG slug as their source
+UPDATE games
+SET externalSlug = externalSource,
+    externalSource = 'rawg'
+WHERE externalId IS NOT NULL
+    AND externalSource IS DISTINCT FROM 'rawg';
+-- merge duplicated imports into one row per game. The rows carry no write time, so the
+-- row with the lowest id is kept, its missing fields filled from its duplicates
+WITH imported AS (
+    SELECT id,
+        row_number() OVER (
+            imports
+            ORDER BY id
+        ) AS position,
+        max(description) OVER imports AS description,
+        max(externalSlug) OVER imports AS externalSlug,
+        max(NULLIF(coverImage, '')) OVER imports AS coverImage
+    FROM games
+    WHERE externalId IS NOT NULL
+    WINDOW imports AS (PARTITION BY externalSource, externalId)
+)
+UPDATE games
+SET description = COALESCE(games.description, imported.description),
+    externalSlug = COALESCE(games.externalSlug, imported.externalSlug),
+    coverImage = COALESCE(NULLIF(games.coverImage, ''), imported.coverImage, '')
+FROM imported
+WHERE games.id = imported.id
+    AND imported.position = 1;
+DELETE FROM games
+WHERE id IN (
+        SELECT id
+        FROM (
+                SELECT id,
+                    row_number() OVER (
+                        PARTITION BY externalSource,
+                        externalId
+                        ORDER BY id
+                    ) AS position
+                FROM games
+                WHERE externalId IS NOT NULL
+            ) AS imported
+        WHERE position > 1
+    );
+-- a game is imported once per external source
+CREATE UNIQUE INDEX IF NOT EXISTS idx_games_external_key ON games (externalSource, externalId);
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+-- merged duplicates are not restored
+DROP INDEX IF EXISTS idx_games_external_key;
+UPDATE games
+SET externalSource = externalSlug
+WHERE externalSlug IS NOT NULL;
+ALTER TABLE games
+DROP COLUMN externalSlug,
+DROP COLUMN updatedAt;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+-- details fetched lazily from the external source of a game
+ALTER TABLE games
+ADD COLUMN IF NOT EXISTS website TEXT DEFAULT NULL,
+ADD COLUMN IF NOT EXISTS metacritic INTEGER DEFAULT NULL,
+ADD COLUMN IF NOT EXISTS playtime INTEGER DEFAULT NULL,
+ADD COLUMN IF NOT EXISTS detailsFetchedAt TIMESTAMP DEFAULT NULL;
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE games
+DROP COLUMN website,
+DROP COLUMN metacritic,
+DROP COLUMN playtime,
+DROP COLUMN detailsFetchedAt;
+-- +goose StatementEnd
@@ -0,0 +1,59 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS genres (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    slug VARCHAR(255) NOT NULL UNIQUE,
+    name TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS tags (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    slug VARCHAR(255) NOT NULL UNIQUE,
+    name TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS developers (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    slug VARCHAR(255) NOT NULL UNIQUE,
+    name TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS publishers (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    slug VARCHAR(255) NOT NULL UNIQUE,
+    name TEXT NOT NULL
+);
+-- join tables, indexed by taxonomy to browse and count its games
+CREATE TABLE IF NOT EXISTS game_genres (
+    gameId UUID NOT NULL REFERENCES games (id) ON DELETE CASCADE,
+    genreId UUID NOT NULL REFERENCES genres (id) ON DELETE CASCADE,
+    PRIMARY KEY (gameId, genreId)
+);
+CREATE INDEX IF NOT EXISTS idx_game_genres_genre ON game_genres (genreId);
+CREATE TABLE IF NOT EXISTS game_tags (
+    gameId UUID NOT NULL REFERENCES games (id) ON DELETE CASCADE,
+    tagId UUID NOT NULL REFERENCES tags (id) ON DELETE CASCADE,
+    PRIMARY KEY (gameId, tagId)
+);
+CREATE INDEX IF NOT EXISTS idx_game_tags_tag ON game_tags (tagId);
+CREATE TABLE IF NOT EXISTS game_developers (
+    gameId UUID NOT NULL REFERENCES games (id) ON DELETE CASCADE,
+    developerId UUID NOT NULL REFERENCES developers (id) ON DELETE CASCADE,
+    PRIMARY KEY (gameId, developerId)
+);
+CREATE INDEX IF NOT EXISTS idx_game_developers_developer ON game_developers (developerId);
+CREATE TABLE IF NOT EXISTS game_publishers (
+    gameId UUID NOT NULL REFERENCES games (id) ON DELETE CASCADE,
+    publisherId UUID NOT NULL REFERENCES publishers (id) ON DELETE CASCADE,
+    PRIMARY KEY (gameId, publisherId)
+);
+CREATE INDEX IF NOT EXISTS idx_game_publishers_publisher ON game_publishers (publisherId);
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS game_genres;
+DROP TABLE IF EXISTS game_tags;
+DROP TABLE IF EXISTS game_developers;
+DROP TABLE IF EXISTS game_publishers;
+DROP TABLE IF EXISTS genres;
+DROP TABLE IF EXISTS tags;
+DROP TABLE IF EXISTS developers;
+DROP TABLE IF EXISTS publishers;
+-- +goose StatementEnd
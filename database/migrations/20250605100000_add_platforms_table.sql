@@ -0,0 +1,67 @@
+-- +goose Up
+-- +goose StatementBegin
+-- platforms synced from RAWG. Parents group the platforms of a family and share the
+-- slug namespace with them, e.g. the "pc" parent holds the "pc" platform.
+CREATE TABLE IF NOT EXISTS platforms (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    slug VARCHAR(255) NOT NULL,
+    name TEXT NOT NULL,
+    isParent BOOLEAN NOT NULL DEFAULT FALSE,
+    parentId UUID REFERENCES platforms (id) ON DELETE SET NULL,
+    UNIQUE (slug, isParent)
+);
+CREATE INDEX IF NOT EXISTS idx_platforms_parent ON platforms (parentId);
+-- alternative names of a platform or parent, stored lowercased
+CREATE TABLE IF NOT EXISTS platform_aliases (
+    alias TEXT PRIMARY KEY,
+    platformSlug VARCHAR(255) NOT NULL
+);
+INSERT INTO platform_aliases (alias, platformSlug)
+VALUES ('ps', 'playstation'),
+    ('ps1', 'playstation1'),
+    ('psx', 'playstation1'),
+    ('ps2', 'playstation2'),
+    ('ps3', 'playstation3'),
+    ('ps4', 'playstation4'),
+    ('ps5', 'playstation5'),
+    ('vita', 'ps-vita'),
+    ('switch', 'nintendo-switch'),
+    ('xbox series x', 'xbox-series-x'),
+    ('xbox series s', 'xbox-series-x'),
+    ('xbox series sx', 'xbox-series-x'),
+    ('xbox 360', 'xbox360'),
+    ('windows', 'pc'),
+    ('mac', 'macos'),
+    ('osx', 'macos') ON CONFLICT (alias) DO NOTHING;
+-- games store platform names, so requested platforms are expanded into the names of the
+-- matching platforms and of the children of matching parents. Requested names are kept
+-- as is so names unknown to the catalog still match exactly.
+CREATE OR REPLACE FUNCTION expand_platforms(requested TEXT []) RETURNS TEXT [] AS $$
+SELECT ARRAY(
+        SELECT DISTINCT child.name
+        FROM platforms AS matched
+            JOIN platforms AS child ON child.id = matched.id
+            OR child.parentId = matched.id
+        WHERE lower(matched.name) = ANY(lowered.names)
+            OR matched.slug = ANY(lowered.names)
+            OR matched.slug IN (
+                SELECT platformSlug
+                FROM platform_aliases
+                WHERE alias = ANY(lowered.names)
+            )
+    ) || requested
+FROM (
+        SELECT ARRAY(
+                SELECT lower(unnest(requested))
+            ) AS names
+    ) AS lowered $$ LANGUAGE sql STABLE;
+-- index game platforms for overlap and containment filters
+CREATE INDEX IF NOT EXISTS idx_games_platforms ON games USING GIN (platforms);
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_games_platforms;
+DROP FUNCTION IF EXISTS expand_platforms(TEXT []);
+DROP TABLE IF EXISTS platform_aliases;
+DROP TABLE IF EXISTS platforms;
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+-- normalized title and release year, identifying the same game across external sources
+ALTER TABLE games
+ADD COLUMN IF NOT EXISTS dedupeKey TEXT DEFAULT NULL;
+-- approximates models.Game.DedupeKey, which also strips accents; rows get the exact key
+-- on their next import
+UPDATE games
+SET dedupeKey = trim(regexp_replace(lower(title), '[^[:alnum:]]+', ' ', 'g')) || ':' || extract(year FROM releaseDate)::int
+WHERE releaseDate > DATE '0001-01-01'
+    AND trim(regexp_replace(lower(title), '[^[:alnum:]]+', ' ', 'g')) <> '';
+CREATE INDEX IF NOT EXISTS idx_games_dedupe_key ON games (dedupeKey);
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_games_dedupe_key;
+ALTER TABLE games
+DROP COLUMN dedupeKey;
+-- +goose StatementEnd
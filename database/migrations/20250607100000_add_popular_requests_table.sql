@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+-- first pages requested by kind, replayed by the cache warm-up; kept apart from the cache
+-- so they survive a flush of Redis. hits decay with a half-life from lastRequestedAt
+CREATE TABLE IF NOT EXISTS popular_requests (
+    kind TEXT NOT NULL,
+    request TEXT NOT NULL,
+    hits DOUBLE PRECISION NOT NULL DEFAULT 1,
+    lastRequestedAt TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (kind, request)
+);
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS popular_requests;
+-- +goose StatementEnd
@@ -0,0 +1,146 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/melkdesousa/gamgo/config"
+	"github.com/melkdesousa/gamgo/utils"
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrCacheUnavailable is returned without calling Redis while the cache is considered down.
+var ErrCacheUnavailable = errors.New("cache unavailable")
+
+// ResilientCache stops calling Redis for a cooldown after repeated failures, so an outage
+// fails the cache calls right away instead of after a timeout each. Get, Set, Del, SetNX,
+// Publish, the scripts and the pipelines go through its circuit breaker, the other
+// commands of the embedded client call Redis directly.
+type ResilientCache struct {
+	redis.Cmdable
+	breaker  *utils.CircuitBreaker
+	degraded atomic.Bool
+}
+
+// NewResilientCache wraps client with a circuit breaker configured by
+// CACHE_BREAKER_THRESHOLD and CACHE_BREAKER_COOLDOWN_SECONDS.
+func NewResilientCache(client redis.Cmdable) *ResilientCache {
+	return &ResilientCache{
+		Cmdable: client,
+		breaker: utils.NewCircuitBreaker(
+			config.GetEnvOrDefault("CACHE_BREAKER_THRESHOLD", 5),
+			time.Duration(config.GetEnvOrDefault("CACHE_BREAKER_COOLDOWN_SECONDS", 30))*time.Second,
+		),
+	}
+}
+
+// Degraded reports whether the cache is considered down, from the failure opening the
+// circuit breaker until a call succeeds again.
+func (c *ResilientCache) Degraded() bool {
+	return c.degraded.Load()
+}
+
+func (c *ResilientCache) Get(ctx context.Context, key string) *redis.StringCmd {
+	if err := c.allow(); err != nil {
+		return redis.NewStringResult("", err)
+	}
+	cmd := c.Cmdable.Get(ctx, key)
+	c.record(ctx, cmd.Err())
+	return cmd
+}
+
+func (c *ResilientCache) Set(ctx context.Context, key string, value any, expiration time.Duration) *redis.StatusCmd {
+	if err := c.allow(); err != nil {
+		return redis.NewStatusResult("", err)
+	}
+	cmd := c.Cmdable.Set(ctx, key, value, expiration)
+	c.record(ctx, cmd.Err())
+	return cmd
+}
+
+func (c *ResilientCache) Del(ctx context.Context, keys ...string) *redis.IntCmd {
+	if err := c.allow(); err != nil {
+		return redis.NewIntResult(0, err)
+	}
+	cmd := c.Cmdable.Del(ctx, keys...)
+	c.record(ctx, cmd.Err())
+	return cmd
+}
+
+func (c *ResilientCache) SetNX(ctx context.Context, key string, value any, expiration time.Duration) *redis.BoolCmd {
+	if err := c.allow(); err != nil {
+		return redis.NewBoolResult(false, err)
+	}
+	cmd := c.Cmdable.SetNX(ctx, key, value, expiration)
+	c.record(ctx, cmd.Err())
+	return cmd
+}
+
+func (c *ResilientCache) Publish(ctx context.Context, channel string, message any) *redis.IntCmd {
+	if err := c.allow(); err != nil {
+		return redis.NewIntResult(0, err)
+	}
+	cmd := c.Cmdable.Publish(ctx, channel, message)
+	c.record(ctx, cmd.Err())
+	return cmd
+}
+
+func (c *ResilientCache) Eval(ctx context.Context, script string, keys []string, args ...any) *redis.Cmd {
+	if err := c.allow(); err != nil {
+		return redis.NewCmdResult(nil, err)
+	}
+	cmd := c.Cmdable.Eval(ctx, script, keys, args...)
+	c.record(ctx, cmd.Err())
+	return cmd
+}
+
+func (c *ResilientCache) EvalSha(ctx context.Context, sha1 string, keys []string, args ...any) *redis.Cmd {
+	if err := c.allow(); err != nil {
+		return redis.NewCmdResult(nil, err)
+	}
+	cmd := c.Cmdable.EvalSha(ctx, sha1, keys, args...)
+	c.record(ctx, cmd.Err())
+	return cmd
+}
+
+func (c *ResilientCache) Pipelined(ctx context.Context, fn func(redis.Pipeliner) error) ([]redis.Cmder, error) {
+	if err := c.allow(); err != nil {
+		return nil, err
+	}
+	cmds, err := c.Cmdable.Pipelined(ctx, fn)
+	c.record(ctx, err)
+	return cmds, err
+}
+
+// allow returns ErrCacheUnavailable while the circuit breaker rejects calls.
+func (c *ResilientCache) allow() error {
+	if err := c.breaker.Allow(); err != nil {
+		return fmt.Errorf("%w: %w", ErrCacheUnavailable, err)
+	}
+	return nil
+}
+
+// record reports the outcome of a call to the circuit breaker. Missing keys and the errors
+// replied by Redis, such as NOSCRIPT, mean it is up.
+func (c *ResilientCache) record(ctx context.Context, err error) {
+	var replyErr redis.Error
+	switch {
+	case err == nil || errors.Is(err, redis.Nil) || errors.As(err, &replyErr):
+		c.breaker.Success()
+		if c.degraded.CompareAndSwap(true, false) {
+			log.Println("Cache is available again, leaving degraded mode")
+		}
+	case ctx.Err() != nil:
+		// the caller gave up, which tells nothing about Redis
+		c.breaker.Abort()
+	default:
+		c.breaker.Failure()
+		if c.breaker.Open() && c.degraded.CompareAndSwap(false, true) {
+			log.Printf("Cache unavailable, entering degraded mode: %v", err)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package database
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResilientCache(t *testing.T) {
+	ctx := context.Background()
+	// newUnreachableCache wraps a client of an address nothing listens on
+	newUnreachableCache := func(t *testing.T) (*ResilientCache, *int) {
+		t.Setenv("CACHE_BREAKER_THRESHOLD", "2")
+		t.Setenv("CACHE_BREAKER_COOLDOWN_SECONDS", "60")
+		dials := 0
+		client := redis.NewClient(&redis.Options{
+			Addr:       "127.0.0.1:1",
+			MaxRetries: -1,
+			Dialer: func(ctx context.Context, network, addr string) (net.Conn, error) {
+				dials++
+				return (&net.Dialer{Timeout: 100 * time.Millisecond}).DialContext(ctx, network, addr)
+			},
+		})
+		t.Cleanup(func() { client.Close() })
+		return NewResilientCache(client), &dials
+	}
+
+	t.Run("TestOpensAfterRepeatedFailures", func(t *testing.T) {
+		cache, dials := newUnreachableCache(t)
+
+		assert.Error(t, cache.Get(ctx, "key").Err())
+		assert.False(t, cache.Degraded(), "A single failure should not degrade the cache")
+		assert.Error(t, cache.Set(ctx, "key", "value", time.Minute).Err())
+		assert.True(t, cache.Degraded())
+
+		// Redis is no longer called during the cooldown
+		calls := *dials
+		assert.ErrorIs(t, cache.Get(ctx, "key").Err(), ErrCacheUnavailable)
+		assert.ErrorIs(t, cache.Del(ctx, "key").Err(), ErrCacheUnavailable)
+		assert.ErrorIs(t, cache.SetNX(ctx, "lock", "token", time.Minute).Err(), ErrCacheUnavailable)
+		assert.Equal(t, calls, *dials)
+	})
+
+	t.Run("TestIgnoresCallsCancelledByTheCaller", func(t *testing.T) {
+		cache, _ := newUnreachableCache(t)
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		for range 3 {
+			assert.Error(t, cache.Get(cancelled, "key").Err())
+		}
+		assert.False(t, cache.Degraded())
+	})
+}
@@ -0,0 +1,214 @@
+package database
+
+import (
+	"container/list"
+	"context"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/melkdesousa/gamgo/config"
+	"github.com/redis/go-redis/v9"
+)
+
+// CACHE_INVALIDATION_CHANNEL is the pub/sub channel on which instances announce the keys they
+// changed, so the others evict them from their in-process cache.
+const CACHE_INVALIDATION_CHANNEL = "cache:invalidate"
+
+// Subscriber subscribes to pub/sub channels, such as a *redis.Client.
+type Subscriber interface {
+	Subscribe(ctx context.Context, channels ...string) *redis.PubSub
+}
+
+// TieredCache keeps the hot values of a remote cache in a bounded in-process LRU for a
+// short time, sparing a round trip to Redis. Writes go to both tiers and are announced on
+// CACHE_INVALIDATION_CHANNEL, so the other instances drop their copy when they Listen.
+// Missing keys are never kept in process, and a value read while another instance changes
+// it may be kept until it expires from the in-process tier. The other commands of the
+// embedded remote cache skip the in-process tier.
+type TieredCache struct {
+	redis.Cmdable
+	local *lru
+	// id tells the announcements of the instance apart from those of the others
+	id string
+}
+
+// NewTieredCache creates a two-tier cache in front of remote, with an in-process tier of
+// CACHE_L1_SIZE values kept up to CACHE_L1_TTL_SECONDS. A zero size disables it.
+func NewTieredCache(remote redis.Cmdable) *TieredCache {
+	return &TieredCache{
+		Cmdable: remote,
+		local: newLRU(
+			config.GetEnvOrDefault("CACHE_L1_SIZE", 1000),
+			time.Duration(config.GetEnvOrDefault("CACHE_L1_TTL_SECONDS", 10))*time.Second,
+		),
+		id: uuid.NewString(),
+	}
+}
+
+func (c *TieredCache) Get(ctx context.Context, key string) *redis.StringCmd {
+	if value, ok := c.local.get(key); ok {
+		return redis.NewStringResult(value, nil)
+	}
+	cmd := c.Cmdable.Get(ctx, key)
+	if value, err := cmd.Result(); err == nil {
+		c.local.add(key, value, 0)
+	}
+	return cmd
+}
+
+func (c *TieredCache) Set(ctx context.Context, key string, value any, expiration time.Duration) *redis.StatusCmd {
+	cmd := c.Cmdable.Set(ctx, key, value, expiration)
+	c.local.remove(key)
+	if cmd.Err() == nil {
+		switch value := value.(type) {
+		case string:
+			c.local.add(key, value, expiration)
+		case []byte:
+			c.local.add(key, string(value), expiration)
+		}
+	}
+	c.announce(ctx, key)
+	return cmd
+}
+
+func (c *TieredCache) Del(ctx context.Context, keys ...string) *redis.IntCmd {
+	cmd := c.Cmdable.Del(ctx, keys...)
+	for _, key := range keys {
+		c.local.remove(key)
+		c.announce(ctx, key)
+	}
+	return cmd
+}
+
+// announce asks the other instances to evict key.
+func (c *TieredCache) announce(ctx context.Context, key string) {
+	if c.local.disabled() {
+		return
+	}
+	if err := c.Cmdable.Publish(ctx, CACHE_INVALIDATION_CHANNEL, c.id+" "+key).Err(); err != nil {
+		log.Printf("Error announcing the invalidation of cache key %s: %v", key, err)
+	}
+}
+
+// Listen evicts the keys announced by the other instances until ctx is done. The whole
+// in-process tier is dropped whenever the subscription fails, since announcements may have
+// been missed meanwhile.
+func (c *TieredCache) Listen(ctx context.Context, subscriber Subscriber) {
+	if c.local.disabled() {
+		return
+	}
+	pubsub := subscriber.Subscribe(ctx, CACHE_INVALIDATION_CHANNEL)
+	defer pubsub.Close()
+	for {
+		msg, err := pubsub.ReceiveMessage(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			log.Printf("Error receiving cache invalidations, dropping the in-process cache: %v", err)
+			c.local.purge()
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(time.Second):
+			}
+			continue
+		}
+		c.invalidate(msg.Payload)
+	}
+}
+
+// invalidate evicts the key of an announcement made by another instance.
+func (c *TieredCache) invalidate(payload string) {
+	origin, key, ok := strings.Cut(payload, " ")
+	if !ok || origin == c.id {
+		return
+	}
+	c.local.remove(key)
+}
+
+// lru is a bounded map of values expiring after ttl, evicting the least recently used
+// value when full.
+type lru struct {
+	size int
+	ttl  time.Duration
+	now  func() time.Time
+
+	mu      sync.Mutex
+	order   *list.List // of *lruEntry, most recently used first
+	entries map[string]*list.Element
+}
+
+type lruEntry struct {
+	key       string
+	value     string
+	expiresAt time.Time
+}
+
+func newLRU(size int, ttl time.Duration) *lru {
+	return &lru{size: size, ttl: ttl, now: time.Now, order: list.New(), entries: map[string]*list.Element{}}
+}
+
+func (l *lru) disabled() bool {
+	return l.size <= 0 || l.ttl <= 0
+}
+
+func (l *lru) get(key string) (string, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	element, ok := l.entries[key]
+	if !ok {
+		return "", false
+	}
+	entry := element.Value.(*lruEntry)
+	if !l.now().Before(entry.expiresAt) {
+		l.order.Remove(element)
+		delete(l.entries, key)
+		return "", false
+	}
+	l.order.MoveToFront(element)
+	return entry.value, true
+}
+
+// add keeps value for the ttl of the lru, or for expiration when shorter and not zero.
+func (l *lru) add(key, value string, expiration time.Duration) {
+	if l.disabled() {
+		return
+	}
+	ttl := l.ttl
+	if expiration > 0 {
+		ttl = min(ttl, expiration)
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if element, ok := l.entries[key]; ok {
+		element.Value = &lruEntry{key: key, value: value, expiresAt: l.now().Add(ttl)}
+		l.order.MoveToFront(element)
+		return
+	}
+	l.entries[key] = l.order.PushFront(&lruEntry{key: key, value: value, expiresAt: l.now().Add(ttl)})
+	for l.order.Len() > l.size {
+		oldest := l.order.Back()
+		l.order.Remove(oldest)
+		delete(l.entries, oldest.Value.(*lruEntry).key)
+	}
+}
+
+func (l *lru) remove(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if element, ok := l.entries[key]; ok {
+		l.order.Remove(element)
+		delete(l.entries, key)
+	}
+}
+
+func (l *lru) purge() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.order.Init()
+	clear(l.entries)
+}
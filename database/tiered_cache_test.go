@@ -0,0 +1,131 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeRemote is a shared cache delivering its announcements to the tiered caches in front
+// of it. Its other commands are not implemented.
+type fakeRemote struct {
+	redis.Cmdable
+	values      map[string]string
+	gets        int
+	subscribers []*TieredCache
+}
+
+func (r *fakeRemote) Get(ctx context.Context, key string) *redis.StringCmd {
+	r.gets++
+	value, ok := r.values[key]
+	if !ok {
+		return redis.NewStringResult("", redis.Nil)
+	}
+	return redis.NewStringResult(value, nil)
+}
+
+func (r *fakeRemote) Set(ctx context.Context, key string, value any, expiration time.Duration) *redis.StatusCmd {
+	r.values[key] = value.(string)
+	return redis.NewStatusResult("OK", nil)
+}
+
+func (r *fakeRemote) Del(ctx context.Context, keys ...string) *redis.IntCmd {
+	for _, key := range keys {
+		delete(r.values, key)
+	}
+	return redis.NewIntResult(int64(len(keys)), nil)
+}
+
+func (r *fakeRemote) Publish(ctx context.Context, channel string, message any) *redis.IntCmd {
+	for _, subscriber := range r.subscribers {
+		subscriber.invalidate(message.(string))
+	}
+	return redis.NewIntResult(int64(len(r.subscribers)), nil)
+}
+
+func TestTieredCache(t *testing.T) {
+	ctx := context.Background()
+	t.Setenv("CACHE_L1_SIZE", "2")
+	t.Setenv("CACHE_L1_TTL_SECONDS", "10")
+	newCaches := func(instances int) (*fakeRemote, []*TieredCache) {
+		remote := &fakeRemote{values: map[string]string{}}
+		for range instances {
+			remote.subscribers = append(remote.subscribers, NewTieredCache(remote))
+		}
+		return remote, remote.subscribers
+	}
+
+	t.Run("TestServesHotKeysInProcess", func(t *testing.T) {
+		remote, caches := newCaches(1)
+		remote.values["key"] = "value"
+
+		for range 3 {
+			assert.Equal(t, "value", caches[0].Get(ctx, "key").Val())
+		}
+		assert.Equal(t, 1, remote.gets)
+	})
+
+	t.Run("TestDoesNotKeepMisses", func(t *testing.T) {
+		remote, caches := newCaches(1)
+
+		assert.ErrorIs(t, caches[0].Get(ctx, "key").Err(), redis.Nil)
+		assert.ErrorIs(t, caches[0].Get(ctx, "key").Err(), redis.Nil)
+		assert.Equal(t, 2, remote.gets)
+	})
+
+	t.Run("TestEvictsTheLeastRecentlyUsedKeys", func(t *testing.T) {
+		remote, caches := newCaches(1)
+		remote.values = map[string]string{"a": "1", "b": "2", "c": "3"}
+
+		caches[0].Get(ctx, "a")
+		caches[0].Get(ctx, "b")
+		caches[0].Get(ctx, "a")
+		caches[0].Get(ctx, "c") // evicts b
+		gets := remote.gets
+		caches[0].Get(ctx, "a")
+		caches[0].Get(ctx, "c")
+		assert.Equal(t, gets, remote.gets)
+		caches[0].Get(ctx, "b")
+		assert.Equal(t, gets+1, remote.gets)
+	})
+
+	t.Run("TestExpiresKeysInProcess", func(t *testing.T) {
+		remote, caches := newCaches(1)
+		now := time.Now()
+		caches[0].local.now = func() time.Time { return now }
+		caches[0].Set(ctx, "key", "value", 5*time.Second)
+
+		now = now.Add(4 * time.Second)
+		assert.Equal(t, "value", caches[0].Get(ctx, "key").Val())
+		assert.Equal(t, 0, remote.gets)
+		now = now.Add(time.Second) // the remote expiration is shorter than the in-process ttl
+		caches[0].Get(ctx, "key")
+		assert.Equal(t, 1, remote.gets)
+	})
+
+	t.Run("TestInvalidatesOtherInstances", func(t *testing.T) {
+		_, caches := newCaches(2)
+		caches[0].Set(ctx, "key", "old", time.Minute)
+		assert.Equal(t, "old", caches[1].Get(ctx, "key").Val())
+
+		caches[0].Set(ctx, "key", "new", time.Minute)
+		assert.Equal(t, "new", caches[0].Get(ctx, "key").Val(), "The writer should keep its own value")
+		assert.Equal(t, "new", caches[1].Get(ctx, "key").Val())
+
+		caches[1].Del(ctx, "key")
+		assert.ErrorIs(t, caches[0].Get(ctx, "key").Err(), redis.Nil)
+	})
+
+	t.Run("TestDisabledInProcess", func(t *testing.T) {
+		t.Setenv("CACHE_L1_SIZE", "0")
+		remote, caches := newCaches(1)
+		remote.values["key"] = "value"
+
+		caches[0].Get(ctx, "key")
+		caches[0].Get(ctx, "key")
+		assert.Equal(t, 2, remote.gets)
+	})
+}
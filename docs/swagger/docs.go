@@ -19,35 +19,47 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/auth/login": {
-            "post": {
-                "description": "Authenticate user and return JWT token",
-                "consumes": [
-                    "application/json"
+        "/admin/cache/keys": {
+            "get": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
                 ],
+                "description": "list the keys of the cache matching a glob pattern, or the cache keys starting with a prefix whatever their schema version. All cache keys are listed when neither is given. Patterns must start with a schema version, such as v*:, or with tag:",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "admin"
                 ],
-                "summary": "User Login",
+                "summary": "Cache Keys",
                 "parameters": [
                     {
-                        "description": "User login credentials",
-                        "name": "login",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.LoginRequest"
-                        }
+                        "type": "string",
+                        "description": "glob pattern of the keys, such as v*:search:*",
+                        "name": "pattern",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "prefix of the cache keys without their schema version, such as search:game",
+                        "name": "prefix",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 100,
+                        "description": "maximum number of keys",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/mappers.AuthResponse"
+                            "$ref": "#/definitions/mappers.CommonResponse-mappers_CacheKeysOutputDTO"
                         }
                     },
                     "400": {
@@ -69,47 +81,32 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/games": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
-                        "JWT": []
+                        "AdminToken": []
                     }
                 ],
-                "description": "get games",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "delete the keys of the cache matching a glob pattern, or the cache keys starting with a prefix whatever their schema version. One of them is required. Patterns must start with a schema version, such as v*:, or with tag:",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "games"
+                    "admin"
                 ],
-                "summary": "List Games",
+                "summary": "Purge Cache",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "game search by title",
-                        "name": "title",
-                        "in": "query"
-                    },
-                    {
-                        "type": "array",
-                        "items": {
-                            "type": "string"
-                        },
-                        "collectionFormat": "csv",
-                        "description": "game search by platforms, comma-separated",
-                        "name": "platforms",
+                        "description": "glob pattern of the keys, such as v*:search:*",
+                        "name": "pattern",
                         "in": "query"
                     },
                     {
-                        "type": "integer",
-                        "description": "page number, default is 1",
-                        "name": "page",
+                        "type": "string",
+                        "description": "prefix of the cache keys without their schema version, such as search:game",
+                        "name": "prefix",
                         "in": "query"
                     }
                 ],
@@ -117,10 +114,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/mappers.PaginationResponse-array_mappers_GameOutputDTO"
-                            }
+                            "$ref": "#/definitions/mappers.CommonResponse-mappers_CachePurgeOutputDTO"
                         }
                     },
                     "400": {
@@ -129,10 +123,10 @@ const docTemplate = `{
                             "$ref": "#/definitions/mappers.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/mappers.PaginationResponse-array_mappers_GameOutputDTO"
+                            "$ref": "#/definitions/mappers.ErrorResponse"
                         }
                     },
                     "500": {
@@ -144,46 +138,35 @@ const docTemplate = `{
                 }
             }
         },
-        "/games/search": {
+        "/admin/cache/keys/{key}": {
             "get": {
                 "security": [
                     {
-                        "JWT": []
+                        "AdminToken": []
                     }
                 ],
-                "description": "search games by title",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "get the value of a key of the cache, as listed by the cache keys, with its schema version and time to live. Keys must start with a schema version or with tag:",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "games"
+                    "admin"
                 ],
-                "summary": "Search Games",
+                "summary": "Cache Entry",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "game search by title",
-                        "name": "title",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "page number, default is 1",
-                        "name": "page",
-                        "in": "query"
+                        "description": "key of the cache, including its schema version",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/mappers.PaginationResponse-array_mappers_GameOutputDTO"
-                            }
+                            "$ref": "#/definitions/mappers.CommonResponse-mappers_CacheEntryOutputDTO"
                         }
                     },
                     "400": {
@@ -192,10 +175,16 @@ const docTemplate = `{
                             "$ref": "#/definitions/mappers.ErrorResponse"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/mappers.ErrorResponse"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/mappers.PaginationResponse-array_mappers_GameOutputDTO"
+                            "$ref": "#/definitions/mappers.ErrorResponse"
                         }
                     },
                     "500": {
@@ -206,95 +195,1220 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "handlers.LoginRequest": {
-            "type": "object",
-            "properties": {
-                "email": {
-                    "type": "string"
-                },
-                "password": {
-                    "type": "string"
-                }
-            }
         },
-        "mappers.AuthResponse": {
-            "type": "object",
-            "properties": {
-                "expiration": {
-                    "description": "Unix timestamp for token expiration",
-                    "type": "integer"
-                },
-                "token": {
-                    "type": "string"
+        "/admin/cache/metrics": {
+            "get": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "description": "get the counters of the search cache of the instance since it started, with its hit and miss ratios: fresh, stale and negative hits, misses, errors, corrupted entries, entries invalidated by changed games, and background refreshes scheduled, skipped, dropped, succeeded or failed",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Cache Metrics",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/mappers.CommonResponse-mappers_CacheMetricsOutputDTO"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/mappers.ErrorResponse"
+                        }
+                    }
                 }
             }
         },
-        "mappers.ErrorResponse": {
-            "type": "object",
-            "properties": {
-                "details": {
-                    "type": "string"
-                },
-                "error": {
-                    "type": "string"
+        "/admin/rawg/quota": {
+            "get": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "description": "get the number of RAWG requests made with each API key in the current month against its budget",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "RAWG Quota",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/mappers.CommonResponse-array_mappers_RawgKeyUsageOutputDTO"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/mappers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/mappers.ErrorResponse"
+                        }
+                    }
                 }
             }
         },
-        "mappers.GameOutputDTO": {
-            "type": "object",
-            "properties": {
-                "coverImage": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "platforms": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
+        "/auth/login": {
+            "post": {
+                "description": "Authenticate user and return JWT token",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "User Login",
+                "parameters": [
+                    {
+                        "description": "User login credentials",
+                        "name": "login",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.LoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/mappers.AuthResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/mappers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/mappers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/mappers.ErrorResponse"
+                        }
                     }
-                },
-                "rating": {
-                    "type": "number"
-                },
-                "released": {
-                    "type": "string"
-                },
-                "title": {
-                    "type": "string"
                 }
             }
         },
-        "mappers.PaginationResponse-array_mappers_GameOutputDTO": {
-            "type": "object",
-            "properties": {
-                "count": {
-                    "type": "integer"
-                },
-                "data": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/mappers.GameOutputDTO"
+        "/developers": {
+            "get": {
+                "security": [
+                    {
+                        "JWT": []
                     }
-                },
+                ],
+                "description": "get developers with their number of games, the most prolific first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "taxonomies"
+                ],
+                "summary": "List Developers",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "page number, default is 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page size, default is 10 and at most 50",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/mappers.PaginationResponse-array_mappers_TaxonomyOutputDTO"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/mappers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/games": {
+            "get": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "get games matching every provided filter",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "games"
+                ],
+                "summary": "List Games",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "game search expression",
+                        "name": "title",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "game platforms or platform families, comma-separated, see /platforms",
+                        "name": "platforms",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "any",
+                            "all"
+                        ],
+                        "type": "string",
+                        "description": "whether games must run on any or all of the platforms, default is any",
+                        "name": "platformMatch",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "earliest release date, YYYY-MM-DD",
+                        "name": "releasedFrom",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "latest release date, YYYY-MM-DD",
+                        "name": "releasedTo",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "minimum rating, from 0 to 5",
+                        "name": "minRating",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "maximum rating, from 0 to 5",
+                        "name": "maxRating",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "external source the game was imported from",
+                        "name": "source",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "genre slugs, comma-separated, games must have any of them",
+                        "name": "genres",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "tag slugs, comma-separated, games must have any of them",
+                        "name": "tags",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "developer slugs, comma-separated, games must have any of them",
+                        "name": "developers",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "publisher slugs, comma-separated, games must have any of them",
+                        "name": "publishers",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page number, default is 1, ignored when a cursor is given",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page size, default is 10 and at most 50",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next or prev cursor returned with a previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "relevance",
+                            "rating",
+                            "releaseDate",
+                            "title"
+                        ],
+                        "type": "string",
+                        "description": "sort field, defaults to relevance with a title and to title otherwise",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "description": "sort direction, defaults to desc except for title",
+                        "name": "order",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/mappers.PaginationResponse-array_mappers_GameOutputDTO"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/mappers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/mappers.PaginationResponse-array_mappers_GameOutputDTO"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/mappers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/games/by-external/{source}/{externalId}": {
+            "get": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "get a game and its details by the id of the source it was imported from, importing RAWG games on demand",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "games"
+                ],
+                "summary": "Get Game By External ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "external source, such as rawg",
+                        "name": "source",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "id of the game in the external source",
+                        "name": "externalId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/mappers.CommonResponse-mappers_GameDetailOutputDTO"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/mappers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/mappers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/mappers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/games/search": {
+            "get": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "search games by title",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "games"
+                ],
+                "summary": "Search Games",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "game search expression supporting quoted phrases, OR, -excluded and prefix* terms",
+                        "name": "title",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page number, default is 1, ignored when a cursor is given",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page size, default is 10 and at most 50",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next or prev cursor returned with a previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "relevance",
+                            "rating",
+                            "releaseDate",
+                            "title"
+                        ],
+                        "type": "string",
+                        "description": "sort field, default is relevance",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "description": "sort direction, defaults to desc except for title",
+                        "name": "order",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/mappers.PaginationResponse-array_mappers_GameOutputDTO"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/mappers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/mappers.PaginationResponse-array_mappers_GameOutputDTO"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/mappers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/games/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "get a game and its details by id, fetching the details from RAWG when missing or stale",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "games"
+                ],
+                "summary": "Get Game",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "game id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/mappers.CommonResponse-mappers_GameDetailOutputDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/mappers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/mappers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/mappers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/genres": {
+            "get": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "get genres with their number of games, the most used first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "taxonomies"
+                ],
+                "summary": "List Genres",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "page number, default is 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page size, default is 10 and at most 50",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/mappers.PaginationResponse-array_mappers_TaxonomyOutputDTO"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/mappers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/health": {
+            "get": {
+                "description": "check that the service is up. While the cache is down, searches are served from the database and the status is degraded",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Health",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/mappers.HealthResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/platforms": {
+            "get": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "get the platform families with their platforms and the number of games of each",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "platforms"
+                ],
+                "summary": "List Platforms",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/mappers.CommonResponse-array_mappers_PlatformOutputDTO"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/mappers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/publishers": {
+            "get": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "get publishers with their number of games, the most prolific first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "taxonomies"
+                ],
+                "summary": "List Publishers",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "page number, default is 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page size, default is 10 and at most 50",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/mappers.PaginationResponse-array_mappers_TaxonomyOutputDTO"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/mappers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tags": {
+            "get": {
+                "security": [
+                    {
+                        "JWT": []
+                    }
+                ],
+                "description": "get tags with their number of games, the most used first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "taxonomies"
+                ],
+                "summary": "List Tags",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "page number, default is 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page size, default is 10 and at most 50",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/mappers.PaginationResponse-array_mappers_TaxonomyOutputDTO"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/mappers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "handlers.LoginRequest": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "mappers.AuthResponse": {
+            "type": "object",
+            "properties": {
+                "expiration": {
+                    "description": "Unix timestamp for token expiration",
+                    "type": "integer"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "mappers.CacheEntryOutputDTO": {
+            "type": "object",
+            "properties": {
+                "key": {
+                    "type": "string"
+                },
+                "ttlSeconds": {
+                    "description": "zero when it never expires",
+                    "type": "integer"
+                },
+                "value": {
+                    "type": "object"
+                },
+                "version": {
+                    "description": "Version is the schema version of the value, empty for keys outside of typed caches",
+                    "type": "string"
+                }
+            }
+        },
+        "mappers.CacheKeysOutputDTO": {
+            "type": "object",
+            "properties": {
+                "keys": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "pattern": {
+                    "type": "string"
+                }
+            }
+        },
+        "mappers.CacheMetricsOutputDTO": {
+            "type": "object",
+            "properties": {
+                "counters": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "hitRatio": {
+                    "description": "HitRatio is the share of the searches served from the cache, fresh, stale or empty",
+                    "type": "number"
+                },
+                "missRatio": {
+                    "type": "number"
+                }
+            }
+        },
+        "mappers.CachePurgeOutputDTO": {
+            "type": "object",
+            "properties": {
+                "pattern": {
+                    "type": "string"
+                },
+                "purged": {
+                    "type": "integer"
+                }
+            }
+        },
+        "mappers.CommonResponse-array_mappers_PlatformOutputDTO": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/mappers.PlatformOutputDTO"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "mappers.CommonResponse-array_mappers_RawgKeyUsageOutputDTO": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/mappers.RawgKeyUsageOutputDTO"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "mappers.CommonResponse-mappers_CacheEntryOutputDTO": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/mappers.CacheEntryOutputDTO"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "mappers.CommonResponse-mappers_CacheKeysOutputDTO": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/mappers.CacheKeysOutputDTO"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "mappers.CommonResponse-mappers_CacheMetricsOutputDTO": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/mappers.CacheMetricsOutputDTO"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "mappers.CommonResponse-mappers_CachePurgeOutputDTO": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/mappers.CachePurgeOutputDTO"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "mappers.CommonResponse-mappers_GameDetailOutputDTO": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/mappers.GameDetailOutputDTO"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "mappers.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "details": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                }
+            }
+        },
+        "mappers.GameDetailOutputDTO": {
+            "type": "object",
+            "properties": {
+                "coverImage": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "developers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/mappers.TaxonomyOutputDTO"
+                    }
+                },
+                "externalId": {
+                    "type": "string"
+                },
+                "externalSource": {
+                    "type": "string"
+                },
+                "genres": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/mappers.TaxonomyOutputDTO"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "metacritic": {
+                    "type": "integer"
+                },
+                "platforms": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "playtime": {
+                    "description": "average hours",
+                    "type": "integer"
+                },
+                "publishers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/mappers.TaxonomyOutputDTO"
+                    }
+                },
+                "rating": {
+                    "description": "out of 5, with the decimals of the stored hundredths",
+                    "type": "number"
+                },
+                "released": {
+                    "type": "string"
+                },
+                "snippet": {
+                    "description": "matched text, terms wrapped in \u003cmark\u003e tags",
+                    "type": "string"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/mappers.TaxonomyOutputDTO"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                },
+                "website": {
+                    "type": "string"
+                }
+            }
+        },
+        "mappers.GameOutputDTO": {
+            "type": "object",
+            "properties": {
+                "coverImage": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "platforms": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "rating": {
+                    "description": "out of 5, with the decimals of the stored hundredths",
+                    "type": "number"
+                },
+                "released": {
+                    "type": "string"
+                },
+                "snippet": {
+                    "description": "matched text, terms wrapped in \u003cmark\u003e tags",
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "mappers.HealthResponse": {
+            "type": "object",
+            "properties": {
+                "cache": {
+                    "type": "string",
+                    "enum": [
+                        "up",
+                        "down"
+                    ]
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "ok",
+                        "degraded"
+                    ]
+                }
+            }
+        },
+        "mappers.PaginationResponse-array_mappers_GameOutputDTO": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/mappers.GameOutputDTO"
+                    }
+                },
+                "filters": {},
+                "limit": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "next": {
+                    "description": "cursor of the next page, pass it back as the cursor parameter",
+                    "type": "string"
+                },
+                "page": {
+                    "description": "only set when paginating by page number",
+                    "type": "integer"
+                },
+                "prev": {
+                    "description": "cursor of the previous page",
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "mappers.PaginationResponse-array_mappers_TaxonomyOutputDTO": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/mappers.TaxonomyOutputDTO"
+                    }
+                },
                 "filters": {},
+                "limit": {
+                    "type": "integer"
+                },
                 "message": {
                     "type": "string"
                 },
+                "next": {
+                    "description": "cursor of the next page, pass it back as the cursor parameter",
+                    "type": "string"
+                },
                 "page": {
+                    "description": "only set when paginating by page number",
                     "type": "integer"
                 },
+                "prev": {
+                    "description": "cursor of the previous page",
+                    "type": "string"
+                },
                 "total": {
                     "type": "integer"
                 }
             }
+        },
+        "mappers.PlatformOutputDTO": {
+            "type": "object",
+            "properties": {
+                "children": {
+                    "description": "only set for families",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/mappers.PlatformOutputDTO"
+                    }
+                },
+                "gamesCount": {
+                    "type": "integer"
+                },
+                "isParent": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "slug": {
+                    "type": "string"
+                }
+            }
+        },
+        "mappers.RawgKeyUsageOutputDTO": {
+            "type": "object",
+            "properties": {
+                "budget": {
+                    "description": "zero when unlimited",
+                    "type": "integer"
+                },
+                "hardLimit": {
+                    "type": "integer"
+                },
+                "key": {
+                    "description": "masked down to its last characters",
+                    "type": "string"
+                },
+                "period": {
+                    "type": "string"
+                },
+                "remaining": {
+                    "description": "zero when unlimited",
+                    "type": "integer"
+                },
+                "softLimit": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "ok",
+                        "soft_limit",
+                        "exhausted"
+                    ]
+                },
+                "used": {
+                    "type": "integer"
+                }
+            }
+        },
+        "mappers.TaxonomyOutputDTO": {
+            "type": "object",
+            "properties": {
+                "gamesCount": {
+                    "description": "only set by taxonomy listings",
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "slug": {
+                    "type": "string"
+                }
+            }
         }
     },
     "securityDefinitions": {
+        "AdminToken": {
+            "description": "Token of the administration endpoints, set through ADMIN_TOKEN",
+            "type": "apiKey",
+            "name": "X-Admin-Token",
+            "in": "header"
+        },
         "JWT": {
             "description": "Enter your JWT token in the format: Bearer \\\u003ctoken\\\u003e",
             "type": "apiKey",
@@ -0,0 +1,36 @@
+package providers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/melkdesousa/gamgo/config"
+	"github.com/melkdesousa/gamgo/external/rawg"
+)
+
+// NewRegistryFromEnv creates a registry of the providers listed in GAME_PROVIDERS, comma
+// separated in order of priority and defaulting to rawg. The static provider reads the
+// games of the file at STATIC_GAMES_FILE.
+func NewRegistryFromEnv(rawgClient RawgClient) (*Registry, error) {
+	var enabled []Provider
+	for _, name := range strings.Split(config.GetEnvOrDefault("GAME_PROVIDERS", rawg.SourceName), ",") {
+		switch name = strings.ToLower(strings.TrimSpace(name)); name {
+		case "":
+			continue
+		case rawg.SourceName:
+			enabled = append(enabled, NewRawgProvider(rawgClient))
+		case StaticSourceName:
+			provider, err := NewStaticProvider(config.MustGetEnv("STATIC_GAMES_FILE"))
+			if err != nil {
+				return nil, err
+			}
+			enabled = append(enabled, provider)
+		default:
+			return nil, fmt.Errorf("unknown game provider %q", name)
+		}
+	}
+	if len(enabled) == 0 {
+		return nil, fmt.Errorf("no game provider enabled in GAME_PROVIDERS")
+	}
+	return NewRegistry(enabled...), nil
+}
@@ -0,0 +1,87 @@
+// Package providers abstracts the external catalogs games are imported from behind a
+// common interface returning internal models.
+package providers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/melkdesousa/gamgo/dao/models"
+)
+
+// ErrUnavailable is returned when a provider refuses to be called for now, such as when
+// its request quota is exceeded or it is considered down. Callers should fall back to
+// the stored games.
+var ErrUnavailable = errors.New("provider unavailable")
+
+// Provider is an external catalog of games.
+type Provider interface {
+	// Source is the externalSource of the games of the provider.
+	Source() string
+	// SearchGames returns a page of the games matching term, pages starting at 1.
+	SearchGames(ctx context.Context, term string, page int) (SearchResult, error)
+	// GetGame returns a game along with its details by its id in the provider, or
+	// models.ErrGameNotFound.
+	GetGame(ctx context.Context, externalID string) (models.Game, error)
+}
+
+// SearchResult is a page of the games found by a provider.
+type SearchResult struct {
+	Games []models.Game
+	// Total is the number of matches across every page
+	Total   int
+	HasNext bool
+}
+
+// Registry holds the enabled providers by source, in order of priority.
+type Registry struct {
+	providers []Provider
+	bySource  map[string]Provider
+}
+
+// NewRegistry creates a registry of providers, the first ones taking priority when the
+// same game is found by several of them. Later providers of an already registered source
+// are ignored.
+func NewRegistry(providers ...Provider) *Registry {
+	registry := &Registry{bySource: make(map[string]Provider, len(providers))}
+	for _, provider := range providers {
+		if _, ok := registry.bySource[provider.Source()]; ok {
+			continue
+		}
+		registry.providers = append(registry.providers, provider)
+		registry.bySource[provider.Source()] = provider
+	}
+	return registry
+}
+
+// Get returns the provider of a source.
+func (r *Registry) Get(source string) (Provider, bool) {
+	provider, ok := r.bySource[source]
+	return provider, ok
+}
+
+// Providers returns the enabled providers in order of priority.
+func (r *Registry) Providers() []Provider {
+	return r.providers
+}
+
+// MergeGames concatenates the games found by several providers, given in order of
+// priority, dropping the games already found by a previous provider according to their
+// dedupe key.
+func MergeGames(results ...[]models.Game) []models.Game {
+	merged := []models.Game{}
+	seen := map[string]string{} // dedupe key to source
+	for _, games := range results {
+		for _, game := range games {
+			key := game.DedupeKey()
+			if source, ok := seen[key]; ok && key != "" && source != game.ExternalSource {
+				continue
+			}
+			if key != "" {
+				seen[key] = game.ExternalSource
+			}
+			merged = append(merged, game)
+		}
+	}
+	return merged
+}
@@ -0,0 +1,59 @@
+package providers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/melkdesousa/gamgo/dao/models"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeProvider struct {
+	source string
+}
+
+func (p fakeProvider) Source() string {
+	return p.source
+}
+
+func (p fakeProvider) SearchGames(context.Context, string, int) (SearchResult, error) {
+	return SearchResult{}, nil
+}
+
+func (p fakeProvider) GetGame(context.Context, string) (models.Game, error) {
+	return models.Game{}, models.ErrGameNotFound
+}
+
+func TestRegistry(t *testing.T) {
+	first := fakeProvider{source: "rawg"}
+	registry := NewRegistry(first, fakeProvider{source: "static"}, fakeProvider{source: "rawg"})
+
+	assert.Len(t, registry.Providers(), 2, "Later providers of a registered source should be ignored")
+	provider, ok := registry.Get("rawg")
+	assert.True(t, ok)
+	assert.Equal(t, first, provider)
+	_, ok = registry.Get("igdb")
+	assert.False(t, ok)
+}
+
+func TestMergeGames(t *testing.T) {
+	released := time.Date(2017, 2, 24, 0, 0, 0, 0, time.UTC)
+	rawgGames := []models.Game{
+		{Title: "Hollow Knight", ReleaseDate: released, ExternalID: "9767", ExternalSource: "rawg"},
+		{Title: "Untitled", ExternalID: "1", ExternalSource: "rawg"},
+	}
+	staticGames := []models.Game{
+		{Title: "HOLLOW KNIGHT!", ReleaseDate: released, ExternalID: "hollow-knight", ExternalSource: "static"},
+		{Title: "Hollow Knight", ReleaseDate: released.AddDate(1, 0, 0), ExternalID: "remaster", ExternalSource: "static"},
+		{Title: "Untitled", ExternalID: "untitled", ExternalSource: "static"},
+	}
+
+	merged := MergeGames(rawgGames, staticGames)
+
+	var ids []string
+	for _, game := range merged {
+		ids = append(ids, game.ExternalID)
+	}
+	assert.Equal(t, []string{"9767", "1", "remaster", "untitled"}, ids, "Only games with the same title and year should be merged")
+}
@@ -0,0 +1,65 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/melkdesousa/gamgo/dao/models"
+	"github.com/melkdesousa/gamgo/external/rawg"
+	"github.com/melkdesousa/gamgo/mappers"
+)
+
+// RawgClient is the part of the RAWG client used by the RAWG provider.
+type RawgClient interface {
+	SearchGames(ctx context.Context, query string, page int) (*rawg.GameListResponse, error)
+	GetGame(ctx context.Context, id string) (*rawg.GameDetailResponse, error)
+}
+
+// RawgProvider imports games from RAWG.
+type RawgProvider struct {
+	client RawgClient
+}
+
+// NewRawgProvider creates a provider backed by a RAWG client.
+func NewRawgProvider(client RawgClient) *RawgProvider {
+	return &RawgProvider{client: client}
+}
+
+func (p *RawgProvider) Source() string {
+	return rawg.SourceName
+}
+
+func (p *RawgProvider) SearchGames(ctx context.Context, term string, page int) (SearchResult, error) {
+	resp, err := p.client.SearchGames(ctx, term, page)
+	if err != nil {
+		return SearchResult{}, rawgError(err)
+	}
+	if resp == nil {
+		return SearchResult{Games: []models.Game{}}, nil
+	}
+	return SearchResult{
+		Games:   mappers.MapGamesJSONToModel(resp.Results),
+		Total:   int(resp.Count),
+		HasNext: resp.HasNext(),
+	}, nil
+}
+
+func (p *RawgProvider) GetGame(ctx context.Context, externalID string) (models.Game, error) {
+	detail, err := p.client.GetGame(ctx, externalID)
+	if errors.Is(err, rawg.ErrNotFound) {
+		return models.Game{}, models.ErrGameNotFound
+	}
+	if err != nil {
+		return models.Game{}, rawgError(err)
+	}
+	return mappers.MapGameDetailJSONToModel(*detail), nil
+}
+
+// rawgError marks the errors of RAWG refusing to be called as ErrUnavailable.
+func rawgError(err error) error {
+	if errors.Is(err, rawg.ErrQuotaExceeded) || errors.Is(err, rawg.ErrCircuitOpen) {
+		return fmt.Errorf("%w: %w", ErrUnavailable, err)
+	}
+	return err
+}
@@ -0,0 +1,149 @@
+package providers
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/melkdesousa/gamgo/dao/models"
+	"github.com/melkdesousa/gamgo/utils"
+)
+
+// StaticSourceName identifies the games of the static provider.
+const StaticSourceName = "static"
+
+// staticPageSize is the number of games of a search page of the static provider.
+const staticPageSize = 20
+
+// StaticGame is a game of the JSON file of the static provider.
+type StaticGame struct {
+	ID          string            `json:"id"`
+	Slug        string            `json:"slug"`
+	Title       string            `json:"title"`
+	Description string            `json:"description"`
+	Platforms   []string          `json:"platforms"`
+	Released    string            `json:"released"` // YYYY-MM-DD
+	Rating      float64           `json:"rating"`   // from 0 to 5
+	CoverImage  string            `json:"coverImage"`
+	Website     string            `json:"website"`
+	Genres      []models.Taxonomy `json:"genres"`
+	Developers  []models.Taxonomy `json:"developers"`
+	Publishers  []models.Taxonomy `json:"publishers"`
+}
+
+// StaticProvider serves a fixed catalog read from a JSON file holding an array of
+// StaticGame, such as a curated list of games missing from other providers.
+type StaticProvider struct {
+	games []models.Game
+	// titles holds the words of the normalized title of each game
+	titles [][]string
+}
+
+// NewStaticProvider creates a provider serving the games of a JSON file.
+func NewStaticProvider(path string) (*StaticProvider, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read static games file: %w", err)
+	}
+	var entries []StaticGame
+	if err := json.Unmarshal(content, &entries); err != nil {
+		return nil, fmt.Errorf("failed to decode static games file %s: %w", path, err)
+	}
+	provider := &StaticProvider{}
+	for _, entry := range entries {
+		game, err := entry.toModel()
+		if err != nil {
+			return nil, fmt.Errorf("invalid game %q in %s: %w", entry.ID, path, err)
+		}
+		provider.games = append(provider.games, game)
+	}
+	slices.SortStableFunc(provider.games, func(a, b models.Game) int {
+		return cmp.Compare(utils.NormalizeTitle(a.Title), utils.NormalizeTitle(b.Title))
+	})
+	provider.titles = make([][]string, len(provider.games))
+	for i, game := range provider.games {
+		provider.titles[i] = strings.Fields(utils.NormalizeTitle(game.Title))
+	}
+	return provider, nil
+}
+
+func (g StaticGame) toModel() (models.Game, error) {
+	if g.ID == "" || g.Title == "" {
+		return models.Game{}, fmt.Errorf("id and title are required")
+	}
+	var released time.Time
+	if g.Released != "" {
+		var err error
+		if released, err = time.Parse(time.DateOnly, g.Released); err != nil {
+			return models.Game{}, fmt.Errorf("invalid release date: %w", err)
+		}
+	}
+	platforms := g.Platforms
+	if platforms == nil {
+		platforms = []string{}
+	}
+	return models.Game{
+		ID:             uuid.NewString(),
+		Title:          g.Title,
+		Description:    g.Description,
+		ReleaseDate:    released,
+		Platforms:      platforms,
+		Rating:         int(g.Rating * 100),
+		ExternalID:     g.ID,
+		ExternalSource: StaticSourceName,
+		ExternalSlug:   g.Slug,
+		CoverImage:     g.CoverImage,
+		Website:        g.Website,
+		Genres:         g.Genres,
+		Developers:     g.Developers,
+		Publishers:     g.Publishers,
+	}, nil
+}
+
+func (p *StaticProvider) Source() string {
+	return StaticSourceName
+}
+
+// SearchGames returns the games, ordered by title, whose title has a word starting with
+// each word of term.
+func (p *StaticProvider) SearchGames(_ context.Context, term string, page int) (SearchResult, error) {
+	words := strings.Fields(utils.NormalizeTitle(term))
+	matches := []models.Game{}
+	for i, game := range p.games {
+		if matchesWords(p.titles[i], words) {
+			matches = append(matches, game)
+		}
+	}
+	start := min(max(page-1, 0)*staticPageSize, len(matches))
+	end := min(start+staticPageSize, len(matches))
+	return SearchResult{
+		Games:   slices.Clone(matches[start:end]),
+		Total:   len(matches),
+		HasNext: end < len(matches),
+	}, nil
+}
+
+func (p *StaticProvider) GetGame(_ context.Context, externalID string) (models.Game, error) {
+	for _, game := range p.games {
+		if game.ExternalID == externalID {
+			return game, nil
+		}
+	}
+	return models.Game{}, models.ErrGameNotFound
+}
+
+// matchesWords reports whether every word of words starts a word of title.
+func matchesWords(title, words []string) bool {
+	for _, word := range words {
+		if !slices.ContainsFunc(title, func(titleWord string) bool { return strings.HasPrefix(titleWord, word) }) {
+			return false
+		}
+	}
+	return true
+}
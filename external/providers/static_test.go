@@ -0,0 +1,75 @@
+package providers
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/melkdesousa/gamgo/dao/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStaticProvider(t *testing.T) {
+	ctx := context.Background()
+	provider, err := NewStaticProvider(filepath.Join("testdata", "static_games.json"))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	t.Run("SearchGamesByWordPrefixes", func(t *testing.T) {
+		result, err := provider.SearchGames(ctx, "hol kni", 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, result.Total)
+		assert.False(t, result.HasNext)
+		if assert.Len(t, result.Games, 2) {
+			assert.Equal(t, "Hollow Knight", result.Games[0].Title, "Games should be ordered by title")
+			assert.Equal(t, "Hollow Knight: Silksong", result.Games[1].Title)
+		}
+	})
+
+	t.Run("SearchGamesIgnoresAccents", func(t *testing.T) {
+		result, err := provider.SearchGames(ctx, "POKEMON", 1)
+
+		assert.NoError(t, err)
+		if assert.Len(t, result.Games, 1) {
+			assert.Equal(t, "Pokémon Red", result.Games[0].Title)
+		}
+	})
+
+	t.Run("SearchGamesPastLastPage", func(t *testing.T) {
+		result, err := provider.SearchGames(ctx, "hollow", 2)
+
+		assert.NoError(t, err)
+		assert.Empty(t, result.Games)
+		assert.Equal(t, 2, result.Total)
+	})
+
+	t.Run("GetGame", func(t *testing.T) {
+		game, err := provider.GetGame(ctx, "silksong")
+
+		assert.NoError(t, err)
+		assert.Equal(t, StaticSourceName, game.ExternalSource)
+		assert.Equal(t, "hollow-knight-silksong", game.ExternalSlug)
+		assert.Equal(t, time.Date(2025, 9, 4, 0, 0, 0, 0, time.UTC), game.ReleaseDate)
+		assert.Equal(t, 450, game.Rating)
+		assert.Equal(t, []models.Taxonomy{{Slug: "team-cherry", Name: "Team Cherry"}}, game.Developers)
+	})
+
+	t.Run("GetGameNotFound", func(t *testing.T) {
+		_, err := provider.GetGame(ctx, "missing")
+
+		assert.ErrorIs(t, err, models.ErrGameNotFound)
+	})
+
+	t.Run("InvalidFile", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "games.json")
+		assert.NoError(t, os.WriteFile(path, []byte(`[{"id": "undated", "title": "Undated", "released": "soon"}]`), 0o600))
+
+		_, err := NewStaticProvider(path)
+
+		assert.ErrorContains(t, err, "invalid release date")
+	})
+}
@@ -1,33 +1,236 @@
 package rawg
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"math/rand/v2"
 	"net/http"
 	"net/url"
+	"strings"
+	"time"
 
 	"github.com/melkdesousa/gamgo/config"
+	"github.com/melkdesousa/gamgo/utils"
 )
 
-type RawgAPI struct{}
+// SourceName identifies RAWG as the external source of imported games.
+const SourceName = "rawg"
 
-func NewRawgAPI() *RawgAPI {
-	return &RawgAPI{}
+// Config holds the settings of the RAWG client.
+type Config struct {
+	BaseURL string
+	// APIKeys are used in turns, keeping each of them within MonthlyBudget
+	APIKeys []string
+	// Timeout bounds each attempt of a request
+	Timeout time.Duration
+	// MaxRetries is the number of attempts after the first one for retryable failures
+	MaxRetries int
+	// BaseBackoff and MaxBackoff bound the jittered exponential delay between attempts.
+	// Requests are not retried when Retry-After asks to wait longer than MaxBackoff.
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+	// BreakerThreshold consecutive failed attempts open the circuit for BreakerCooldown
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+	// MonthlyBudget is the number of requests allowed per key and month, unlimited when
+	// zero. Keys past QuotaSoftPercent of it are used last, and requests are refused with
+	// ErrQuotaExceeded once every key is past QuotaHardPercent of it.
+	MonthlyBudget    int
+	QuotaSoftPercent int
+	QuotaHardPercent int
+	// Mode records the responses of RAWG to, or replays them from, FixturesDir. Replayed
+	// requests are not accounted.
+	Mode        Mode
+	FixturesDir string
 }
 
-func (api *RawgAPI) SearchGames(ctx context.Context, title string, page int) (*GameListResponse, error) {
-	baseURL := fmt.Sprintf("%s/api/games?key=%s&search=%s&page=%d", config.MustGetEnv("RAWG_BASE_URL"), config.MustGetEnv("RAWG_API_KEY"), url.QueryEscape(title), page)
-	log.Printf("Searching games with title: %s, page: %d", title, page)
-	resp, err := http.Get(baseURL)
+// ConfigFromEnv reads the client settings from the environment.
+func ConfigFromEnv() Config {
+	mode := Mode(config.GetEnvOrDefault("RAWG_MODE", string(ModeLive)))
+	return Config{
+		BaseURL:          config.MustGetEnv("RAWG_BASE_URL"),
+		APIKeys:          apiKeysFromEnv(mode != ModeReplay),
+		Timeout:          time.Duration(config.GetEnvOrDefault("RAWG_TIMEOUT_SECONDS", 10)) * time.Second,
+		MaxRetries:       config.GetEnvOrDefault("RAWG_MAX_RETRIES", 3),
+		BaseBackoff:      time.Duration(config.GetEnvOrDefault("RAWG_BACKOFF_MS", 200)) * time.Millisecond,
+		MaxBackoff:       time.Duration(config.GetEnvOrDefault("RAWG_MAX_BACKOFF_MS", 5000)) * time.Millisecond,
+		BreakerThreshold: config.GetEnvOrDefault("RAWG_BREAKER_THRESHOLD", 5),
+		BreakerCooldown:  time.Duration(config.GetEnvOrDefault("RAWG_BREAKER_COOLDOWN_SECONDS", 30)) * time.Second,
+		MonthlyBudget:    config.GetEnvOrDefault("RAWG_MONTHLY_BUDGET", 20000),
+		QuotaSoftPercent: config.GetEnvOrDefault("RAWG_QUOTA_SOFT_PERCENT", 80),
+		QuotaHardPercent: config.GetEnvOrDefault("RAWG_QUOTA_HARD_PERCENT", 100),
+		Mode:             mode,
+		FixturesDir:      config.GetEnvOrDefault("RAWG_FIXTURES_DIR", "testdata/rawg"),
+	}
+}
+
+// apiKeysFromEnv reads the comma separated RAWG_API_KEYS, falling back to RAWG_API_KEY,
+// which is only optional when the keys are not required.
+func apiKeysFromEnv(required bool) []string {
+	var keys []string
+	for _, key := range strings.Split(config.GetEnvOrDefault("RAWG_API_KEYS", ""), ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 && (required || config.GetEnvOrDefault("RAWG_API_KEY", "") != "") {
+		keys = append(keys, config.MustGetEnv("RAWG_API_KEY"))
+	}
+	return keys
+}
+
+type RawgAPI struct {
+	config     Config
+	httpClient *http.Client
+	breaker    *utils.CircuitBreaker
+	quota      *Quota
+}
+
+// NewRawgAPI creates a client configured from the environment, accounting its requests
+// in usage.
+func NewRawgAPI(usage UsageStore) *RawgAPI {
+	return NewRawgAPIWithConfig(ConfigFromEnv(), nil, usage)
+}
+
+// NewRawgAPIWithConfig creates a client sending requests through httpClient, or through
+// a new client when it is nil. Requests are not accounted when usage is nil.
+func NewRawgAPIWithConfig(cfg Config, httpClient *http.Client, usage UsageStore) *RawgAPI {
+	if httpClient == nil {
+		httpClient = &http.Client{}
+	}
+	switch cfg.Mode {
+	case ModeRecord:
+		httpClient = withTransport(httpClient, &recorder{dir: cfg.FixturesDir, next: cmp.Or[http.RoundTripper](httpClient.Transport, http.DefaultTransport)})
+	case ModeReplay:
+		httpClient = withTransport(httpClient, &replayer{dir: cfg.FixturesDir})
+		usage = nil
+		if len(cfg.APIKeys) == 0 {
+			cfg.APIKeys = []string{"replay"}
+		}
+	}
+	return &RawgAPI{
+		config:     cfg,
+		httpClient: httpClient,
+		breaker:    utils.NewCircuitBreaker(cfg.BreakerThreshold, cfg.BreakerCooldown),
+		quota:      NewQuota(cfg.APIKeys, cfg.MonthlyBudget, cfg.QuotaSoftPercent, cfg.QuotaHardPercent, usage),
+	}
+}
+
+// withTransport returns a copy of client sending requests through transport.
+func withTransport(client *http.Client, transport http.RoundTripper) *http.Client {
+	copied := *client
+	copied.Transport = transport
+	return &copied
+}
+
+// QuotaUsage reports the consumption of each API key in the current month.
+func (api *RawgAPI) QuotaUsage(ctx context.Context) ([]KeyUsage, error) {
+	return api.quota.Usage(ctx)
+}
+
+// get sends a GET request to RAWG and decodes the JSON response into response. Failures
+// that may be transient are retried with jittered exponential backoff, and requests are
+// rejected with ErrCircuitOpen without calling RAWG while it is considered down. Every
+// attempt counts towards the quota of the key it is sent with.
+func (api *RawgAPI) get(ctx context.Context, path string, query url.Values, response any) error {
+	if query == nil {
+		query = url.Values{}
+	}
+	for attempt := 0; ; attempt++ {
+		if err := api.breaker.Allow(); err != nil {
+			return ErrCircuitOpen
+		}
+		key, err := api.quota.Acquire(ctx)
+		if err != nil {
+			api.breaker.Abort()
+			return err
+		}
+		query.Set("key", key)
+		endpoint := api.config.BaseURL + path + "?" + query.Encode()
+		var retryAfter time.Duration
+		retryAfter, err = api.attempt(ctx, endpoint, path, response)
+		if err == nil || !retryable(ctx, err) || attempt >= api.config.MaxRetries || retryAfter > api.config.MaxBackoff {
+			return err
+		}
+		delay := api.backoff(attempt, retryAfter)
+		log.Printf("Retrying %s in %v after attempt %d failed: %v", path, delay, attempt+1, err)
+		select {
+		case <-ctx.Done():
+			return errors.Join(err, ctx.Err())
+		case <-time.After(delay):
+		}
+	}
+}
+
+// attempt sends a single request, reporting its outcome to the circuit breaker. It
+// returns the delay requested by RAWG before retrying, if any.
+func (api *RawgAPI) attempt(ctx context.Context, endpoint, path string, response any) (time.Duration, error) {
+	parent := ctx
+	if api.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, api.config.Timeout)
+		defer cancel()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		api.breaker.Abort() // the request never left
+		return 0, err
+	}
+	resp, err := api.httpClient.Do(req)
 	if err != nil {
-		return nil, err
+		if parent.Err() != nil || errors.Is(err, ErrNotRecorded) {
+			api.breaker.Abort() // cancelled by the caller or not replayable, RAWG is not at fault
+		} else {
+			api.breaker.Failure()
+		}
+		return 0, fmt.Errorf("rawg: request to %s failed: %w", path, err)
 	}
 	defer resp.Body.Close()
-	var response GameListResponse
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return nil, err
+	if resp.StatusCode != http.StatusOK {
+		apiErr := newAPIError(resp, path)
+		if errors.Is(apiErr, ErrServer) {
+			api.breaker.Failure()
+		} else {
+			api.breaker.Success()
+		}
+		return apiErr.RetryAfter, apiErr
+	}
+	api.breaker.Success()
+	if err := json.NewDecoder(resp.Body).Decode(response); err != nil {
+		return 0, fmt.Errorf("rawg: failed to decode response of %s: %w", path, err)
+	}
+	return 0, nil
+}
+
+// retryable reports whether a failed attempt may succeed when sent again.
+func retryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil || errors.Is(err, ErrNotRecorded) {
+		return false
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.retryable()
+	}
+	// network failures and attempt timeouts, but not undecodable responses
+	var decodeErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	return !errors.As(err, &decodeErr) && !errors.As(err, &typeErr)
+}
+
+// backoff returns the delay before the attempt following attempt, honoring the delay
+// requested by RAWG when there is one.
+func (api *RawgAPI) backoff(attempt int, retryAfter time.Duration) time.Duration {
+	if retryAfter > 0 {
+		return retryAfter
+	}
+	ceiling := min(api.config.BaseBackoff<<attempt, api.config.MaxBackoff)
+	if ceiling <= 0 {
+		return 0
 	}
-	return &response, nil
+	// full jitter spreads the retries of concurrent requests
+	return rand.N(ceiling) + 1
 }
@@ -0,0 +1,259 @@
+package rawg
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRawgAPI(t *testing.T) {
+	testConfig := func(baseURL string) Config {
+		return Config{
+			BaseURL:          baseURL,
+			APIKeys:          []string{"test-key"},
+			Timeout:          time.Second,
+			MaxRetries:       2,
+			BaseBackoff:      time.Millisecond,
+			MaxBackoff:       10 * time.Millisecond,
+			BreakerThreshold: 5,
+			BreakerCooldown:  time.Minute,
+		}
+	}
+	// newServer passes each request to respond along with its 1-based attempt number
+	newServer := func(t *testing.T, respond func(w http.ResponseWriter, r *http.Request, attempt int32)) (*httptest.Server, *atomic.Int32) {
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			respond(w, r, calls.Add(1))
+		}))
+		t.Cleanup(server.Close)
+		return server, &calls
+	}
+	const gameListBody = `{"count": 1, "results": [{"id": 3498, "name": "Grand Theft Auto V"}]}`
+
+	t.Run("Success", func(t *testing.T) {
+		// Setup
+		server, calls := newServer(t, func(w http.ResponseWriter, r *http.Request, _ int32) {
+			assert.Equal(t, "/api/games", r.URL.Path)
+			assert.Equal(t, "test-key", r.URL.Query().Get("key"))
+			assert.Equal(t, "gta", r.URL.Query().Get("search"))
+			assert.Equal(t, "2", r.URL.Query().Get("page"))
+			w.Write([]byte(gameListBody))
+		})
+		api := NewRawgAPIWithConfig(testConfig(server.URL), nil, nil)
+
+		// Call the client
+		response, err := api.SearchGames(context.Background(), "gta", 2)
+
+		// Assertions
+		assert.NoError(t, err)
+		if assert.NotNil(t, response) && assert.Len(t, response.Results, 1) {
+			assert.Equal(t, "Grand Theft Auto V", response.Results[0].Name)
+		}
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("RetriesServerErrors", func(t *testing.T) {
+		// Setup
+		server, calls := newServer(t, func(w http.ResponseWriter, _ *http.Request, attempt int32) {
+			if attempt < 3 {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			w.Write([]byte(gameListBody))
+		})
+		api := NewRawgAPIWithConfig(testConfig(server.URL), nil, nil)
+
+		// Call the client
+		response, err := api.SearchGames(context.Background(), "gta", 1)
+
+		// Assertions
+		assert.NoError(t, err)
+		if assert.NotNil(t, response) {
+			assert.Len(t, response.Results, 1)
+		}
+		assert.Equal(t, int32(3), calls.Load())
+	})
+
+	t.Run("GivesUpAfterMaxRetries", func(t *testing.T) {
+		// Setup
+		server, calls := newServer(t, func(w http.ResponseWriter, _ *http.Request, _ int32) {
+			w.WriteHeader(http.StatusInternalServerError)
+		})
+		api := NewRawgAPIWithConfig(testConfig(server.URL), nil, nil)
+
+		// Call the client
+		_, err := api.SearchGames(context.Background(), "gta", 1)
+
+		// Assertions
+		assert.ErrorIs(t, err, ErrServer)
+		var apiErr *APIError
+		if assert.ErrorAs(t, err, &apiErr) {
+			assert.Equal(t, http.StatusInternalServerError, apiErr.StatusCode)
+		}
+		assert.Equal(t, int32(3), calls.Load(), "The first attempt and two retries should be sent")
+	})
+
+	t.Run("ClientErrorsAreNotRetried", func(t *testing.T) {
+		cases := []struct {
+			status int
+			err    error
+		}{
+			{http.StatusUnauthorized, ErrUnauthorized},
+			{http.StatusNotFound, ErrNotFound},
+		}
+		for _, tc := range cases {
+			// Setup
+			server, calls := newServer(t, func(w http.ResponseWriter, _ *http.Request, _ int32) {
+				w.WriteHeader(tc.status)
+			})
+			api := NewRawgAPIWithConfig(testConfig(server.URL), nil, nil)
+
+			// Call the client
+			_, err := api.GetGame(context.Background(), "3498")
+
+			// Assertions
+			assert.ErrorIs(t, err, tc.err)
+			assert.Equal(t, int32(1), calls.Load())
+		}
+	})
+
+	t.Run("HonorsRetryAfter", func(t *testing.T) {
+		// Setup
+		var firstAt, secondAt time.Time
+		server, calls := newServer(t, func(w http.ResponseWriter, _ *http.Request, attempt int32) {
+			if attempt == 1 {
+				firstAt = time.Now()
+				w.Header().Set("Retry-After", "1")
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			secondAt = time.Now()
+			w.Write([]byte(gameListBody))
+		})
+		cfg := testConfig(server.URL)
+		cfg.MaxBackoff = 2 * time.Second
+		api := NewRawgAPIWithConfig(cfg, nil, nil)
+
+		// Call the client
+		_, err := api.SearchGames(context.Background(), "gta", 1)
+
+		// Assertions
+		assert.NoError(t, err)
+		assert.Equal(t, int32(2), calls.Load())
+		assert.GreaterOrEqual(t, secondAt.Sub(firstAt), time.Second)
+	})
+
+	t.Run("RetryAfterLongerThanMaxBackoff", func(t *testing.T) {
+		// Setup
+		server, calls := newServer(t, func(w http.ResponseWriter, _ *http.Request, _ int32) {
+			w.Header().Set("Retry-After", "60")
+			w.WriteHeader(http.StatusTooManyRequests)
+		})
+		api := NewRawgAPIWithConfig(testConfig(server.URL), nil, nil)
+
+		// Call the client
+		_, err := api.SearchGames(context.Background(), "gta", 1)
+
+		// Assertions
+		assert.ErrorIs(t, err, ErrRateLimited)
+		var apiErr *APIError
+		if assert.ErrorAs(t, err, &apiErr) {
+			assert.Equal(t, time.Minute, apiErr.RetryAfter)
+		}
+		assert.Equal(t, int32(1), calls.Load(), "The request should not be retried before RAWG allows it")
+	})
+
+	t.Run("AttemptTimeout", func(t *testing.T) {
+		// Setup
+		server, calls := newServer(t, func(w http.ResponseWriter, r *http.Request, attempt int32) {
+			if attempt == 1 {
+				<-r.Context().Done()
+				return
+			}
+			w.Write([]byte(gameListBody))
+		})
+		cfg := testConfig(server.URL)
+		cfg.Timeout = 50 * time.Millisecond
+		api := NewRawgAPIWithConfig(cfg, nil, nil)
+
+		// Call the client
+		_, err := api.SearchGames(context.Background(), "gta", 1)
+
+		// Assertions
+		assert.NoError(t, err, "A timed out attempt should be retried")
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("CircuitOpens", func(t *testing.T) {
+		// Setup
+		server, calls := newServer(t, func(w http.ResponseWriter, _ *http.Request, _ int32) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		})
+		cfg := testConfig(server.URL)
+		cfg.MaxRetries = 0
+		cfg.BreakerThreshold = 2
+		api := NewRawgAPIWithConfig(cfg, nil, nil)
+
+		// Call the client
+		_, firstErr := api.SearchGames(context.Background(), "gta", 1)
+		_, secondErr := api.SearchGames(context.Background(), "gta", 1)
+		_, thirdErr := api.SearchGames(context.Background(), "gta", 1)
+
+		// Assertions
+		assert.ErrorIs(t, firstErr, ErrServer)
+		assert.ErrorIs(t, secondErr, ErrServer)
+		assert.ErrorIs(t, thirdErr, ErrCircuitOpen)
+		assert.Equal(t, int32(2), calls.Load(), "RAWG should not be called while the circuit is open")
+	})
+
+	t.Run("CanceledContext", func(t *testing.T) {
+		// Setup
+		server, calls := newServer(t, func(w http.ResponseWriter, r *http.Request, _ int32) {
+			<-r.Context().Done()
+		})
+		cfg := testConfig(server.URL)
+		cfg.BreakerThreshold = 1
+		api := NewRawgAPIWithConfig(cfg, nil, nil)
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		// Call the client
+		_, err := api.SearchGames(ctx, "gta", 1)
+
+		// Assertions
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, int32(1), calls.Load(), "A request cancelled by the caller should not be retried")
+		assert.False(t, api.breaker.Open(), "A request cancelled by the caller should not open the circuit")
+	})
+
+	t.Run("QuotaExceeded", func(t *testing.T) {
+		// Setup
+		var usedKeys []string
+		server, calls := newServer(t, func(w http.ResponseWriter, r *http.Request, _ int32) {
+			usedKeys = append(usedKeys, r.URL.Query().Get("key"))
+			w.Write([]byte(gameListBody))
+		})
+		cfg := testConfig(server.URL)
+		cfg.APIKeys = []string{"key-a", "key-b"}
+		cfg.MonthlyBudget = 1
+		api := NewRawgAPIWithConfig(cfg, nil, newMemoryUsageStore())
+
+		// Call the client
+		_, firstErr := api.SearchGames(context.Background(), "gta", 1)
+		_, secondErr := api.SearchGames(context.Background(), "gta", 1)
+		_, thirdErr := api.SearchGames(context.Background(), "gta", 1)
+
+		// Assertions
+		assert.NoError(t, firstErr)
+		assert.NoError(t, secondErr)
+		assert.ErrorIs(t, thirdErr, ErrQuotaExceeded)
+		assert.Equal(t, []string{"key-a", "key-b"}, usedKeys)
+		assert.Equal(t, int32(2), calls.Load(), "RAWG should not be called once every key is exhausted")
+		assert.False(t, api.breaker.Open())
+	})
+}
@@ -0,0 +1,92 @@
+package rawg
+
+import (
+	"context"
+	"log"
+	"net/url"
+)
+
+// platformPageSize is the page size of the platform listings, which hold about 50 platforms.
+const platformPageSize = 40
+
+// ListPlatforms fetches a page of the platforms known by RAWG.
+func (api *RawgAPI) ListPlatforms(ctx context.Context, page int) (*PlatformListResponse, error) {
+	log.Printf("Fetching platforms, page: %d", page)
+	return getPage[Genre](ctx, api, "/api/platforms", (&ListOptions{Page: page, PageSize: platformPageSize}).values())
+}
+
+// ListParentPlatforms fetches a page of the platform families known by RAWG along with their platforms.
+func (api *RawgAPI) ListParentPlatforms(ctx context.Context, page int) (*ParentPlatformListResponse, error) {
+	log.Printf("Fetching parent platforms, page: %d", page)
+	return getPage[ParentPlatform](ctx, api, "/api/platforms/lists/parents", (&ListOptions{Page: page, PageSize: platformPageSize}).values())
+}
+
+// GetPlatform fetches a platform by its RAWG id.
+func (api *RawgAPI) GetPlatform(ctx context.Context, id string) (*PlatformDetail, error) {
+	return getOne[PlatformDetail](ctx, api, "/api/platforms/"+url.PathEscape(id))
+}
+
+// ListGenres fetches a page of the genres.
+func (api *RawgAPI) ListGenres(ctx context.Context, opts *ListOptions) (*Page[Genre], error) {
+	return getPage[Genre](ctx, api, "/api/genres", opts.values())
+}
+
+// GetGenre fetches a genre by its RAWG id or slug.
+func (api *RawgAPI) GetGenre(ctx context.Context, id string) (*EntityDetail, error) {
+	return getOne[EntityDetail](ctx, api, "/api/genres/"+url.PathEscape(id))
+}
+
+// ListTags fetches a page of the tags.
+func (api *RawgAPI) ListTags(ctx context.Context, opts *ListOptions) (*Page[Tag], error) {
+	return getPage[Tag](ctx, api, "/api/tags", opts.values())
+}
+
+// GetTag fetches a tag by its RAWG id or slug.
+func (api *RawgAPI) GetTag(ctx context.Context, id string) (*EntityDetail, error) {
+	return getOne[EntityDetail](ctx, api, "/api/tags/"+url.PathEscape(id))
+}
+
+// ListDevelopers fetches a page of the developers.
+func (api *RawgAPI) ListDevelopers(ctx context.Context, opts *ListOptions) (*Page[Genre], error) {
+	return getPage[Genre](ctx, api, "/api/developers", opts.values())
+}
+
+// GetDeveloper fetches a developer by its RAWG id or slug.
+func (api *RawgAPI) GetDeveloper(ctx context.Context, id string) (*EntityDetail, error) {
+	return getOne[EntityDetail](ctx, api, "/api/developers/"+url.PathEscape(id))
+}
+
+// ListPublishers fetches a page of the publishers.
+func (api *RawgAPI) ListPublishers(ctx context.Context, opts *ListOptions) (*Page[Genre], error) {
+	return getPage[Genre](ctx, api, "/api/publishers", opts.values())
+}
+
+// GetPublisher fetches a publisher by its RAWG id or slug.
+func (api *RawgAPI) GetPublisher(ctx context.Context, id string) (*EntityDetail, error) {
+	return getOne[EntityDetail](ctx, api, "/api/publishers/"+url.PathEscape(id))
+}
+
+// ListStores fetches a page of the stores.
+func (api *RawgAPI) ListStores(ctx context.Context, opts *ListOptions) (*Page[StoreDetail], error) {
+	return getPage[StoreDetail](ctx, api, "/api/stores", opts.values())
+}
+
+// GetStore fetches a store by its RAWG id.
+func (api *RawgAPI) GetStore(ctx context.Context, id string) (*StoreDetail, error) {
+	return getOne[StoreDetail](ctx, api, "/api/stores/"+url.PathEscape(id))
+}
+
+// ListCreators fetches a page of the people who worked on games.
+func (api *RawgAPI) ListCreators(ctx context.Context, opts *ListOptions) (*Page[Creator], error) {
+	return getPage[Creator](ctx, api, "/api/creators", opts.values())
+}
+
+// GetCreator fetches a creator by its RAWG id or slug.
+func (api *RawgAPI) GetCreator(ctx context.Context, id string) (*CreatorDetail, error) {
+	return getOne[CreatorDetail](ctx, api, "/api/creators/"+url.PathEscape(id))
+}
+
+// ListCreatorRoles fetches a page of the positions creators can hold, such as composer.
+func (api *RawgAPI) ListCreatorRoles(ctx context.Context, opts *ListOptions) (*Page[Genre], error) {
+	return getPage[Genre](ctx, api, "/api/creator-roles", opts.values())
+}
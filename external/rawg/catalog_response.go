@@ -0,0 +1,35 @@
+package rawg
+
+// EntityDetail is a genre, tag, developer or publisher returned by its detail endpoint.
+type EntityDetail struct {
+	Genre
+	Description string `json:"description"` // HTML
+}
+
+// StoreDetail is a store of /stores, with its description only set by /stores/{id}.
+type StoreDetail struct {
+	Genre
+	Domain      string `json:"domain"`
+	Description string `json:"description"` // HTML
+}
+
+// Creator is a person who worked on games, such as a designer or a composer.
+type Creator struct {
+	ID              int64   `json:"id"`
+	Name            string  `json:"name"`
+	Slug            string  `json:"slug"`
+	Image           string  `json:"image"`
+	ImageBackground string  `json:"image_background"`
+	GamesCount      int64   `json:"games_count"`
+	Positions       []Genre `json:"positions"`
+}
+
+// CreatorDetail is a creator returned by /creators/{id}.
+type CreatorDetail struct {
+	Creator
+	Description  string `json:"description"` // HTML
+	ReviewsCount int64  `json:"reviews_count"`
+	Rating       string `json:"rating"`
+	RatingTop    int64  `json:"rating_top"`
+	Updated      string `json:"updated"`
+}
@@ -0,0 +1,78 @@
+package rawg
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/melkdesousa/gamgo/utils"
+)
+
+var (
+	// ErrNotFound is returned when RAWG has no game or other resource for the requested id.
+	ErrNotFound = errors.New("rawg: not found")
+	// ErrUnauthorized is returned when RAWG rejects the API key.
+	ErrUnauthorized = errors.New("rawg: unauthorized")
+	// ErrRateLimited is returned when RAWG keeps throttling requests after every retry.
+	ErrRateLimited = errors.New("rawg: rate limited")
+	// ErrServer is returned when RAWG keeps failing after every retry.
+	ErrServer = errors.New("rawg: server error")
+	// ErrCircuitOpen is returned without calling RAWG while it is considered down.
+	ErrCircuitOpen = fmt.Errorf("rawg: %w", utils.ErrCircuitOpen)
+)
+
+// APIError describes an unsuccessful response of RAWG. It wraps the sentinel error of
+// its kind, so callers can check it with errors.Is.
+type APIError struct {
+	StatusCode int
+	// RetryAfter is the delay requested by RAWG through the Retry-After header, if any
+	RetryAfter time.Duration
+	Path       string
+	kind       error
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%v: status %d fetching %s", e.kind, e.StatusCode, e.Path)
+}
+
+func (e *APIError) Unwrap() error {
+	return e.kind
+}
+
+// newAPIError classifies an unsuccessful response.
+func newAPIError(resp *http.Response, path string) *APIError {
+	err := &APIError{StatusCode: resp.StatusCode, Path: path, RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"))}
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		err.kind = ErrUnauthorized
+	case resp.StatusCode == http.StatusNotFound:
+		err.kind = ErrNotFound
+	case resp.StatusCode == http.StatusTooManyRequests:
+		err.kind = ErrRateLimited
+	case resp.StatusCode >= http.StatusInternalServerError:
+		err.kind = ErrServer
+	default:
+		err.kind = errors.New("rawg: unexpected response")
+	}
+	return err
+}
+
+// retryable reports whether the request may succeed when sent again.
+func (e *APIError) retryable() bool {
+	return errors.Is(e.kind, ErrRateLimited) || errors.Is(e.kind, ErrServer)
+}
+
+// parseRetryAfter reads a Retry-After header given either in seconds or as an HTTP date.
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := time.ParseDuration(value + "s"); err == nil && seconds > 0 {
+		return seconds
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0)
+	}
+	return 0
+}
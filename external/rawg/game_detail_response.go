@@ -0,0 +1,21 @@
+package rawg
+
+// GameDetailResponse is the detail of a single game returned by /games/{id}.
+type GameDetailResponse struct {
+	ID              int        `json:"id"`
+	Slug            string     `json:"slug"`
+	Name            string     `json:"name"`
+	Description     string     `json:"description"`     // HTML
+	DescriptionRaw  string     `json:"description_raw"` // plain text, not always present
+	Metacritic      int        `json:"metacritic"`
+	Released        string     `json:"released"`
+	BackgroundImage string     `json:"background_image"`
+	Website         string     `json:"website"`
+	Rating          float64    `json:"rating"`
+	Playtime        int        `json:"playtime"` // in hours
+	Platforms       []Platform `json:"platforms"`
+	Genres          []Genre    `json:"genres"`
+	Tags            []Tag      `json:"tags"`
+	Developers      []Genre    `json:"developers"`
+	Publishers      []Genre    `json:"publishers"`
+}
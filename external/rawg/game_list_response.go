@@ -1,45 +1,43 @@
 package rawg
 
-type GameListResponse struct {
-	Count         int64       `json:"count"`
-	Next          interface{} `json:"next"`
-	Previous      interface{} `json:"previous"`
-	Results       []Result    `json:"results"`
-	UserPlatforms bool        `json:"user_platforms"`
-}
+import "encoding/json"
+
+// GameListResponse is a page of /games and of the listings of related games.
+type GameListResponse = Page[Result]
 
+// Result is a game as listed by RAWG.
 type Result struct {
-	Slug            string     `json:"slug"`
-	Name            string     `json:"name"`
-	Platforms       []Platform `json:"platforms"`
-	ID              int        `json:"id"`
-	Released        string     `json:"released"`
-	Rating          float64    `json:"rating"`
-	BackgroundImage string     `json:"background_image"`
-	// Playtime         int64             `json:"playtime"`
-	// Stores           []Store           `json:"stores"`
-	// Tba              bool              `json:"tba"`
-	// RatingTop        int64             `json:"rating_top"`
-	// Ratings          []Rating          `json:"ratings"`
-	// RatingsCount     int64             `json:"ratings_count"`
-	// ReviewsTextCount int64             `json:"reviews_text_count"`
-	// Added            int64             `json:"added"`
-	// AddedByStatus    *AddedByStatus    `json:"added_by_status"`
-	// Metacritic       *int64            `json:"metacritic"`
-	// SuggestionsCount int64             `json:"suggestions_count"`
-	// Updated          time.Time         `json:"updated"`
-	// Score            string            `json:"score"`
-	// Clip             interface{}       `json:"clip"`
-	// Tags             []Tag             `json:"tags"`
-	// EsrbRating       interface{}       `json:"esrb_rating"`
-	// UserGame         interface{}       `json:"user_game"`
-	// ReviewsCount     int64             `json:"reviews_count"`
-	// SaturatedColor   string            `json:"saturated_color"`
-	// DominantColor    string            `json:"dominant_color"`
-	// ShortScreenshots []ShortScreenshot `json:"short_screenshots"`
-	// ParentPlatforms  []Platform        `json:"parent_platforms"`
-	// Genres           []Genre           `json:"genres"`
-	// CommunityRating  *int64            `json:"community_rating,omitempty"`
+	Slug             string            `json:"slug"`
+	Name             string            `json:"name"`
+	Platforms        []Platform        `json:"platforms"`
+	ID               int               `json:"id"`
+	Released         string            `json:"released"`
+	Rating           float64           `json:"rating"`
+	BackgroundImage  string            `json:"background_image"`
+	Genres           []Genre           `json:"genres"`
+	Tags             []Tag             `json:"tags"`
+	Playtime         int64             `json:"playtime"`
+	Stores           []Store           `json:"stores"`
+	Tba              bool              `json:"tba"`
+	RatingTop        int64             `json:"rating_top"`
+	Ratings          []Rating          `json:"ratings"`
+	RatingsCount     int64             `json:"ratings_count"`
+	ReviewsTextCount int64             `json:"reviews_text_count"`
+	Added            int64             `json:"added"`
+	AddedByStatus    *AddedByStatus    `json:"added_by_status"`
+	Metacritic       *int64            `json:"metacritic"`
+	SuggestionsCount int64             `json:"suggestions_count"`
+	Updated          string            `json:"updated"` // such as 2019-11-10T15:13:56, without a time zone
+	Score            string            `json:"score"`   // search relevance, only set when searching
+	Clip             json.RawMessage   `json:"clip"`
+	EsrbRating       *Genre            `json:"esrb_rating"`
+	UserGame         json.RawMessage   `json:"user_game"`
+	ReviewsCount     int64             `json:"reviews_count"`
+	SaturatedColor   string            `json:"saturated_color"`
+	DominantColor    string            `json:"dominant_color"`
+	ShortScreenshots []ShortScreenshot `json:"short_screenshots"`
+	ParentPlatforms  []Platform        `json:"parent_platforms"`
+	CommunityRating  *int64            `json:"community_rating,omitempty"`
 }
 
 type AddedByStatus struct {
@@ -51,14 +49,27 @@ type AddedByStatus struct {
 	Playing *int64 `json:"playing,omitempty"`
 }
 
+// Genre is the shape shared by most named RAWG entities, such as genres, platforms,
+// stores, developers and publishers.
 type Genre struct {
-	Name string `json:"name"`
-	// ID   int64  `json:"id"`
-	// Slug string `json:"slug"`
+	Name            string `json:"name"`
+	ID              int64  `json:"id"`
+	Slug            string `json:"slug"`
+	GamesCount      int64  `json:"games_count"`
+	ImageBackground string `json:"image_background"`
 }
 
+// Platform is a platform a game was released on.
 type Platform struct {
-	Platform Genre `json:"platform"`
+	Platform     Genre         `json:"platform"`
+	ReleasedAt   string        `json:"released_at"`
+	Requirements *Requirements `json:"requirements"`
+}
+
+// Requirements are the system requirements of a game on a platform, as text.
+type Requirements struct {
+	Minimum     string `json:"minimum"`
+	Recommended string `json:"recommended"`
 }
 
 type Rating struct {
@@ -73,7 +84,9 @@ type ShortScreenshot struct {
 	Image string `json:"image"`
 }
 
+// Store is a store a game is sold at.
 type Store struct {
+	ID    int64 `json:"id"`
 	Store Genre `json:"store"`
 }
 
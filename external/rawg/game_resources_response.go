@@ -0,0 +1,41 @@
+package rawg
+
+// Screenshot is a screenshot of a game.
+type Screenshot struct {
+	ID     int64  `json:"id"`
+	Image  string `json:"image"`
+	Hidden bool   `json:"hidden"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
+// GameStore links a game to the page selling it at a store.
+type GameStore struct {
+	ID      int64  `json:"id"`
+	GameID  int64  `json:"game_id"`
+	StoreID int64  `json:"store_id"`
+	URL     string `json:"url"`
+}
+
+// Achievement is an achievement of a game. Percent is the share of players who got it.
+type Achievement struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Image       string `json:"image"`
+	Percent     string `json:"percent"`
+}
+
+// Movie is a trailer or gameplay video of a game.
+type Movie struct {
+	ID      int64     `json:"id"`
+	Name    string    `json:"name"`
+	Preview string    `json:"preview"`
+	Data    MovieData `json:"data"`
+}
+
+// MovieData holds the URLs of a movie by quality.
+type MovieData struct {
+	Low string `json:"480"`
+	Max string `json:"max"`
+}
@@ -0,0 +1,96 @@
+package rawg
+
+import (
+	"context"
+	"log"
+	"net/url"
+)
+
+// SearchGames fetches a page of the games matching title, ranked by relevance.
+func (api *RawgAPI) SearchGames(ctx context.Context, title string, page int) (*GameListResponse, error) {
+	log.Printf("Searching games with title: %s, page: %d", title, page)
+	return api.ListGames(ctx, &GamesOptions{ListOptions: ListOptions{Page: page}, Search: title})
+}
+
+// ListGames fetches a page of the games matching opts.
+func (api *RawgAPI) ListGames(ctx context.Context, opts *GamesOptions) (*GameListResponse, error) {
+	return getPage[Result](ctx, api, "/api/games", opts.values())
+}
+
+// GetGame fetches the details of a game by its RAWG id or slug.
+func (api *RawgAPI) GetGame(ctx context.Context, id string) (*GameDetailResponse, error) {
+	log.Printf("Fetching game details for id: %s", id)
+	return getOne[GameDetailResponse](ctx, api, gamePath(id, ""))
+}
+
+// ListGameAdditions fetches a page of the DLCs, editions and other additions of a game.
+func (api *RawgAPI) ListGameAdditions(ctx context.Context, id string, opts *ListOptions) (*GameListResponse, error) {
+	return getPage[Result](ctx, api, gamePath(id, "additions"), opts.values())
+}
+
+// ListGameSeries fetches a page of the other games of the series a game belongs to.
+func (api *RawgAPI) ListGameSeries(ctx context.Context, id string, opts *ListOptions) (*GameListResponse, error) {
+	return getPage[Result](ctx, api, gamePath(id, "game-series"), opts.values())
+}
+
+// ListParentGames fetches a page of the games an addition belongs to.
+func (api *RawgAPI) ListParentGames(ctx context.Context, id string, opts *ListOptions) (*GameListResponse, error) {
+	return getPage[Result](ctx, api, gamePath(id, "parent-games"), opts.values())
+}
+
+// ListSuggestedGames fetches a page of the games similar to a game.
+func (api *RawgAPI) ListSuggestedGames(ctx context.Context, id string, opts *ListOptions) (*GameListResponse, error) {
+	return getPage[Result](ctx, api, gamePath(id, "suggested"), opts.values())
+}
+
+// ListGameScreenshots fetches a page of the screenshots of a game.
+func (api *RawgAPI) ListGameScreenshots(ctx context.Context, id string, opts *ListOptions) (*Page[Screenshot], error) {
+	return getPage[Screenshot](ctx, api, gamePath(id, "screenshots"), opts.values())
+}
+
+// ListGameStores fetches a page of the store pages selling a game.
+func (api *RawgAPI) ListGameStores(ctx context.Context, id string, opts *ListOptions) (*Page[GameStore], error) {
+	return getPage[GameStore](ctx, api, gamePath(id, "stores"), opts.values())
+}
+
+// ListGameAchievements fetches a page of the achievements of a game.
+func (api *RawgAPI) ListGameAchievements(ctx context.Context, id string, opts *ListOptions) (*Page[Achievement], error) {
+	return getPage[Achievement](ctx, api, gamePath(id, "achievements"), opts.values())
+}
+
+// ListGameMovies fetches a page of the trailers and videos of a game.
+func (api *RawgAPI) ListGameMovies(ctx context.Context, id string, opts *ListOptions) (*Page[Movie], error) {
+	return getPage[Movie](ctx, api, gamePath(id, "movies"), opts.values())
+}
+
+// ListGameDevelopmentTeam fetches a page of the creators who worked on a game.
+func (api *RawgAPI) ListGameDevelopmentTeam(ctx context.Context, id string, opts *ListOptions) (*Page[Creator], error) {
+	return getPage[Creator](ctx, api, gamePath(id, "development-team"), opts.values())
+}
+
+// gamePath is the path of a game by its RAWG id or slug, or of one of its resources.
+func gamePath(id, resource string) string {
+	path := "/api/games/" + url.PathEscape(id)
+	if resource != "" {
+		path += "/" + resource
+	}
+	return path
+}
+
+// getPage fetches a page of a listing.
+func getPage[T any](ctx context.Context, api *RawgAPI, path string, query url.Values) (*Page[T], error) {
+	var page Page[T]
+	if err := api.get(ctx, path, query, &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// getOne fetches a single resource.
+func getOne[T any](ctx context.Context, api *RawgAPI, path string) (*T, error) {
+	var response T
+	if err := api.get(ctx, path, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
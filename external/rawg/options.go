@@ -0,0 +1,116 @@
+package rawg
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DateRange is an inclusive range of days.
+type DateRange struct {
+	From time.Time
+	To   time.Time
+}
+
+func (r DateRange) String() string {
+	return r.From.Format(time.DateOnly) + "," + r.To.Format(time.DateOnly)
+}
+
+// GamesOptions mirror the query parameters of /games. Zero values are not sent.
+type GamesOptions struct {
+	ListOptions
+	Search string
+	// SearchPrecise disables fuzzy matching and SearchExact only matches the whole search
+	SearchPrecise bool
+	SearchExact   bool
+	// ParentPlatforms, Platforms and Stores are RAWG ids
+	ParentPlatforms []int64
+	Platforms       []int64
+	Stores          []int64
+	// Developers, Publishers, Genres, Tags and Creators are RAWG ids or slugs
+	Developers []string
+	Publishers []string
+	Genres     []string
+	Tags       []string
+	Creators   []string
+	// Dates filters by release date, matching any of the ranges
+	Dates []DateRange
+	// Updated filters by the date RAWG last updated the game
+	Updated        *DateRange
+	PlatformsCount int
+	// MetacriticMin and MetacriticMax bound the Metacritic score, both set or both zero
+	MetacriticMin int
+	MetacriticMax int
+	// ExcludeCollection is the id of a collection whose games are left out
+	ExcludeCollection int64
+	ExcludeAdditions  bool
+	ExcludeParents    bool
+	ExcludeGameSeries bool
+	ExcludeStores     []int64
+}
+
+func (o *GamesOptions) values() url.Values {
+	if o == nil {
+		return url.Values{}
+	}
+	query := o.ListOptions.values()
+	setString(query, "search", o.Search)
+	setBool(query, "search_precise", o.SearchPrecise)
+	setBool(query, "search_exact", o.SearchExact)
+	setIDs(query, "parent_platforms", o.ParentPlatforms)
+	setIDs(query, "platforms", o.Platforms)
+	setIDs(query, "stores", o.Stores)
+	setString(query, "developers", strings.Join(o.Developers, ","))
+	setString(query, "publishers", strings.Join(o.Publishers, ","))
+	setString(query, "genres", strings.Join(o.Genres, ","))
+	setString(query, "tags", strings.Join(o.Tags, ","))
+	setString(query, "creators", strings.Join(o.Creators, ","))
+	if len(o.Dates) > 0 {
+		dates := make([]string, len(o.Dates))
+		for i, dateRange := range o.Dates {
+			dates[i] = dateRange.String()
+		}
+		query.Set("dates", strings.Join(dates, "."))
+	}
+	if o.Updated != nil {
+		query.Set("updated", o.Updated.String())
+	}
+	if o.PlatformsCount > 0 {
+		query.Set("platforms_count", strconv.Itoa(o.PlatformsCount))
+	}
+	if o.MetacriticMin > 0 || o.MetacriticMax > 0 {
+		query.Set("metacritic", strconv.Itoa(o.MetacriticMin)+","+strconv.Itoa(o.MetacriticMax))
+	}
+	if o.ExcludeCollection > 0 {
+		query.Set("exclude_collection", strconv.FormatInt(o.ExcludeCollection, 10))
+	}
+	setBool(query, "exclude_additions", o.ExcludeAdditions)
+	setBool(query, "exclude_parents", o.ExcludeParents)
+	setBool(query, "exclude_game_series", o.ExcludeGameSeries)
+	setIDs(query, "exclude_stores", o.ExcludeStores)
+	return query
+}
+
+func setString(query url.Values, name, value string) {
+	if value != "" {
+		query.Set(name, value)
+	}
+}
+
+func setBool(query url.Values, name string, value bool) {
+	if value {
+		query.Set(name, "true")
+	}
+}
+
+func setIDs(query url.Values, name string, ids []int64) {
+	if len(ids) == 0 {
+		return
+	}
+	values := make([]string, len(ids))
+	for i, id := range ids {
+		values[i] = strconv.FormatInt(id, 10)
+	}
+	query.Set(name, strings.Join(values, ","))
+}
@@ -0,0 +1,94 @@
+package rawg
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"net/url"
+	"strconv"
+)
+
+// Page is a page of a RAWG listing. Next and Previous are the URLs of the adjacent pages,
+// empty at either end.
+type Page[T any] struct {
+	Count    int64  `json:"count"`
+	Next     string `json:"next"`
+	Previous string `json:"previous"`
+	Results  []T    `json:"results"`
+}
+
+// HasNext reports whether RAWG has a page after this one.
+func (p *Page[T]) HasNext() bool {
+	return p.Next != ""
+}
+
+// ListOptions are the pagination and ordering parameters shared by the listings. Zero
+// values are left for RAWG to default.
+type ListOptions struct {
+	Page     int
+	PageSize int
+	// Ordering is a field to sort by, such as name or -added for descending order. It is
+	// ignored by the listings that cannot be sorted.
+	Ordering string
+}
+
+func (o *ListOptions) values() url.Values {
+	query := url.Values{}
+	if o == nil {
+		return query
+	}
+	if o.Page > 0 {
+		query.Set("page", strconv.Itoa(o.Page))
+	}
+	if o.PageSize > 0 {
+		query.Set("page_size", strconv.Itoa(o.PageSize))
+	}
+	if o.Ordering != "" {
+		query.Set("ordering", o.Ordering)
+	}
+	return query
+}
+
+// All iterates over the results of a listing, starting at the page returned by first and
+// following the next links up to the last page. Iteration stops after yielding an error.
+//
+//	for genre, err := range rawg.All(ctx, api, func(ctx context.Context) (*rawg.Page[rawg.Genre], error) {
+//		return api.ListGenres(ctx, nil)
+//	}) {
+func All[T any](ctx context.Context, api *RawgAPI, first func(ctx context.Context) (*Page[T], error)) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		page, err := first(ctx)
+		for {
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+			for _, result := range page.Results {
+				if !yield(result, nil) {
+					return
+				}
+			}
+			if !page.HasNext() {
+				return
+			}
+			page, err = nextPage[T](ctx, api, page.Next)
+		}
+	}
+}
+
+// nextPage fetches the page a next link points to. Only the path and query of the link
+// are kept, so pages are fetched from the configured base URL with the client's own key.
+func nextPage[T any](ctx context.Context, api *RawgAPI, next string) (*Page[T], error) {
+	link, err := url.Parse(next)
+	if err != nil {
+		return nil, fmt.Errorf("rawg: invalid next link %q: %w", next, err)
+	}
+	query := link.Query()
+	query.Del("key")
+	var page Page[T]
+	if err := api.get(ctx, link.Path, query, &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
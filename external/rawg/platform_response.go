@@ -0,0 +1,24 @@
+package rawg
+
+// PlatformListResponse is a page of /platforms.
+type PlatformListResponse = Page[Genre]
+
+// ParentPlatformListResponse is a page of /platforms/lists/parents.
+type ParentPlatformListResponse = Page[ParentPlatform]
+
+// ParentPlatform groups the platforms of a family, such as every PlayStation.
+type ParentPlatform struct {
+	ID        int64   `json:"id"`
+	Name      string  `json:"name"`
+	Slug      string  `json:"slug"`
+	Platforms []Genre `json:"platforms"`
+}
+
+// PlatformDetail is a platform returned by /platforms/{id}.
+type PlatformDetail struct {
+	Genre
+	Description string `json:"description"` // HTML
+	Image       string `json:"image"`
+	YearStart   *int   `json:"year_start"`
+	YearEnd     *int   `json:"year_end"`
+}
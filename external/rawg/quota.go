@@ -0,0 +1,231 @@
+package rawg
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/melkdesousa/gamgo/database"
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrQuotaExceeded is returned without calling RAWG when every API key used up its budget
+// for the current period.
+var ErrQuotaExceeded = errors.New("rawg: request quota exceeded")
+
+// Quota statuses of an API key.
+const (
+	QuotaOK        = "ok"
+	QuotaSoftLimit = "soft_limit"
+	QuotaExhausted = "exhausted"
+)
+
+// UsageStore counts the requests made with each API key.
+type UsageStore interface {
+	// Increment adds a request to the counter of key, which expires at expireAt, and
+	// returns the updated count.
+	Increment(ctx context.Context, key string, expireAt time.Time) (int64, error)
+	// Get returns the counts of keys, zero for the ones never incremented.
+	Get(ctx context.Context, keys ...string) ([]int64, error)
+}
+
+// KeyUsage reports the consumption of an API key in the current period.
+type KeyUsage struct {
+	// Key is the API key masked down to its last characters
+	Key       string
+	Period    string
+	Used      int64
+	Budget    int64
+	SoftLimit int64
+	HardLimit int64
+	Status    string
+}
+
+// Quota spreads requests across API keys and keeps each of them within a monthly budget.
+// Keys are taken in turns while under the soft limit, keys past it are only used once every
+// key is past it, and no request is sent with a key that reached the hard limit.
+type Quota struct {
+	keys  []string
+	ids   []string
+	store UsageStore
+	// budget is the number of requests allowed per key and period, unlimited when zero
+	budget    int64
+	softLimit int64
+	hardLimit int64
+	next      atomic.Uint64
+	now       func() time.Time
+}
+
+// NewQuota creates a quota for keys. The soft and hard limits are percentages of budget,
+// the hard one defaulting to the whole budget and the soft one to the hard one. Requests
+// are not accounted when store is nil or budget is not positive.
+func NewQuota(keys []string, budget, softPercent, hardPercent int, store UsageStore) *Quota {
+	if hardPercent <= 0 {
+		hardPercent = 100
+	}
+	if softPercent <= 0 || softPercent > hardPercent {
+		softPercent = hardPercent
+	}
+	ids := make([]string, len(keys))
+	for i, key := range keys {
+		sum := sha256.Sum256([]byte(key))
+		ids[i] = hex.EncodeToString(sum[:6]) // the keys themselves are not stored
+	}
+	return &Quota{
+		keys:      keys,
+		ids:       ids,
+		store:     store,
+		budget:    int64(max(budget, 0)),
+		softLimit: int64(budget) * int64(softPercent) / 100,
+		hardLimit: int64(budget) * int64(hardPercent) / 100,
+		now:       time.Now,
+	}
+}
+
+// Acquire picks the key of the next request and accounts the request to it, returning
+// ErrQuotaExceeded when every key reached the hard limit. Requests are let through
+// unaccounted when the usage store is unavailable.
+func (q *Quota) Acquire(ctx context.Context) (string, error) {
+	if len(q.keys) == 0 {
+		return "", nil
+	}
+	start := int(q.next.Add(1)-1) % len(q.keys)
+	if q.store == nil || q.budget == 0 {
+		return q.keys[start], nil
+	}
+	period, expireAt := q.period()
+	usage, err := q.store.Get(ctx, q.counterKeys(period)...)
+	if err != nil {
+		log.Printf("Error reading RAWG quota usage, sending request unaccounted: %v", err)
+		return q.keys[start], nil
+	}
+	for _, limit := range []int64{q.softLimit, q.hardLimit} {
+		for offset := range q.keys {
+			i := (start + offset) % len(q.keys)
+			if usage[i] >= limit {
+				continue
+			}
+			used, err := q.store.Increment(ctx, q.counterKey(period, i), expireAt)
+			if err != nil {
+				log.Printf("Error accounting RAWG request, sending it unaccounted: %v", err)
+				return q.keys[i], nil
+			}
+			if used > q.hardLimit {
+				// concurrent requests took the last ones
+				usage[i] = used
+				continue
+			}
+			if used == q.softLimit {
+				log.Printf("Warning: RAWG key %s reached the soft limit of %d requests for %s", mask(q.keys[i]), q.softLimit, period)
+			}
+			return q.keys[i], nil
+		}
+	}
+	log.Printf("Every RAWG key reached the hard limit of %d requests for %s", q.hardLimit, period)
+	return "", ErrQuotaExceeded
+}
+
+// Usage reports the consumption of each key in the current period.
+func (q *Quota) Usage(ctx context.Context) ([]KeyUsage, error) {
+	period, _ := q.period()
+	usage := make([]int64, len(q.keys))
+	if q.store != nil && len(q.keys) > 0 {
+		var err error
+		if usage, err = q.store.Get(ctx, q.counterKeys(period)...); err != nil {
+			return nil, fmt.Errorf("failed to read RAWG quota usage: %w", err)
+		}
+	}
+	report := make([]KeyUsage, len(q.keys))
+	for i, key := range q.keys {
+		status := QuotaOK
+		if q.budget > 0 && usage[i] >= q.hardLimit {
+			status = QuotaExhausted
+		} else if q.budget > 0 && usage[i] >= q.softLimit {
+			status = QuotaSoftLimit
+		}
+		report[i] = KeyUsage{
+			Key:       mask(key),
+			Period:    period,
+			Used:      usage[i],
+			Budget:    q.budget,
+			SoftLimit: q.softLimit,
+			HardLimit: q.hardLimit,
+			Status:    status,
+		}
+	}
+	return report, nil
+}
+
+// period returns the current month and when its counters may be dropped. Counters outlive
+// their month by a week so the previous consumption can still be looked up.
+func (q *Quota) period() (string, time.Time) {
+	now := q.now().UTC()
+	start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return start.Format("2006-01"), start.AddDate(0, 1, 7)
+}
+
+func (q *Quota) counterKey(period string, i int) string {
+	return database.GetCacheKey(database.CACHE_RAWG_QUOTA_KEY_PREFIX, period, q.ids[i])
+}
+
+func (q *Quota) counterKeys(period string) []string {
+	keys := make([]string, len(q.ids))
+	for i := range q.ids {
+		keys[i] = q.counterKey(period, i)
+	}
+	return keys
+}
+
+// mask hides an API key but for its last characters.
+func mask(key string) string {
+	if len(key) <= 4 {
+		return "****"
+	}
+	return "****" + key[len(key)-4:]
+}
+
+type redisUsageStore struct {
+	client redis.Cmdable
+}
+
+// NewRedisUsageStore creates a usage store keeping the counters in Redis.
+func NewRedisUsageStore(client redis.Cmdable) UsageStore {
+	return &redisUsageStore{client: client}
+}
+
+func (s *redisUsageStore) Increment(ctx context.Context, key string, expireAt time.Time) (int64, error) {
+	var incr *redis.IntCmd
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		incr = pipe.Incr(ctx, key)
+		pipe.ExpireAt(ctx, key, expireAt)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return incr.Val(), nil
+}
+
+func (s *redisUsageStore) Get(ctx context.Context, keys ...string) ([]int64, error) {
+	values, err := s.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+	counts := make([]int64, len(values))
+	for i, value := range values {
+		text, ok := value.(string)
+		if !ok {
+			continue // never incremented
+		}
+		if counts[i], err = strconv.ParseInt(text, 10, 64); err != nil {
+			return nil, fmt.Errorf("invalid counter %s: %w", keys[i], err)
+		}
+	}
+	return counts, nil
+}
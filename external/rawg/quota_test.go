@@ -0,0 +1,109 @@
+package rawg
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// memoryUsageStore keeps the counters in memory, failing every call when err is set.
+type memoryUsageStore struct {
+	mu     sync.Mutex
+	counts map[string]int64
+	err    error
+}
+
+func newMemoryUsageStore() *memoryUsageStore {
+	return &memoryUsageStore{counts: map[string]int64{}}
+}
+
+func (s *memoryUsageStore) Increment(_ context.Context, key string, _ time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return 0, s.err
+	}
+	s.counts[key]++
+	return s.counts[key], nil
+}
+
+func (s *memoryUsageStore) Get(_ context.Context, keys ...string) ([]int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return nil, s.err
+	}
+	counts := make([]int64, len(keys))
+	for i, key := range keys {
+		counts[i] = s.counts[key]
+	}
+	return counts, nil
+}
+
+func TestQuota(t *testing.T) {
+	ctx := context.Background()
+	newQuota := func(store UsageStore, keys ...string) *Quota {
+		// budget of 10 requests per key, soft limit at 5 and hard limit at 10
+		quota := NewQuota(keys, 10, 50, 100, store)
+		quota.now = func() time.Time { return time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC) }
+		return quota
+	}
+	acquire := func(t *testing.T, quota *Quota, times int) []string {
+		keys := make([]string, times)
+		for i := range keys {
+			key, err := quota.Acquire(ctx)
+			assert.NoError(t, err)
+			keys[i] = key
+		}
+		return keys
+	}
+
+	t.Run("RotatesKeys", func(t *testing.T) {
+		quota := newQuota(newMemoryUsageStore(), "key-a", "key-b")
+		assert.Equal(t, []string{"key-a", "key-b", "key-a", "key-b"}, acquire(t, quota, 4))
+	})
+
+	t.Run("KeysPastSoftLimitAreUsedLast", func(t *testing.T) {
+		quota := newQuota(newMemoryUsageStore(), "key-a", "key-b")
+		for range 5 {
+			quota.next.Store(0) // always prefer key-a
+			acquire(t, quota, 1)
+		}
+		quota.next.Store(0)
+		assert.Equal(t, []string{"key-b", "key-b"}, acquire(t, quota, 2), "key-a should be skipped past its soft limit")
+	})
+
+	t.Run("RefusesPastHardLimit", func(t *testing.T) {
+		quota := newQuota(newMemoryUsageStore(), "key-a")
+		acquire(t, quota, 10)
+		_, err := quota.Acquire(ctx)
+		assert.ErrorIs(t, err, ErrQuotaExceeded)
+	})
+
+	t.Run("UnavailableStoreLetsRequestsThrough", func(t *testing.T) {
+		store := newMemoryUsageStore()
+		store.err = errors.New("connection refused")
+		quota := newQuota(store, "key-a")
+		assert.Equal(t, []string{"key-a"}, acquire(t, quota, 1))
+	})
+
+	t.Run("Usage", func(t *testing.T) {
+		quota := newQuota(newMemoryUsageStore(), "first-key-1234", "second-key-5678")
+		for range 6 {
+			quota.next.Store(0)
+			acquire(t, quota, 1)
+		}
+
+		usage, err := quota.Usage(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []KeyUsage{
+			{Key: "****1234", Period: "2025-06", Used: 5, Budget: 10, SoftLimit: 5, HardLimit: 10, Status: QuotaSoftLimit},
+			{Key: "****5678", Period: "2025-06", Used: 1, Budget: 10, SoftLimit: 5, HardLimit: 10, Status: QuotaOK},
+		}, usage)
+	})
+}
@@ -0,0 +1,255 @@
+// Package rawgtest fakes the RAWG API for local development and tests, serving
+// deterministic RAWG-shaped responses from a directory of JSON fixtures.
+//
+// A request for /api/{path} is answered with the fixture {path}.json, the /api prefix
+// being optional. Fixtures holding an array are listings: their items are filtered by the
+// search parameter, matching the names having a word starting with each word of the
+// search, and paginated with page and page_size like RAWG, next and previous links
+// included. Fixtures holding an object are served as they are. A game detail without a
+// fixture of its own, such as /api/games/4200, is answered with the item of games.json
+// whose id or slug matches. Requests without a fixture get a 404.
+package rawgtest
+
+import (
+	"cmp"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"net/http"
+	"net/url"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/melkdesousa/gamgo/utils"
+)
+
+//go:embed fixtures
+var fixtures embed.FS
+
+// Fixtures returns the catalog shipped with the package, a couple dozen well-known games
+// with their genres and platforms.
+func Fixtures() fs.FS {
+	sub, err := fs.Sub(fixtures, "fixtures")
+	if err != nil {
+		panic(err) // the directory is embedded
+	}
+	return sub
+}
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 40
+)
+
+// Faults are injected into the responses of the stub. Requests are counted from 1, and
+// those answered with a failure get no fixture.
+type Faults struct {
+	// Latency delays every response
+	Latency time.Duration
+	// ErrorEvery answers every nth request with ErrorStatus, never when zero
+	ErrorEvery  int
+	ErrorStatus int // defaults to 500
+	// RateLimitEvery answers every nth request with a 429 asking to retry after RetryAfter,
+	// never when zero
+	RateLimitEvery int
+	RetryAfter     time.Duration
+}
+
+// Handler serves the fixtures of a directory as the RAWG API.
+type Handler struct {
+	fixtures fs.FS
+	mu       sync.Mutex
+	faults   Faults
+	requests int
+}
+
+// NewHandler creates a handler serving fixtures, such as Fixtures() or os.DirFS(dir).
+func NewHandler(fixtures fs.FS, faults Faults) *Handler {
+	return &Handler{fixtures: fixtures, faults: faults}
+}
+
+// SetFaults replaces the faults injected into the following responses.
+func (h *Handler) SetFaults(faults Faults) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.faults = faults
+}
+
+// Requests returns the number of requests received so far.
+func (h *Handler) Requests() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.requests
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	h.requests++
+	n, faults := h.requests, h.faults
+	h.mu.Unlock()
+
+	if faults.Latency > 0 {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-time.After(faults.Latency):
+		}
+	}
+	if r.Method != http.MethodGet {
+		writeDetail(w, http.StatusMethodNotAllowed, "Method not allowed.")
+		return
+	}
+	if faults.RateLimitEvery > 0 && n%faults.RateLimitEvery == 0 {
+		if faults.RetryAfter > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int(faults.RetryAfter.Round(time.Second)/time.Second)))
+		}
+		writeDetail(w, http.StatusTooManyRequests, "Request was throttled.")
+		return
+	}
+	if faults.ErrorEvery > 0 && n%faults.ErrorEvery == 0 {
+		writeDetail(w, cmp.Or(faults.ErrorStatus, http.StatusInternalServerError), "Server error.")
+		return
+	}
+
+	name := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api"), "/")
+	body, err := h.respond(name, r)
+	if errors.Is(err, fs.ErrNotExist) {
+		writeDetail(w, http.StatusNotFound, "Not found.")
+		return
+	}
+	if err != nil {
+		log.Printf("rawgtest: failed to serve %s: %v", r.URL.Path, err)
+		writeDetail(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(body)
+}
+
+// respond returns the body of the response to the request of a fixture.
+func (h *Handler) respond(name string, r *http.Request) ([]byte, error) {
+	if name == "" || !fs.ValidPath(name) {
+		return nil, fs.ErrNotExist
+	}
+	content, err := fs.ReadFile(h.fixtures, name+".json")
+	if errors.Is(err, fs.ErrNotExist) && path.Dir(name) == "games" {
+		return h.gameDetail(path.Base(name))
+	}
+	if err != nil {
+		return nil, err
+	}
+	var items []json.RawMessage
+	if json.Unmarshal(content, &items) != nil {
+		return content, nil // an object, served as it is
+	}
+	return listPage(items, r)
+}
+
+// gameDetail returns the item of games.json whose id or slug is id.
+func (h *Handler) gameDetail(id string) ([]byte, error) {
+	content, err := fs.ReadFile(h.fixtures, "games.json")
+	if err != nil {
+		return nil, err
+	}
+	var items []json.RawMessage
+	if err := json.Unmarshal(content, &items); err != nil {
+		return nil, fmt.Errorf("games.json is not a list: %w", err)
+	}
+	for _, item := range items {
+		var game listItem
+		if err := json.Unmarshal(item, &game); err == nil && (game.ID.String() == id || game.Slug == id) {
+			return item, nil
+		}
+	}
+	return nil, fs.ErrNotExist
+}
+
+// listItem holds the fields of a listed item used to find it.
+type listItem struct {
+	ID   json.Number `json:"id"`
+	Slug string      `json:"slug"`
+	Name string      `json:"name"`
+}
+
+type page struct {
+	Count    int               `json:"count"`
+	Next     *string           `json:"next"`
+	Previous *string           `json:"previous"`
+	Results  []json.RawMessage `json:"results"`
+}
+
+// listPage returns the page of the items matching the search of the request.
+func listPage(items []json.RawMessage, r *http.Request) ([]byte, error) {
+	query := r.URL.Query()
+	words := strings.Fields(utils.NormalizeTitle(query.Get("search")))
+	matches := []json.RawMessage{}
+	for _, item := range items {
+		var entry listItem
+		if err := json.Unmarshal(item, &entry); err != nil {
+			return nil, fmt.Errorf("invalid listing item: %w", err)
+		}
+		if matchesWords(strings.Fields(utils.NormalizeTitle(entry.Name)), words) {
+			matches = append(matches, item)
+		}
+	}
+	number := max(queryInt(query, "page", 1), 1)
+	size := min(max(queryInt(query, "page_size", defaultPageSize), 1), maxPageSize)
+	start := min((number-1)*size, len(matches))
+	end := min(start+size, len(matches))
+	if start == len(matches) && number > 1 {
+		// RAWG answers the pages past the last one with a 404
+		return nil, fs.ErrNotExist
+	}
+	response := page{Count: len(matches), Results: matches[start:end]}
+	if end < len(matches) {
+		response.Next = pageLink(r, number+1)
+	}
+	if number > 1 {
+		response.Previous = pageLink(r, number-1)
+	}
+	return json.Marshal(response)
+}
+
+// matchesWords reports whether every word of words starts a word of name.
+func matchesWords(name, words []string) bool {
+	for _, word := range words {
+		if !slices.ContainsFunc(name, func(nameWord string) bool { return strings.HasPrefix(nameWord, word) }) {
+			return false
+		}
+	}
+	return true
+}
+
+// pageLink returns the URL of another page of the listing of the request.
+func pageLink(r *http.Request, number int) *string {
+	query := r.URL.Query()
+	query.Set("page", strconv.Itoa(number))
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	link := (&url.URL{Scheme: scheme, Host: r.Host, Path: r.URL.Path, RawQuery: query.Encode()}).String()
+	return &link
+}
+
+func queryInt(query url.Values, name string, fallback int) int {
+	value, err := strconv.Atoi(query.Get(name))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+// writeDetail writes an error response shaped like those of RAWG.
+func writeDetail(w http.ResponseWriter, status int, detail string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"detail": detail})
+}
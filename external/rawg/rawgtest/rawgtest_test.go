@@ -0,0 +1,134 @@
+package rawgtest_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/melkdesousa/gamgo/external/rawg"
+	"github.com/melkdesousa/gamgo/external/rawg/rawgtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStub(t *testing.T) {
+	ctx := context.Background()
+	newAPI := func(server *rawgtest.Server) *rawg.RawgAPI {
+		return rawg.NewRawgAPIWithConfig(rawg.Config{BaseURL: server.URL, APIKeys: []string{"test-key"}, Timeout: time.Second}, nil, nil)
+	}
+
+	t.Run("SearchGames", func(t *testing.T) {
+		// Setup
+		api := newAPI(rawgtest.NewServer(t, rawgtest.Fixtures(), rawgtest.Faults{}))
+
+		// Call the client
+		page, err := api.SearchGames(ctx, "PORTAL", 1)
+
+		// Assertions
+		assert.NoError(t, err)
+		if assert.NotNil(t, page) && assert.Len(t, page.Results, 2) {
+			assert.Equal(t, int64(2), page.Count)
+			assert.Equal(t, "Portal 2", page.Results[0].Name)
+			assert.Equal(t, "Portal", page.Results[1].Name)
+			assert.False(t, page.HasNext())
+		}
+	})
+
+	t.Run("Pagination", func(t *testing.T) {
+		// Setup
+		server := rawgtest.NewServer(t, rawgtest.Fixtures(), rawgtest.Faults{})
+		api := newAPI(server)
+
+		// Call the client
+		first, firstErr := api.ListGames(ctx, &rawg.GamesOptions{ListOptions: rawg.ListOptions{PageSize: 10}})
+		var names []string
+		for game, err := range rawg.All(ctx, api, func(ctx context.Context) (*rawg.Page[rawg.Result], error) {
+			return api.ListGames(ctx, &rawg.GamesOptions{ListOptions: rawg.ListOptions{PageSize: 10}})
+		}) {
+			assert.NoError(t, err)
+			names = append(names, game.Name)
+		}
+		_, pastLastErr := api.ListGames(ctx, &rawg.GamesOptions{ListOptions: rawg.ListOptions{Page: 4, PageSize: 10}})
+
+		// Assertions
+		assert.NoError(t, firstErr)
+		if assert.NotNil(t, first) {
+			assert.Len(t, first.Results, 10)
+			assert.True(t, first.HasNext())
+			assert.Empty(t, first.Previous)
+		}
+		assert.Len(t, names, 25, "Following the next links should list the whole catalog")
+		assert.ErrorIs(t, pastLastErr, rawg.ErrNotFound)
+	})
+
+	t.Run("GetGame", func(t *testing.T) {
+		// Setup
+		api := newAPI(rawgtest.NewServer(t, rawgtest.Fixtures(), rawgtest.Faults{}))
+
+		// Call the client
+		detail, detailErr := api.GetGame(ctx, "4200")
+		listed, listedErr := api.GetGame(ctx, "celeste")
+		_, missingErr := api.GetGame(ctx, "0")
+
+		// Assertions
+		assert.NoError(t, detailErr)
+		assert.NoError(t, listedErr)
+		if detail != nil {
+			assert.Equal(t, "Portal 2", detail.Name)
+			assert.Equal(t, "Valve Software", detail.Developers[0].Name)
+		}
+		if listed != nil {
+			assert.Equal(t, 26823, listed.ID, "Games without a detail fixture should be served from the listing")
+		}
+		assert.ErrorIs(t, missingErr, rawg.ErrNotFound)
+	})
+
+	t.Run("ObjectFixtures", func(t *testing.T) {
+		// Setup
+		fixtures := fstest.MapFS{"games/7/movies.json": {Data: []byte(`{"count": 0, "next": null, "previous": null, "results": []}`)}}
+		api := newAPI(rawgtest.NewServer(t, fixtures, rawgtest.Faults{}))
+
+		// Call the client
+		movies, err := api.ListGameMovies(ctx, "7", nil)
+
+		// Assertions
+		assert.NoError(t, err)
+		if assert.NotNil(t, movies) {
+			assert.Empty(t, movies.Results)
+		}
+	})
+
+	t.Run("Faults", func(t *testing.T) {
+		// Setup
+		server := rawgtest.NewServer(t, rawgtest.Fixtures(), rawgtest.Faults{RateLimitEvery: 2, RetryAfter: 3 * time.Second})
+
+		// Call the stub
+		first, firstErr := http.Get(server.URL + "/api/genres")
+		second, secondErr := http.Get(server.URL + "/api/genres")
+		server.SetFaults(rawgtest.Faults{ErrorEvery: 1, ErrorStatus: http.StatusBadGateway})
+		third, thirdErr := http.Get(server.URL + "/api/genres")
+
+		// Assertions
+		assert.NoError(t, firstErr)
+		assert.NoError(t, secondErr)
+		assert.NoError(t, thirdErr)
+		assert.Equal(t, http.StatusOK, first.StatusCode)
+		assert.Equal(t, http.StatusTooManyRequests, second.StatusCode)
+		assert.Equal(t, "3", second.Header.Get("Retry-After"))
+		assert.Equal(t, http.StatusBadGateway, third.StatusCode)
+		assert.Equal(t, 3, server.Requests())
+	})
+
+	t.Run("Latency", func(t *testing.T) {
+		// Setup
+		server := rawgtest.NewServer(t, rawgtest.Fixtures(), rawgtest.Faults{Latency: 50 * time.Millisecond})
+		api := rawg.NewRawgAPIWithConfig(rawg.Config{BaseURL: server.URL, APIKeys: []string{"test-key"}, Timeout: 10 * time.Millisecond}, nil, nil)
+
+		// Call the client
+		_, err := api.ListGenres(ctx, nil)
+
+		// Assertions
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
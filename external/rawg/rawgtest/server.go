@@ -0,0 +1,22 @@
+package rawgtest
+
+import (
+	"io/fs"
+	"net/http/httptest"
+	"testing"
+)
+
+// Server is a stub of the RAWG API listening on a local address, to be used as the base
+// URL of a RAWG client.
+type Server struct {
+	*httptest.Server
+	*Handler
+}
+
+// NewServer starts a stub serving fixtures, closed when the test ends.
+func NewServer(tb testing.TB, fixtures fs.FS, faults Faults) *Server {
+	handler := NewHandler(fixtures, faults)
+	server := &Server{Server: httptest.NewServer(handler), Handler: handler}
+	tb.Cleanup(server.Close)
+	return server
+}
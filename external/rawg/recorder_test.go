@@ -0,0 +1,83 @@
+package rawg
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordReplay(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/games":
+			w.Write([]byte(`{"count": 41, "next": "https://api.rawg.io/api/games?key=` + r.URL.Query().Get("key") + `&page=2&search=portal", "previous": null, "results": [{"id": 4200, "name": "Portal 2"}]}`))
+		case "/api/games/busy":
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"detail": "Not found."}`))
+		}
+	}))
+	defer server.Close()
+	newAPI := func(mode Mode) *RawgAPI {
+		return NewRawgAPIWithConfig(Config{BaseURL: server.URL, APIKeys: []string{"secret-key"}, Timeout: time.Second, MaxRetries: 2, Mode: mode, FixturesDir: dir}, nil, nil)
+	}
+
+	t.Run("Record", func(t *testing.T) {
+		// Setup
+		api := newAPI(ModeRecord)
+
+		// Call the client
+		page, err := api.SearchGames(ctx, "portal", 1)
+		_, notFoundErr := api.GetGame(ctx, "404")
+		_, busyErr := api.GetGame(ctx, "busy")
+
+		// Assertions
+		assert.NoError(t, err)
+		if assert.NotNil(t, page) {
+			assert.Equal(t, "Portal 2", page.Results[0].Name)
+		}
+		assert.ErrorIs(t, notFoundErr, ErrNotFound)
+		assert.ErrorIs(t, busyErr, ErrRateLimited)
+		files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+		assert.Len(t, files, 2, "Throttled responses should not be recorded")
+		for _, file := range files {
+			content, err := os.ReadFile(file)
+			assert.NoError(t, err)
+			assert.NotContains(t, string(content), "secret-key", "The API key should be stripped")
+		}
+	})
+
+	t.Run("Replay", func(t *testing.T) {
+		// Setup
+		api := newAPI(ModeReplay)
+		before := requests
+
+		// Call the client
+		page, err := api.SearchGames(ctx, "portal", 1)
+		_, notFoundErr := api.GetGame(ctx, "404")
+		_, unrecordedErr := api.SearchGames(ctx, "portal", 2)
+
+		// Assertions
+		assert.NoError(t, err)
+		if assert.NotNil(t, page) {
+			assert.Equal(t, int64(41), page.Count)
+			assert.Equal(t, "https://api.rawg.io/api/games?key=redacted&page=2&search=portal", page.Next)
+		}
+		assert.ErrorIs(t, notFoundErr, ErrNotFound)
+		assert.ErrorIs(t, unrecordedErr, ErrNotRecorded)
+		assert.ErrorContains(t, unrecordedErr, "/api/games?page=2&search=portal")
+		assert.Equal(t, before, requests, "RAWG should not be called")
+	})
+}
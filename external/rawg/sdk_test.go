@@ -0,0 +1,224 @@
+package rawg
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fixtureServer replays the JSON fixtures of testdata, named after the request path
+// without its /api prefix, such as games_4200_screenshots.json, with a _page<n> suffix
+// for the pages after the first. Requests without a fixture get a 404.
+type fixtureServer struct {
+	*httptest.Server
+	mu      sync.Mutex
+	queries []url.Values
+}
+
+func newFixtureServer(t *testing.T) *fixtureServer {
+	server := &fixtureServer{}
+	server.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		server.mu.Lock()
+		server.queries = append(server.queries, r.URL.Query())
+		server.mu.Unlock()
+		name := strings.ReplaceAll(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api"), "/"), "/", "_")
+		if page := r.URL.Query().Get("page"); page != "" && page != "1" {
+			name += "_page" + page
+		}
+		body, err := os.ReadFile(filepath.Join("testdata", name+".json"))
+		if err != nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(body)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func (s *fixtureServer) lastQuery() url.Values {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.queries[len(s.queries)-1]
+}
+
+func TestRawgSDK(t *testing.T) {
+	ctx := context.Background()
+	newAPI := func(server *fixtureServer) *RawgAPI {
+		return NewRawgAPIWithConfig(Config{BaseURL: server.URL, APIKeys: []string{"test-key"}, Timeout: time.Second}, nil, nil)
+	}
+
+	t.Run("ListGames", func(t *testing.T) {
+		// Setup
+		server := newFixtureServer(t)
+		api := newAPI(server)
+
+		// Call the client
+		page, err := api.ListGames(ctx, &GamesOptions{ListOptions: ListOptions{PageSize: 2, Ordering: "-metacritic"}, Search: "portal"})
+
+		// Assertions
+		assert.NoError(t, err)
+		if !assert.NotNil(t, page) || !assert.Len(t, page.Results, 2) {
+			return
+		}
+		assert.Equal(t, int64(3), page.Count)
+		assert.True(t, page.HasNext())
+		game := page.Results[0]
+		assert.Equal(t, "Portal 2", game.Name)
+		assert.Equal(t, int64(95), *game.Metacritic)
+		assert.Nil(t, page.Results[1].Metacritic, "A null score should stay unknown")
+		assert.Equal(t, int64(13135), game.AddedByStatus.Owned)
+		assert.Equal(t, "Everyone 10+", game.EsrbRating.Name)
+		assert.Equal(t, "Steam", game.Stores[0].Store.Name)
+		assert.Equal(t, "Windows 10", game.Platforms[0].Requirements.Recommended)
+		assert.Equal(t, "playstation", game.ParentPlatforms[1].Platform.Slug)
+		assert.Len(t, game.ShortScreenshots, 1)
+		query := server.lastQuery()
+		assert.Equal(t, "portal", query.Get("search"))
+		assert.Equal(t, "2", query.Get("page_size"))
+		assert.Equal(t, "-metacritic", query.Get("ordering"))
+	})
+
+	t.Run("GamesOptions", func(t *testing.T) {
+		opts := &GamesOptions{
+			ListOptions:     ListOptions{Page: 3},
+			SearchPrecise:   true,
+			ParentPlatforms: []int64{1, 2},
+			Genres:          []string{"action", "indie"},
+			Dates: []DateRange{
+				{From: time.Date(2010, 1, 1, 0, 0, 0, 0, time.UTC), To: time.Date(2018, 12, 31, 0, 0, 0, 0, time.UTC)},
+				{From: time.Date(1960, 1, 1, 0, 0, 0, 0, time.UTC), To: time.Date(1969, 12, 31, 0, 0, 0, 0, time.UTC)},
+			},
+			MetacriticMin:  80,
+			MetacriticMax:  100,
+			ExcludeParents: true,
+		}
+
+		query := opts.values()
+
+		assert.Equal(t, url.Values{
+			"page":             {"3"},
+			"search_precise":   {"true"},
+			"parent_platforms": {"1,2"},
+			"genres":           {"action,indie"},
+			"dates":            {"2010-01-01,2018-12-31.1960-01-01,1969-12-31"},
+			"metacritic":       {"80,100"},
+			"exclude_parents":  {"true"},
+		}, query)
+	})
+
+	t.Run("AllFollowsNextLinks", func(t *testing.T) {
+		// Setup
+		server := newFixtureServer(t)
+		api := newAPI(server)
+
+		// Call the client
+		var names []string
+		for game, err := range All(ctx, api, func(ctx context.Context) (*Page[Result], error) {
+			return api.ListGames(ctx, &GamesOptions{Search: "portal"})
+		}) {
+			assert.NoError(t, err)
+			names = append(names, game.Name)
+		}
+
+		// Assertions
+		assert.Equal(t, []string{"Portal 2", "Portal", "Portal Stories: Mel"}, names)
+		query := server.lastQuery()
+		assert.Equal(t, "2", query.Get("page"))
+		assert.Equal(t, "portal", query.Get("search"), "The next link should keep the filters")
+		assert.Equal(t, "test-key", query.Get("key"), "The recorded key should be replaced")
+	})
+
+	t.Run("AllStopsAtErrors", func(t *testing.T) {
+		// Setup
+		server := newFixtureServer(t)
+		api := newAPI(server)
+
+		// Call the client
+		var errs []error
+		for _, err := range All(ctx, api, func(ctx context.Context) (*Page[Genre], error) {
+			return api.ListGenres(ctx, nil) // no fixture
+		}) {
+			errs = append(errs, err)
+		}
+
+		// Assertions
+		if assert.Len(t, errs, 1) {
+			assert.ErrorIs(t, errs[0], ErrNotFound)
+		}
+	})
+
+	t.Run("GameResources", func(t *testing.T) {
+		// Setup
+		server := newFixtureServer(t)
+		api := newAPI(server)
+
+		// Call the client
+		screenshots, screenshotsErr := api.ListGameScreenshots(ctx, "4200", nil)
+		movies, moviesErr := api.ListGameMovies(ctx, "4200", nil)
+		achievements, achievementsErr := api.ListGameAchievements(ctx, "4200", nil)
+		stores, storesErr := api.ListGameStores(ctx, "4200", nil)
+		_, additionsErr := api.ListGameAdditions(ctx, "4200", nil)
+
+		// Assertions
+		assert.NoError(t, screenshotsErr)
+		assert.NoError(t, moviesErr)
+		assert.NoError(t, achievementsErr)
+		assert.NoError(t, storesErr)
+		assert.ErrorIs(t, additionsErr, ErrNotFound)
+		if screenshots != nil && assert.Len(t, screenshots.Results, 2) {
+			assert.Equal(t, 1280, screenshots.Results[0].Width)
+		}
+		if movies != nil && assert.Len(t, movies.Results, 1) {
+			assert.Equal(t, "https://steamcdn-a.akamaihd.net/steam/apps/81613/movie480.mp4", movies.Results[0].Data.Low)
+		}
+		if achievements != nil && assert.Len(t, achievements.Results, 1) {
+			assert.Equal(t, "92.69", achievements.Results[0].Percent)
+		}
+		if stores != nil && assert.Len(t, stores.Results, 1) {
+			assert.Equal(t, int64(1), stores.Results[0].StoreID)
+		}
+	})
+
+	t.Run("Catalog", func(t *testing.T) {
+		// Setup
+		server := newFixtureServer(t)
+		api := newAPI(server)
+
+		// Call the client
+		genre, genreErr := api.GetGenre(ctx, "action")
+		platform, platformErr := api.GetPlatform(ctx, "4")
+		creators, creatorsErr := api.ListCreators(ctx, &ListOptions{Page: 1})
+		stores, storesErr := api.ListStores(ctx, nil)
+
+		// Assertions
+		assert.NoError(t, genreErr)
+		assert.NoError(t, platformErr)
+		assert.NoError(t, creatorsErr)
+		assert.NoError(t, storesErr)
+		if genre != nil {
+			assert.Equal(t, "action", genre.Slug)
+			assert.Contains(t, genre.Description, "action game")
+		}
+		if platform != nil {
+			assert.Equal(t, "PC", platform.Name)
+			assert.Nil(t, platform.YearStart)
+		}
+		if creators != nil && assert.Len(t, creators.Results, 1) {
+			assert.Equal(t, "writer", creators.Results[0].Positions[0].Slug)
+		}
+		if stores != nil && assert.Len(t, stores.Results, 1) {
+			assert.Equal(t, "store.steampowered.com", stores.Results[0].Domain)
+		}
+	})
+}
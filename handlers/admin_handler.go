@@ -0,0 +1,224 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/melkdesousa/gamgo/cache"
+	"github.com/melkdesousa/gamgo/config"
+	"github.com/melkdesousa/gamgo/mappers"
+	"github.com/melkdesousa/gamgo/services"
+)
+
+// AdminHandler handles the administration endpoints, authenticated by the ADMIN_TOKEN
+// environment variable instead of user sessions.
+type AdminHandler struct {
+	app          *fiber.App
+	adminService *services.AdminService
+	token        string
+}
+
+// NewAdminHandler creates a new AdminHandler. It must be registered before the JWT
+// middleware. The endpoints are disabled when ADMIN_TOKEN is not set.
+func NewAdminHandler(app *fiber.App, adminService *services.AdminService) {
+	handler := &AdminHandler{
+		app:          app,
+		adminService: adminService,
+		token:        config.GetEnvOrDefault("ADMIN_TOKEN", ""),
+	}
+	admin := app.Group("/admin", handler.authorize)
+	admin.Get("/rawg/quota", handler.RawgQuota)
+	admin.Get("/cache/metrics", handler.CacheMetrics)
+	admin.Get("/cache/keys", handler.CacheKeys)
+	admin.Delete("/cache/keys", handler.PurgeCache)
+	// keys contain slashes, such as those of search cursors
+	admin.Get("/cache/keys/*", handler.CacheEntry)
+}
+
+// authorize lets through the requests carrying the admin token in the X-Admin-Token header.
+func (h *AdminHandler) authorize(c *fiber.Ctx) error {
+	if h.token == "" {
+		return c.Status(http.StatusNotFound).JSON(mappers.ErrorResponse{Error: "Not found"})
+	}
+	if subtle.ConstantTimeCompare([]byte(c.Get("X-Admin-Token")), []byte(h.token)) != 1 {
+		return c.Status(http.StatusUnauthorized).JSON(mappers.ErrorResponse{Error: "Invalid admin token"})
+	}
+	return c.Next()
+}
+
+// RawgQuota godoc
+//
+//	@Summary		RAWG Quota
+//	@Description	get the number of RAWG requests made with each API key in the current month against its budget
+//	@Security		AdminToken
+//	@Tags			admin
+//	@Produce		json
+//	@Success		200	{object}	mappers.CommonResponse[[]mappers.RawgKeyUsageOutputDTO]
+//	@Failure		401	{object}	mappers.ErrorResponse
+//	@Failure		500	{object}	mappers.ErrorResponse
+//	@Router			/admin/rawg/quota [get]
+func (h *AdminHandler) RawgQuota(c *fiber.Ctx) error {
+	usage, err := h.adminService.RawgQuotaUsage(c.Context())
+	if err != nil {
+		log.Printf("Error from AdminService: %v", err)
+		return c.Status(http.StatusInternalServerError).JSON(mappers.ErrorResponse{
+			Error:   "An unexpected error occurred",
+			Details: err.Error(),
+		})
+	}
+	return c.Status(http.StatusOK).JSON(mappers.CommonResponse[[]mappers.RawgKeyUsageOutputDTO]{
+		Data:    mappers.MapRawgKeyUsageToOutputDTO(usage),
+		Message: "RAWG quota retrieved successfully",
+	})
+}
+
+// CacheMetrics godoc
+//
+//	@Summary		Cache Metrics
+//	@Description	get the counters of the search cache of the instance since it started, with its hit and miss ratios: fresh, stale and negative hits, misses, errors, corrupted entries, entries invalidated by changed games, and background refreshes scheduled, skipped, dropped, succeeded or failed
+//	@Security		AdminToken
+//	@Tags			admin
+//	@Produce		json
+//	@Success		200	{object}	mappers.CommonResponse[mappers.CacheMetricsOutputDTO]
+//	@Failure		401	{object}	mappers.ErrorResponse
+//	@Router			/admin/cache/metrics [get]
+func (h *AdminHandler) CacheMetrics(c *fiber.Ctx) error {
+	return c.Status(http.StatusOK).JSON(mappers.CommonResponse[mappers.CacheMetricsOutputDTO]{
+		Data:    mappers.MapCacheMetricsToOutputDTO(h.adminService.SearchCacheMetrics()),
+		Message: "Cache metrics retrieved successfully",
+	})
+}
+
+// CacheKeys godoc
+//
+//	@Summary		Cache Keys
+//	@Description	list the keys of the cache matching a glob pattern, or the cache keys starting with a prefix whatever their schema version. All cache keys are listed when neither is given. Patterns must start with a schema version, such as v*:, or with tag:
+//	@Security		AdminToken
+//	@Tags			admin
+//	@Produce		json
+//	@Param			pattern	query		string	false	"glob pattern of the keys, such as v*:search:*"
+//	@Param			prefix	query		string	false	"prefix of the cache keys without their schema version, such as search:game"
+//	@Param			limit	query		int		false	"maximum number of keys"	default(100)
+//	@Success		200		{object}	mappers.CommonResponse[mappers.CacheKeysOutputDTO]
+//	@Failure		400		{object}	mappers.ErrorResponse
+//	@Failure		401		{object}	mappers.ErrorResponse
+//	@Failure		500		{object}	mappers.ErrorResponse
+//	@Router			/admin/cache/keys [get]
+func (h *AdminHandler) CacheKeys(c *fiber.Ctx) error {
+	pattern := cacheKeyPattern(c)
+	if pattern == "" {
+		pattern = cache.KeyPattern("")
+	}
+	limit, err := strconv.Atoi(c.Query("limit", "100"))
+	if err != nil || limit <= 0 {
+		log.Printf("Invalid limit: %s, defaulting to 100", c.Query("limit"))
+		limit = 100
+	}
+	keys, err := h.adminService.CacheKeys(c.Context(), pattern, limit)
+	if errors.Is(err, services.ErrForeignCachePattern) {
+		return c.Status(http.StatusBadRequest).JSON(mappers.ErrorResponse{Error: "Invalid pattern", Details: err.Error()})
+	}
+	if err != nil {
+		log.Printf("Error from AdminService: %v", err)
+		return c.Status(http.StatusInternalServerError).JSON(mappers.ErrorResponse{
+			Error:   "An unexpected error occurred",
+			Details: err.Error(),
+		})
+	}
+	return c.Status(http.StatusOK).JSON(mappers.CommonResponse[mappers.CacheKeysOutputDTO]{
+		Data:    mappers.CacheKeysOutputDTO{Pattern: pattern, Keys: keys},
+		Message: "Cache keys retrieved successfully",
+	})
+}
+
+// CacheEntry godoc
+//
+//	@Summary		Cache Entry
+//	@Description	get the value of a key of the cache, as listed by the cache keys, with its schema version and time to live. Keys must start with a schema version or with tag:
+//	@Security		AdminToken
+//	@Tags			admin
+//	@Produce		json
+//	@Param			key	path		string	true	"key of the cache, including its schema version"
+//	@Success		200	{object}	mappers.CommonResponse[mappers.CacheEntryOutputDTO]
+//	@Failure		400	{object}	mappers.ErrorResponse
+//	@Failure		401	{object}	mappers.ErrorResponse
+//	@Failure		404	{object}	mappers.ErrorResponse
+//	@Failure		500	{object}	mappers.ErrorResponse
+//	@Router			/admin/cache/keys/{key} [get]
+func (h *AdminHandler) CacheEntry(c *fiber.Ctx) error {
+	key, err := url.PathUnescape(c.Params("*"))
+	if err != nil || key == "" {
+		return c.Status(http.StatusBadRequest).JSON(mappers.ErrorResponse{Error: "Invalid cache key"})
+	}
+	entry, err := h.adminService.CacheEntry(c.Context(), key)
+	if errors.Is(err, services.ErrForeignCachePattern) {
+		return c.Status(http.StatusBadRequest).JSON(mappers.ErrorResponse{Error: "Invalid cache key", Details: err.Error()})
+	}
+	if errors.Is(err, services.ErrCacheKeyNotFound) {
+		return c.Status(http.StatusNotFound).JSON(mappers.ErrorResponse{Error: "Cache key not found"})
+	}
+	if err != nil {
+		log.Printf("Error from AdminService: %v", err)
+		return c.Status(http.StatusInternalServerError).JSON(mappers.ErrorResponse{
+			Error:   "An unexpected error occurred",
+			Details: err.Error(),
+		})
+	}
+	return c.Status(http.StatusOK).JSON(mappers.CommonResponse[mappers.CacheEntryOutputDTO]{
+		Data:    mappers.MapCacheEntryToOutputDTO(entry),
+		Message: "Cache entry retrieved successfully",
+	})
+}
+
+// PurgeCache godoc
+//
+//	@Summary		Purge Cache
+//	@Description	delete the keys of the cache matching a glob pattern, or the cache keys starting with a prefix whatever their schema version. One of them is required. Patterns must start with a schema version, such as v*:, or with tag:
+//	@Security		AdminToken
+//	@Tags			admin
+//	@Produce		json
+//	@Param			pattern	query		string	false	"glob pattern of the keys, such as v*:search:*"
+//	@Param			prefix	query		string	false	"prefix of the cache keys without their schema version, such as search:game"
+//	@Success		200		{object}	mappers.CommonResponse[mappers.CachePurgeOutputDTO]
+//	@Failure		400		{object}	mappers.ErrorResponse
+//	@Failure		401		{object}	mappers.ErrorResponse
+//	@Failure		500		{object}	mappers.ErrorResponse
+//	@Router			/admin/cache/keys [delete]
+func (h *AdminHandler) PurgeCache(c *fiber.Ctx) error {
+	pattern := cacheKeyPattern(c)
+	if pattern == "" {
+		return c.Status(http.StatusBadRequest).JSON(mappers.ErrorResponse{Error: "A pattern or a prefix is required"})
+	}
+	purged, err := h.adminService.PurgeCache(c.Context(), pattern)
+	if errors.Is(err, services.ErrForeignCachePattern) {
+		return c.Status(http.StatusBadRequest).JSON(mappers.ErrorResponse{Error: "Invalid pattern", Details: err.Error()})
+	}
+	if err != nil {
+		log.Printf("Error from AdminService: %v", err)
+		return c.Status(http.StatusInternalServerError).JSON(mappers.ErrorResponse{
+			Error:   "An unexpected error occurred",
+			Details: err.Error(),
+		})
+	}
+	return c.Status(http.StatusOK).JSON(mappers.CommonResponse[mappers.CachePurgeOutputDTO]{
+		Data:    mappers.CachePurgeOutputDTO{Pattern: pattern, Purged: purged},
+		Message: "Cache keys purged successfully",
+	})
+}
+
+// cacheKeyPattern returns the glob pattern given by the pattern or prefix query parameters,
+// empty when neither is.
+func cacheKeyPattern(c *fiber.Ctx) string {
+	if pattern := c.Query("pattern"); pattern != "" {
+		return pattern
+	}
+	if prefix := c.Query("prefix"); prefix != "" {
+		return cache.KeyPattern(prefix)
+	}
+	return ""
+}
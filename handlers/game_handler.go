@@ -1,11 +1,19 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
 	"log"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/melkdesousa/gamgo/dao/models"
+	"github.com/melkdesousa/gamgo/dao/search"
+	"github.com/melkdesousa/gamgo/external/providers"
 	"github.com/melkdesousa/gamgo/mappers"
 	"github.com/melkdesousa/gamgo/services"
 	"github.com/melkdesousa/gamgo/utils"
@@ -29,6 +37,8 @@ func NewGameHandler(app *fiber.App, gameService *services.GameService) {
 	})
 	app.Get("/games/search", handler.SearchGames)
 	app.Get("/games", handler.ListGames)
+	app.Get("/games/by-external/:source/:externalId", handler.GetGameByExternalID)
+	app.Get("/games/:id", handler.GetGame)
 }
 
 // SearchGames godoc
@@ -39,8 +49,12 @@ func NewGameHandler(app *fiber.App, gameService *services.GameService) {
 //	@Tags			games
 //	@Accept			json
 //	@Produce		json
-//	@Param			title	query		string	false	"game search by title"
-//	@Param			page	query		int		false	"page number, default is 1"
+//	@Param			title	query		string	false	"game search expression supporting quoted phrases, OR, -excluded and prefix* terms"
+//	@Param			page	query		int		false	"page number, default is 1, ignored when a cursor is given"
+//	@Param			limit	query		int		false	"page size, default is 10 and at most 50"
+//	@Param			cursor	query		string	false	"next or prev cursor returned with a previous page"
+//	@Param			sort	query		string	false	"sort field, default is relevance"	Enums(relevance, rating, releaseDate, title)
+//	@Param			order	query		string	false	"sort direction, defaults to desc except for title"	Enums(asc, desc)
 //	@Success		200		{array}		mappers.PaginationResponse[[]mappers.GameOutputDTO]
 //	@Failure		400		{object}	mappers.ErrorResponse
 //	@Failure		404		{object}	mappers.PaginationResponse[[]mappers.GameOutputDTO]
@@ -49,21 +63,36 @@ func NewGameHandler(app *fiber.App, gameService *services.GameService) {
 func (h *GameHandler) SearchGames(c *fiber.Ctx) error {
 	ctx := c.Context()
 	titleQuery := c.Query("title", "")
-	pageStr := c.Query("page", "1")
-	page, err := strconv.Atoi(pageStr)
-	if err != nil || page < 1 {
-		log.Printf("Invalid page number: %s, defaulting to 1", pageStr)
-		page = 1
-	}
-	sanitizedTitle := utils.Sanitize(titleQuery)
+	page := parsePageRequest(c)
+	sanitizedTitle := strings.TrimSpace(titleQuery)
 	if sanitizedTitle == "" {
 		return c.Status(http.StatusBadRequest).JSON(mappers.ErrorResponse{
-			Error:   "Title query parameter is required and cannot be empty after sanitization",
+			Error:   "Title query parameter is required and cannot be empty",
 			Details: "Please provide a valid title.",
 		})
 	}
-	log.Printf("Handler: Searching games with title (sanitized): '%s', page: %d", sanitizedTitle, page)
-	games, err := h.gameService.SearchGames(ctx, sanitizedTitle, page, pageStr)
+	sort, err := models.ParseGameSort(c.Query("sort"), c.Query("order"), true)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(mappers.ErrorResponse{
+			Error:   "Invalid sort parameters",
+			Details: err.Error(),
+		})
+	}
+	log.Printf("Handler: Searching games with title: '%s', page: %+v, sort: %s", sanitizedTitle, page, sort)
+	result, err := h.gameService.SearchGames(ctx, sanitizedTitle, page, sort)
+	var syntaxErr *search.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return c.Status(http.StatusBadRequest).JSON(mappers.ErrorResponse{
+			Error:   "Invalid search query",
+			Details: syntaxErr.Error(),
+		})
+	}
+	if errors.Is(err, models.ErrInvalidCursor) {
+		return c.Status(http.StatusBadRequest).JSON(mappers.ErrorResponse{
+			Error:   "Invalid cursor",
+			Details: err.Error(),
+		})
+	}
 	if err != nil {
 		log.Printf("Error from GameService: %v", err)
 		return c.Status(http.StatusInternalServerError).JSON(mappers.ErrorResponse{
@@ -71,68 +100,147 @@ func (h *GameHandler) SearchGames(c *fiber.Ctx) error {
 			Details: err.Error(),
 		})
 	}
-	if len(games) == 0 {
-		return c.Status(http.StatusNotFound).JSON(mappers.PaginationResponse[[]mappers.GameOutputDTO]{
-			CommonResponse: mappers.CommonResponse[[]mappers.GameOutputDTO]{
-				Data:    []mappers.GameOutputDTO{},
-				Message: "No games found matching your criteria",
-			},
-			Filters: fiber.Map{
-				"title": sanitizedTitle,
-				"page":  page,
-			},
-			Page:  page,
-			Count: 0,
+	filters := mappers.MapGameFilterToOutputDTO(models.GameFilter{Title: sanitizedTitle}, sort)
+	if len(result.Games) == 0 {
+		return c.Status(http.StatusNotFound).JSON(mappers.MapGamePageToResponse(result, page, filters, "No games found matching your criteria"))
+	}
+	return c.Status(http.StatusOK).JSON(mappers.MapGamePageToResponse(result, page, filters, "Games retrieved successfully"))
+}
+
+// GetGame godoc
+//
+//	@Summary		Get Game
+//	@Description	get a game and its details by id, fetching the details from RAWG when missing or stale
+//	@Security		JWT
+//	@Tags			games
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string	true	"game id"
+//	@Success		200	{object}	mappers.CommonResponse[mappers.GameDetailOutputDTO]
+//	@Failure		400	{object}	mappers.ErrorResponse
+//	@Failure		404	{object}	mappers.ErrorResponse
+//	@Failure		500	{object}	mappers.ErrorResponse
+//	@Router			/games/{id} [get]
+func (h *GameHandler) GetGame(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if err := uuid.Validate(id); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(mappers.ErrorResponse{
+			Error:   "Invalid game id",
+			Details: err.Error(),
 		})
 	}
-	return c.Status(http.StatusOK).JSON(mappers.PaginationResponse[[]mappers.GameOutputDTO]{
-		CommonResponse: mappers.CommonResponse[[]mappers.GameOutputDTO]{
-			Data:    mappers.MapGamesModelToOutputDTO(games),
-			Message: "Games retrieved successfully",
-		},
-		Filters: fiber.Map{
-			"title": sanitizedTitle,
-			"page":  page,
-		},
-		Page:  page,
-		Count: len(games),
+	game, err := h.gameService.GetGame(c.Context(), id)
+	return gameDetailResponse(c, game, err)
+}
+
+// GetGameByExternalID godoc
+//
+//	@Summary		Get Game By External ID
+//	@Description	get a game and its details by the id of the source it was imported from, importing RAWG games on demand
+//	@Security		JWT
+//	@Tags			games
+//	@Accept			json
+//	@Produce		json
+//	@Param			source		path		string	true	"external source, such as rawg"
+//	@Param			externalId	path		string	true	"id of the game in the external source"
+//	@Success		200			{object}	mappers.CommonResponse[mappers.GameDetailOutputDTO]
+//	@Failure		404			{object}	mappers.ErrorResponse
+//	@Failure		500			{object}	mappers.ErrorResponse
+//	@Failure		503			{object}	mappers.ErrorResponse
+//	@Router			/games/by-external/{source}/{externalId} [get]
+func (h *GameHandler) GetGameByExternalID(c *fiber.Ctx) error {
+	source := strings.ToLower(strings.TrimSpace(c.Params("source")))
+	externalID := strings.TrimSpace(c.Params("externalId"))
+	game, err := h.gameService.GetGameByExternalID(c.Context(), source, externalID)
+	return gameDetailResponse(c, game, err)
+}
+
+func gameDetailResponse(c *fiber.Ctx, game models.Game, err error) error {
+	if errors.Is(err, models.ErrGameNotFound) {
+		return c.Status(http.StatusNotFound).JSON(mappers.ErrorResponse{
+			Error: "Game not found",
+		})
+	}
+	if errors.Is(err, providers.ErrUnavailable) {
+		return c.Status(http.StatusServiceUnavailable).JSON(mappers.ErrorResponse{
+			Error: "Game is not stored and its provider is unavailable, try again later",
+		})
+	}
+	if err != nil {
+		log.Printf("Error from GameService: %v", err)
+		return c.Status(http.StatusInternalServerError).JSON(mappers.ErrorResponse{
+			Error:   "An unexpected error occurred",
+			Details: err.Error(),
+		})
+	}
+	return c.Status(http.StatusOK).JSON(mappers.CommonResponse[mappers.GameDetailOutputDTO]{
+		Data:    mappers.MapGameModelToDetailDTO(game),
+		Message: "Game retrieved successfully",
 	})
 }
 
 // ListGames godoc
 //
 //	@Summary		List Games
-//	@Description	get games
+//	@Description	get games matching every provided filter
 //	@Security		JWT
 //	@Tags			games
 //	@Accept			json
 //	@Produce		json
-//	@Param			title		query		string		false	"game search by title"
-//	@Param			platforms	query		[]string	false	"game search by platforms, comma-separated"
-//	@Param			page		query		int			false	"page number, default is 1"
-//	@Success		200			{array}		mappers.PaginationResponse[[]mappers.GameOutputDTO]
-//	@Failure		400			{object}	mappers.ErrorResponse
-//	@Failure		404			{object}	mappers.PaginationResponse[[]mappers.GameOutputDTO]
-//	@Failure		500			{object}	mappers.ErrorResponse
+//	@Param			title			query		string		false	"game search expression"
+//	@Param			platforms		query		[]string	false	"game platforms or platform families, comma-separated, see /platforms"
+//	@Param			platformMatch	query		string		false	"whether games must run on any or all of the platforms, default is any"	Enums(any, all)
+//	@Param			releasedFrom	query		string		false	"earliest release date, YYYY-MM-DD"
+//	@Param			releasedTo		query		string		false	"latest release date, YYYY-MM-DD"
+//	@Param			minRating		query		number		false	"minimum rating, from 0 to 5"
+//	@Param			maxRating		query		number		false	"maximum rating, from 0 to 5"
+//	@Param			source			query		string		false	"external source the game was imported from"
+//	@Param			genres			query		[]string	false	"genre slugs, comma-separated, games must have any of them"
+//	@Param			tags			query		[]string	false	"tag slugs, comma-separated, games must have any of them"
+//	@Param			developers		query		[]string	false	"developer slugs, comma-separated, games must have any of them"
+//	@Param			publishers		query		[]string	false	"publisher slugs, comma-separated, games must have any of them"
+//	@Param			page			query		int			false	"page number, default is 1, ignored when a cursor is given"
+//	@Param			limit			query		int			false	"page size, default is 10 and at most 50"
+//	@Param			cursor			query		string		false	"next or prev cursor returned with a previous page"
+//	@Param			sort			query		string		false	"sort field, defaults to relevance with a title and to title otherwise"	Enums(relevance, rating, releaseDate, title)
+//	@Param			order			query		string		false	"sort direction, defaults to desc except for title"						Enums(asc, desc)
+//	@Success		200				{array}		mappers.PaginationResponse[[]mappers.GameOutputDTO]
+//	@Failure		400				{object}	mappers.ErrorResponse
+//	@Failure		404				{object}	mappers.PaginationResponse[[]mappers.GameOutputDTO]
+//	@Failure		500				{object}	mappers.ErrorResponse
 //	@Router			/games [get]
 func (h *GameHandler) ListGames(c *fiber.Ctx) error {
 	ctx := c.Context()
-	pageStr := c.Query("page", "1")          // Default page to "1"
-	platformsStr := c.Query("platforms", "") // Default to empty string if not provided
-	title := c.Query("title", "")            // Default to empty string if not provided
-	if platformsStr == "" && title == "" {
+	page := parsePageRequest(c)
+	filter, err := parseGameFilter(c)
+	if err != nil {
 		return c.Status(http.StatusBadRequest).JSON(mappers.ErrorResponse{
-			Error:   "At least one of 'platforms' or 'title' query parameters must be provided",
-			Details: "Please provide at least one search criterion.",
+			Error:   "Invalid filter parameters",
+			Details: err.Error(),
 		})
 	}
-	page, err := strconv.Atoi(pageStr)
-	if err != nil || page < 1 {
-		page = 1 // Default to page 1 if conversion fails or page is invalid
+	sort, err := models.ParseGameSort(c.Query("sort"), c.Query("order"), filter.HasTitle())
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(mappers.ErrorResponse{
+			Error:   "Invalid sort parameters",
+			Details: err.Error(),
+		})
+	}
+	log.Printf("Handler: Listing games with page: %+v, filter: %+v, sort: %s", page, filter, sort)
+	result, err := h.gameService.ListGames(ctx, filter, sort, page)
+	var syntaxErr *search.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return c.Status(http.StatusBadRequest).JSON(mappers.ErrorResponse{
+			Error:   "Invalid title query",
+			Details: syntaxErr.Error(),
+		})
+	}
+	if errors.Is(err, models.ErrInvalidCursor) {
+		return c.Status(http.StatusBadRequest).JSON(mappers.ErrorResponse{
+			Error:   "Invalid cursor",
+			Details: err.Error(),
+		})
 	}
-	platforms := utils.SanitizeArrayStrings(platformsStr)
-	log.Printf("Handler: Listing games with page: %d, platforms: '%s', title: '%s'", page, platforms, title)
-	games, total, err := h.gameService.ListGames(ctx, page, platforms, title)
 	if err != nil {
 		log.Printf("Error from GameService: %v", err)
 		return c.Status(http.StatusInternalServerError).JSON(mappers.ErrorResponse{
@@ -140,32 +248,84 @@ func (h *GameHandler) ListGames(c *fiber.Ctx) error {
 			Details: err.Error(),
 		})
 	}
-	if len(games) == 0 {
-		return c.Status(http.StatusNotFound).JSON(mappers.PaginationResponse[[]mappers.GameOutputDTO]{
-			CommonResponse: mappers.CommonResponse[[]mappers.GameOutputDTO]{
-				Data:    []mappers.GameOutputDTO{},
-				Message: "No games found matching your criteria",
-			},
-			Filters: fiber.Map{
-				"platforms": platforms,
-				"title":     title,
-			},
-			Page:  page,
-			Total: 0,
-			Count: 0,
-		})
+	filters := mappers.MapGameFilterToOutputDTO(filter, sort)
+	if len(result.Games) == 0 {
+		return c.Status(http.StatusNotFound).JSON(mappers.MapGamePageToResponse(result, page, filters, "No games found matching your criteria"))
 	}
-	return c.Status(http.StatusOK).JSON(mappers.PaginationResponse[[]mappers.GameOutputDTO]{
-		CommonResponse: mappers.CommonResponse[[]mappers.GameOutputDTO]{
-			Data:    mappers.MapGamesModelToOutputDTO(games),
-			Message: "Games retrieved successfully",
-		},
-		Filters: fiber.Map{
-			"platforms": platforms,
-			"title":     title,
-		},
-		Page:  page,
-		Total: total,
-		Count: len(games),
-	})
+	return c.Status(http.StatusOK).JSON(mappers.MapGamePageToResponse(result, page, filters, "Games retrieved successfully"))
+}
+
+// parsePageRequest reads page, limit and cursor from the query string, falling back
+// to the defaults for missing or invalid values.
+func parsePageRequest(c *fiber.Ctx) models.PageRequest {
+	page, err := strconv.Atoi(c.Query("page", "1"))
+	if err != nil {
+		log.Printf("Invalid page number: %s, defaulting to 1", c.Query("page"))
+	}
+	limit, err := strconv.Atoi(c.Query("limit", strconv.Itoa(models.DefaultPageSize)))
+	if err != nil {
+		log.Printf("Invalid limit: %s, defaulting to %d", c.Query("limit"), models.DefaultPageSize)
+	}
+	return models.NewPageRequest(page, limit, c.Query("cursor"))
+}
+
+// parseGameFilter reads the listing filters from the query string.
+func parseGameFilter(c *fiber.Ctx) (models.GameFilter, error) {
+	filter := models.GameFilter{
+		Title:          strings.TrimSpace(c.Query("title")),
+		Platforms:      utils.SanitizeArrayStrings(c.Query("platforms")),
+		PlatformMatch:  models.PlatformMatch(c.Query("platformMatch", string(models.PlatformMatchAny))),
+		ExternalSource: strings.TrimSpace(c.Query("source")),
+		Genres:         utils.SanitizeSlugs(c.Query("genres")),
+		Tags:           utils.SanitizeSlugs(c.Query("tags")),
+		Developers:     utils.SanitizeSlugs(c.Query("developers")),
+		Publishers:     utils.SanitizeSlugs(c.Query("publishers")),
+	}
+	if filter.PlatformMatch != models.PlatformMatchAny && filter.PlatformMatch != models.PlatformMatchAll {
+		return filter, fmt.Errorf("invalid platformMatch %q, expected any or all", filter.PlatformMatch)
+	}
+	var err error
+	if filter.ReleasedFrom, err = parseDateQuery(c, "releasedFrom"); err != nil {
+		return filter, err
+	}
+	if filter.ReleasedTo, err = parseDateQuery(c, "releasedTo"); err != nil {
+		return filter, err
+	}
+	if filter.ReleasedFrom != nil && filter.ReleasedTo != nil && filter.ReleasedFrom.After(*filter.ReleasedTo) {
+		return filter, errors.New("releasedFrom must not be after releasedTo")
+	}
+	if filter.MinRating, err = parseRatingQuery(c, "minRating"); err != nil {
+		return filter, err
+	}
+	if filter.MaxRating, err = parseRatingQuery(c, "maxRating"); err != nil {
+		return filter, err
+	}
+	if filter.MinRating != nil && filter.MaxRating != nil && *filter.MinRating > *filter.MaxRating {
+		return filter, errors.New("minRating must not be greater than maxRating")
+	}
+	return filter, nil
+}
+
+func parseDateQuery(c *fiber.Ctx, key string) (*time.Time, error) {
+	value := c.Query(key)
+	if value == "" {
+		return nil, nil
+	}
+	date, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s %q, expected a YYYY-MM-DD date", key, value)
+	}
+	return &date, nil
+}
+
+func parseRatingQuery(c *fiber.Ctx, key string) (*float64, error) {
+	value := c.Query(key)
+	if value == "" {
+		return nil, nil
+	}
+	rating, err := strconv.ParseFloat(value, 64)
+	if err != nil || rating < 0 || rating > 5 {
+		return nil, fmt.Errorf("invalid %s %q, expected a number from 0 to 5", key, value)
+	}
+	return &rating, nil
 }
@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/melkdesousa/gamgo/mappers"
+	"github.com/melkdesousa/gamgo/services"
+)
+
+// HealthHandler reports the health of the service.
+type HealthHandler struct {
+	app   *fiber.App
+	cache services.CacheHealth
+}
+
+// NewHealthHandler creates a new HealthHandler. It must be registered before the JWT
+// middleware.
+func NewHealthHandler(app *fiber.App, cache services.CacheHealth) {
+	handler := &HealthHandler{
+		app:   app,
+		cache: cache,
+	}
+	handler.app.Get("/health", handler.Health)
+}
+
+// Health godoc
+//
+//	@Summary		Health
+//	@Description	check that the service is up. While the cache is down, searches are served from the database and the status is degraded
+//	@Tags			health
+//	@Produce		json
+//	@Success		200	{object}	mappers.HealthResponse
+//	@Router			/health [get]
+func (h *HealthHandler) Health(c *fiber.Ctx) error {
+	response := mappers.HealthResponse{Status: "ok", Cache: "up"}
+	if h.cache.Degraded() {
+		response = mappers.HealthResponse{Status: "degraded", Cache: "down"}
+	}
+	return c.Status(http.StatusOK).JSON(response)
+}
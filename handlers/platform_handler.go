@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/melkdesousa/gamgo/mappers"
+	"github.com/melkdesousa/gamgo/services"
+)
+
+// PlatformHandler handles HTTP requests related to the platform catalog.
+type PlatformHandler struct {
+	app             *fiber.App
+	platformService *services.PlatformService
+}
+
+// NewPlatformHandler creates a new PlatformHandler.
+func NewPlatformHandler(app *fiber.App, platformService *services.PlatformService) {
+	handler := &PlatformHandler{
+		app:             app,
+		platformService: platformService,
+	}
+	app.Get("/platforms", handler.ListPlatforms)
+}
+
+// ListPlatforms godoc
+//
+//	@Summary		List Platforms
+//	@Description	get the platform families with their platforms and the number of games of each
+//	@Security		JWT
+//	@Tags			platforms
+//	@Produce		json
+//	@Success		200	{object}	mappers.CommonResponse[[]mappers.PlatformOutputDTO]
+//	@Failure		500	{object}	mappers.ErrorResponse
+//	@Router			/platforms [get]
+func (h *PlatformHandler) ListPlatforms(c *fiber.Ctx) error {
+	platforms, err := h.platformService.ListPlatforms(c.Context())
+	if err != nil {
+		log.Printf("Error from PlatformService: %v", err)
+		return c.Status(http.StatusInternalServerError).JSON(mappers.ErrorResponse{
+			Error:   "An unexpected error occurred",
+			Details: err.Error(),
+		})
+	}
+	return c.Status(http.StatusOK).JSON(mappers.CommonResponse[[]mappers.PlatformOutputDTO]{
+		Data:    mappers.MapPlatformsToOutputDTO(platforms),
+		Message: "Platforms retrieved successfully",
+	})
+}
@@ -15,9 +15,6 @@ func NewSwaggerHandler(app *fiber.App) {
 		app: app,
 	}
 	handler.setupRoutes()
-	handler.app.Get("/health", func(c *fiber.Ctx) error {
-		return c.SendString("OK")
-	})
 }
 
 // @title						Gamgo API
@@ -34,6 +31,11 @@ func NewSwaggerHandler(app *fiber.App) {
 // @in							header
 // @name						Authorization
 // @description				Enter your JWT token in the format: Bearer \<token\>
+//
+// @securityDefinitions.apikey	AdminToken
+// @in							header
+// @name						X-Admin-Token
+// @description				Token of the administration endpoints, set through ADMIN_TOKEN
 func (h *SwaggerHandler) setupRoutes() {
 	h.app.Get("/swagger/*", swagger.HandlerDefault) // default
 }
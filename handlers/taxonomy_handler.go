@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/melkdesousa/gamgo/dao/models"
+	"github.com/melkdesousa/gamgo/mappers"
+	"github.com/melkdesousa/gamgo/services"
+)
+
+// TaxonomyHandler handles HTTP requests listing genres, tags, developers and publishers.
+type TaxonomyHandler struct {
+	app         *fiber.App
+	gameService *services.GameService
+}
+
+// NewTaxonomyHandler creates a new TaxonomyHandler.
+func NewTaxonomyHandler(app *fiber.App, gameService *services.GameService) {
+	handler := &TaxonomyHandler{
+		app:         app,
+		gameService: gameService,
+	}
+	app.Get("/genres", handler.ListGenres)
+	app.Get("/tags", handler.ListTags)
+	app.Get("/developers", handler.ListDevelopers)
+	app.Get("/publishers", handler.ListPublishers)
+}
+
+// ListGenres godoc
+//
+//	@Summary		List Genres
+//	@Description	get genres with their number of games, the most used first
+//	@Security		JWT
+//	@Tags			taxonomies
+//	@Produce		json
+//	@Param			page	query		int	false	"page number, default is 1"
+//	@Param			limit	query		int	false	"page size, default is 10 and at most 50"
+//	@Success		200		{object}	mappers.PaginationResponse[[]mappers.TaxonomyOutputDTO]
+//	@Failure		500		{object}	mappers.ErrorResponse
+//	@Router			/genres [get]
+func (h *TaxonomyHandler) ListGenres(c *fiber.Ctx) error {
+	return h.listTaxonomies(c, models.TaxonomyGenre)
+}
+
+// ListTags godoc
+//
+//	@Summary		List Tags
+//	@Description	get tags with their number of games, the most used first
+//	@Security		JWT
+//	@Tags			taxonomies
+//	@Produce		json
+//	@Param			page	query		int	false	"page number, default is 1"
+//	@Param			limit	query		int	false	"page size, default is 10 and at most 50"
+//	@Success		200		{object}	mappers.PaginationResponse[[]mappers.TaxonomyOutputDTO]
+//	@Failure		500		{object}	mappers.ErrorResponse
+//	@Router			/tags [get]
+func (h *TaxonomyHandler) ListTags(c *fiber.Ctx) error {
+	return h.listTaxonomies(c, models.TaxonomyTag)
+}
+
+// ListDevelopers godoc
+//
+//	@Summary		List Developers
+//	@Description	get developers with their number of games, the most prolific first
+//	@Security		JWT
+//	@Tags			taxonomies
+//	@Produce		json
+//	@Param			page	query		int	false	"page number, default is 1"
+//	@Param			limit	query		int	false	"page size, default is 10 and at most 50"
+//	@Success		200		{object}	mappers.PaginationResponse[[]mappers.TaxonomyOutputDTO]
+//	@Failure		500		{object}	mappers.ErrorResponse
+//	@Router			/developers [get]
+func (h *TaxonomyHandler) ListDevelopers(c *fiber.Ctx) error {
+	return h.listTaxonomies(c, models.TaxonomyDeveloper)
+}
+
+// ListPublishers godoc
+//
+//	@Summary		List Publishers
+//	@Description	get publishers with their number of games, the most prolific first
+//	@Security		JWT
+//	@Tags			taxonomies
+//	@Produce		json
+//	@Param			page	query		int	false	"page number, default is 1"
+//	@Param			limit	query		int	false	"page size, default is 10 and at most 50"
+//	@Success		200		{object}	mappers.PaginationResponse[[]mappers.TaxonomyOutputDTO]
+//	@Failure		500		{object}	mappers.ErrorResponse
+//	@Router			/publishers [get]
+func (h *TaxonomyHandler) ListPublishers(c *fiber.Ctx) error {
+	return h.listTaxonomies(c, models.TaxonomyPublisher)
+}
+
+func (h *TaxonomyHandler) listTaxonomies(c *fiber.Ctx, kind models.TaxonomyKind) error {
+	page := parsePageRequest(c)
+	page.Cursor = "" // taxonomy listings are paginated by number only
+	result, err := h.gameService.ListTaxonomies(c.Context(), kind, page)
+	if err != nil {
+		log.Printf("Error from GameService: %v", err)
+		return c.Status(http.StatusInternalServerError).JSON(mappers.ErrorResponse{
+			Error:   "An unexpected error occurred",
+			Details: err.Error(),
+		})
+	}
+	message := strings.ToUpper(string(kind[:1])) + string(kind[1:]) + " retrieved successfully"
+	return c.Status(http.StatusOK).JSON(mappers.MapTaxonomyPageToResponse(result, page, message))
+}
@@ -3,15 +3,18 @@ package main
 import (
 	"context"
 	"log"
+	"time"
 
 	jwtware "github.com/gofiber/contrib/jwt"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/recover"
 	"github.com/gofiber/template/html/v2"
 	"github.com/joho/godotenv"
+	"github.com/melkdesousa/gamgo/cache"
 	"github.com/melkdesousa/gamgo/config"
 	"github.com/melkdesousa/gamgo/dao"
 	"github.com/melkdesousa/gamgo/database"
+	"github.com/melkdesousa/gamgo/external/providers"
 	"github.com/melkdesousa/gamgo/external/rawg"
 	"github.com/melkdesousa/gamgo/handlers"
 	"github.com/melkdesousa/gamgo/services"
@@ -28,12 +31,10 @@ func main() {
 	if err := godotenv.Load(); err != nil {
 		log.Println("Warning: No .env file found or error loading .env file")
 	}
-	dbConn := database.GetDBConnection()
+	dbPool := database.GetDBConnection()
 	defer func() {
-		if err := dbConn.Close(context.Background()); err != nil {
-			log.Fatalf("Failed to close database connection: %v", err)
-		}
-		log.Println("Database connection closed.")
+		dbPool.Close()
+		log.Println("Database connection pool closed.")
 	}()
 	cacheClient := database.GetCacheConnection()
 	defer func() {
@@ -42,11 +43,35 @@ func main() {
 		}
 		log.Println("Cache connection closed.")
 	}()
-	gameDAO := dao.NewGameDAO(dbConn)
-	accountDAO := dao.NewAccountDAO(dbConn)
-	rawgAPI := rawg.NewRawgAPI()
-	gameService := services.NewGameService(gameDAO, cacheClient, rawgAPI)
+	gameDAO := dao.NewGameDAO(dbPool)
+	accountDAO := dao.NewAccountDAO(dbPool)
+	platformDAO := dao.NewPlatformDAO(dbPool)
+	rawgAPI := rawg.NewRawgAPI(rawg.NewRedisUsageStore(cacheClient))
+	gameProviders, err := providers.NewRegistryFromEnv(rawgAPI)
+	if err != nil {
+		log.Fatalf("Failed to configure game providers: %v", err)
+	}
+	resilientCache := database.NewResilientCache(cacheClient)
+	tieredCache := database.NewTieredCache(resilientCache)
+	go tieredCache.Listen(context.Background(), cacheClient)
+	cacheStore := cache.NewRedisStore(tieredCache)
+	locker := database.NewRedisLocker(resilientCache)
+	popularity := dao.NewPopularityDAO(dbPool, config.GetEnvOrDefault("WARMUP_TRACKED_REQUESTS", 1000), time.Duration(config.GetEnvOrDefault("POPULARITY_HALF_LIFE_HOURS", 24))*time.Hour)
+	gameService := services.NewGameService(gameDAO, cacheStore, locker, popularity, gameProviders)
+	warmer := services.NewWarmer(gameService, popularity, locker)
+	if config.GetEnvOrDefault("WARMUP_ON_STARTUP", false) {
+		go func() {
+			if _, err := warmer.Warm(context.Background()); err != nil {
+				log.Printf("Error warming the cache up at startup: %v", err)
+			}
+		}()
+	}
+	if interval := config.GetEnvOrDefault("WARMUP_INTERVAL_MINUTES", 0); interval > 0 {
+		go warmer.Run(context.Background(), time.Duration(interval)*time.Minute)
+	}
 	accountService := services.NewAccountService(accountDAO)
+	platformService := services.NewPlatformService(platformDAO, rawgAPI)
+	adminService := services.NewAdminService(rawgAPI, cacheStore)
 	engine := html.New("views", ".html")
 	app := fiber.New(fiber.Config{
 		AppName: "gamgo",
@@ -54,10 +79,14 @@ func main() {
 	})
 	app.Static("/static", "./views/static")
 	handlers.NewSwaggerHandler(app)
+	handlers.NewHealthHandler(app, resilientCache)
 	handlers.NewAuthHandler(app, accountService)
 	app.Use(recover.New())
+	handlers.NewAdminHandler(app, adminService)
 	app.Use(JWTProtection)
 	handlers.NewGameHandler(app, gameService)
+	handlers.NewTaxonomyHandler(app, gameService)
+	handlers.NewPlatformHandler(app, platformService)
 	log.Println("Starting server on port :3000")
 	if err := app.Listen(":3000"); err != nil {
 		log.Fatalf("Failed to start server: %v", err)
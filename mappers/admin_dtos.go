@@ -0,0 +1,44 @@
+package mappers
+
+import "encoding/json"
+
+// RawgKeyUsageOutputDTO is the consumption of a RAWG API key in the current month.
+type RawgKeyUsageOutputDTO struct {
+	Key       string `json:"key"` // masked down to its last characters
+	Period    string `json:"period"`
+	Used      int64  `json:"used"`
+	Budget    int64  `json:"budget"`    // zero when unlimited
+	Remaining int64  `json:"remaining"` // zero when unlimited
+	SoftLimit int64  `json:"softLimit"`
+	HardLimit int64  `json:"hardLimit"`
+	Status    string `json:"status" enums:"ok,soft_limit,exhausted"`
+}
+
+// CacheMetricsOutputDTO is the counters of the search cache of the instance since it started.
+type CacheMetricsOutputDTO struct {
+	Counters map[string]int64 `json:"counters"`
+	// HitRatio is the share of the searches served from the cache, fresh, stale or empty
+	HitRatio  float64 `json:"hitRatio"`
+	MissRatio float64 `json:"missRatio"`
+}
+
+// CacheKeysOutputDTO is a listing of the keys of the cache.
+type CacheKeysOutputDTO struct {
+	Pattern string   `json:"pattern"`
+	Keys    []string `json:"keys"`
+}
+
+// CacheEntryOutputDTO is a value of the cache.
+type CacheEntryOutputDTO struct {
+	Key string `json:"key"`
+	// Version is the schema version of the value, empty for keys outside of typed caches
+	Version    string          `json:"version"`
+	TTLSeconds int64           `json:"ttlSeconds"` // zero when it never expires
+	Value      json.RawMessage `json:"value" swaggertype:"object"`
+}
+
+// CachePurgeOutputDTO is the result of a purge of the cache.
+type CachePurgeOutputDTO struct {
+	Pattern string `json:"pattern"`
+	Purged  int    `json:"purged"`
+}
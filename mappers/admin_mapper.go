@@ -0,0 +1,55 @@
+package mappers
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/melkdesousa/gamgo/cache"
+	"github.com/melkdesousa/gamgo/external/rawg"
+)
+
+// MapRawgKeyUsageToOutputDTO converts the consumption of RAWG API keys into output DTOs.
+func MapRawgKeyUsageToOutputDTO(usage []rawg.KeyUsage) []RawgKeyUsageOutputDTO {
+	dtos := make([]RawgKeyUsageOutputDTO, len(usage))
+	for i, key := range usage {
+		dtos[i] = RawgKeyUsageOutputDTO{
+			Key:       key.Key,
+			Period:    key.Period,
+			Used:      key.Used,
+			Budget:    key.Budget,
+			Remaining: max(key.HardLimit-key.Used, 0),
+			SoftLimit: key.SoftLimit,
+			HardLimit: key.HardLimit,
+			Status:    key.Status,
+		}
+	}
+	return dtos
+}
+
+// MapCacheMetricsToOutputDTO converts the counters of the search cache into an output DTO,
+// computing its hit and miss ratios.
+func MapCacheMetricsToOutputDTO(counters map[string]int64) CacheMetricsOutputDTO {
+	dto := CacheMetricsOutputDTO{Counters: counters}
+	hits := counters["hits_fresh"] + counters["hits_stale"] + counters["hits_negative"]
+	if lookups := hits + counters["misses"]; lookups > 0 {
+		dto.HitRatio = float64(hits) / float64(lookups)
+		dto.MissRatio = 1 - dto.HitRatio
+	}
+	return dto
+}
+
+// MapCacheEntryToOutputDTO converts an entry of the cache into an output DTO. Values that
+// are not JSON are returned as strings.
+func MapCacheEntryToOutputDTO(entry cache.Entry) CacheEntryOutputDTO {
+	version, _ := cache.SplitKey(entry.Key)
+	value := json.RawMessage(entry.Value)
+	if !json.Valid(entry.Value) {
+		value, _ = json.Marshal(string(entry.Value))
+	}
+	return CacheEntryOutputDTO{
+		Key:        entry.Key,
+		Version:    version,
+		TTLSeconds: int64(entry.TTL / time.Second),
+		Value:      value,
+	}
+}
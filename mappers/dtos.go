@@ -17,8 +17,18 @@ type ErrorResponse struct {
 
 type PaginationResponse[D any] struct {
 	CommonResponse[D]
-	Page    int         `json:"page"`
+	Page    int         `json:"page,omitempty"` // only set when paginating by page number
+	Limit   int         `json:"limit"`
 	Total   int         `json:"total,omitempty"`
 	Count   int         `json:"count"`
+	Next    string      `json:"next,omitempty"` // cursor of the next page, pass it back as the cursor parameter
+	Prev    string      `json:"prev,omitempty"` // cursor of the previous page
 	Filters interface{} `json:"filters"`
 }
+
+// HealthResponse reports whether the service runs in degraded mode, still serving requests
+// without some of its dependencies.
+type HealthResponse struct {
+	Status string `json:"status" enums:"ok,degraded"`
+	Cache  string `json:"cache" enums:"up,down"`
+}
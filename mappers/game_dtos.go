@@ -5,6 +5,48 @@ type GameOutputDTO struct {
 	Title      string   `json:"title"`
 	Released   string   `json:"released"`
 	Platforms  []string `json:"platforms"`
-	Rating     float64  `json:"rating"`
+	Rating     float64  `json:"rating"` // out of 5, with the decimals of the stored hundredths
 	CoverImage string   `json:"coverImage"`
+	Snippet    string   `json:"snippet,omitempty"` // matched text, terms wrapped in <mark> tags
+}
+
+// GameDetailOutputDTO is a single game along with its details.
+type GameDetailOutputDTO struct {
+	GameOutputDTO
+	Description    string              `json:"description,omitempty"`
+	Website        string              `json:"website,omitempty"`
+	Metacritic     int                 `json:"metacritic,omitempty"`
+	Playtime       int                 `json:"playtime,omitempty"` // average hours
+	ExternalID     string              `json:"externalId,omitempty"`
+	ExternalSource string              `json:"externalSource,omitempty"`
+	Genres         []TaxonomyOutputDTO `json:"genres"`
+	Tags           []TaxonomyOutputDTO `json:"tags"`
+	Developers     []TaxonomyOutputDTO `json:"developers"`
+	Publishers     []TaxonomyOutputDTO `json:"publishers"`
+}
+
+// TaxonomyOutputDTO is a genre, tag, developer or publisher. Its slug is the value of
+// the matching filter of the game listing.
+type TaxonomyOutputDTO struct {
+	Slug       string `json:"slug"`
+	Name       string `json:"name"`
+	GamesCount int    `json:"gamesCount,omitempty"` // only set by taxonomy listings
+}
+
+// GameFiltersOutputDTO echoes the filters and ordering applied to a game listing.
+type GameFiltersOutputDTO struct {
+	Title         string   `json:"title,omitempty"`
+	Platforms     []string `json:"platforms,omitempty"`
+	PlatformMatch string   `json:"platformMatch,omitempty"`
+	ReleasedFrom  string   `json:"releasedFrom,omitempty"`
+	ReleasedTo    string   `json:"releasedTo,omitempty"`
+	MinRating     *float64 `json:"minRating,omitempty"`
+	MaxRating     *float64 `json:"maxRating,omitempty"`
+	Source        string   `json:"source,omitempty"`
+	Genres        []string `json:"genres,omitempty"`
+	Tags          []string `json:"tags,omitempty"`
+	Developers    []string `json:"developers,omitempty"`
+	Publishers    []string `json:"publishers,omitempty"`
+	Sort          string   `json:"sort"`
+	Order         string   `json:"order"`
 }
@@ -8,6 +8,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/melkdesousa/gamgo/dao/models"
 	"github.com/melkdesousa/gamgo/external/rawg"
+	"github.com/melkdesousa/gamgo/utils"
 )
 
 func MapGamesModelToOutputDTO(games []models.Game) []GameOutputDTO {
@@ -18,41 +19,190 @@ func MapGamesModelToOutputDTO(games []models.Game) []GameOutputDTO {
 			Title:      game.Title,
 			Released:   game.ReleaseDate.Format(time.DateOnly),
 			Platforms:  game.Platforms,
-			Rating:     float64(game.Rating / 100),
+			Rating:     float64(game.Rating) / 100,
 			CoverImage: game.CoverImage,
+			Snippet:    game.Snippet,
 		}
 	}
 	return gamesMap
 }
 
-// MapGameInputDTOToModel converts a game result from the external RAWG API to our internal Game model.
-func MapGameInputDTOToModel(gameJSON rawg.Result) models.Game {
-	platforms := make([]string, len(gameJSON.Platforms))
-	for i, p := range gameJSON.Platforms {
-		platforms[i] = p.Platform.Name
+// MapGameModelToDetailDTO converts a game and its details into the output DTO.
+func MapGameModelToDetailDTO(game models.Game) GameDetailOutputDTO {
+	return GameDetailOutputDTO{
+		GameOutputDTO:  MapGamesModelToOutputDTO([]models.Game{game})[0],
+		Description:    game.Description,
+		Website:        game.Website,
+		Metacritic:     game.Metacritic,
+		Playtime:       game.Playtime,
+		ExternalID:     game.ExternalID,
+		ExternalSource: game.ExternalSource,
+		Genres:         MapTaxonomiesToOutputDTO(game.Genres),
+		Tags:           MapTaxonomiesToOutputDTO(game.Tags),
+		Developers:     MapTaxonomiesToOutputDTO(game.Developers),
+		Publishers:     MapTaxonomiesToOutputDTO(game.Publishers),
 	}
+}
 
-	var releaseDateParsed time.Time
-	if gameJSON.Released != "" {
-		parsedTime, err := time.Parse(time.DateOnly, gameJSON.Released)
-		if err != nil {
-			log.Printf("Warning: Error parsing release date '%s': %v. Falling back to empty date.", gameJSON.Released, err)
-			releaseDateParsed = time.Time{} // Zero value for time
-		} else {
-			releaseDateParsed = parsedTime
-		}
+// MapTaxonomiesToOutputDTO converts taxonomies into output DTOs, never returning nil.
+func MapTaxonomiesToOutputDTO(taxonomies []models.Taxonomy) []TaxonomyOutputDTO {
+	dtos := make([]TaxonomyOutputDTO, len(taxonomies))
+	for i, taxonomy := range taxonomies {
+		dtos[i] = TaxonomyOutputDTO{Slug: taxonomy.Slug, Name: taxonomy.Name, GamesCount: taxonomy.GamesCount}
 	}
+	return dtos
+}
 
+// MapTaxonomyPageToResponse converts a page of taxonomies into the paginated response body.
+func MapTaxonomyPageToResponse(page models.TaxonomyPage, request models.PageRequest, message string) PaginationResponse[[]TaxonomyOutputDTO] {
+	return PaginationResponse[[]TaxonomyOutputDTO]{
+		CommonResponse: CommonResponse[[]TaxonomyOutputDTO]{
+			Data:    MapTaxonomiesToOutputDTO(page.Items),
+			Message: message,
+		},
+		Page:  request.Number,
+		Limit: request.Limit,
+		Total: page.Total,
+		Count: len(page.Items),
+	}
+}
+
+// MapGamePageToResponse converts a page of games into the paginated response body.
+func MapGamePageToResponse(page models.GamePage, request models.PageRequest, filters any, message string) PaginationResponse[[]GameOutputDTO] {
+	response := PaginationResponse[[]GameOutputDTO]{
+		CommonResponse: CommonResponse[[]GameOutputDTO]{
+			Data:    MapGamesModelToOutputDTO(page.Games),
+			Message: message,
+		},
+		Limit:   request.Limit,
+		Total:   page.Total,
+		Count:   len(page.Games),
+		Next:    page.Next,
+		Prev:    page.Prev,
+		Filters: filters,
+	}
+	if request.Cursor == "" {
+		response.Page = request.Number
+	}
+	return response
+}
+
+// MapGameFilterToOutputDTO converts the filter and sort applied to a listing into the echoed filters.
+func MapGameFilterToOutputDTO(filter models.GameFilter, sort models.GameSort) GameFiltersOutputDTO {
+	filters := GameFiltersOutputDTO{
+		Title:      filter.Title,
+		Platforms:  filter.Platforms,
+		MinRating:  filter.MinRating,
+		MaxRating:  filter.MaxRating,
+		Source:     filter.ExternalSource,
+		Genres:     filter.Genres,
+		Tags:       filter.Tags,
+		Developers: filter.Developers,
+		Publishers: filter.Publishers,
+		Sort:       string(sort.Field),
+		Order:      sort.Order(),
+	}
+	if len(filter.Platforms) > 0 {
+		filters.PlatformMatch = string(filter.PlatformMatch)
+	}
+	if filter.ReleasedFrom != nil {
+		filters.ReleasedFrom = filter.ReleasedFrom.Format(time.DateOnly)
+	}
+	if filter.ReleasedTo != nil {
+		filters.ReleasedTo = filter.ReleasedTo.Format(time.DateOnly)
+	}
+	return filters
+}
+
+// MapGameInputDTOToModel converts a game result from the external RAWG API to our internal Game model.
+func MapGameInputDTOToModel(gameJSON rawg.Result) models.Game {
 	return models.Game{
 		ID:             uuid.NewString(), // Generate a new UUID for our internal storage
 		Title:          gameJSON.Name,
-		ReleaseDate:    releaseDateParsed,
-		Platforms:      platforms,
+		ReleaseDate:    parseReleaseDate(gameJSON.Released),
+		Platforms:      mapPlatformNames(gameJSON.Platforms),
 		Rating:         int(gameJSON.Rating * 100), // Example: convert 4.5 to 450
 		ExternalID:     strconv.Itoa(gameJSON.ID),
 		CoverImage:     gameJSON.BackgroundImage,
-		ExternalSource: gameJSON.Slug,
+		ExternalSource: rawg.SourceName,
+		ExternalSlug:   gameJSON.Slug,
+		Genres:         mapTaxonomies(gameJSON.Genres),
+		Tags:           mapTags(gameJSON.Tags),
+	}
+}
+
+// MapGameDetailJSONToModel converts the details of a game from the external RAWG API to our internal Game model.
+func MapGameDetailJSONToModel(detail rawg.GameDetailResponse) models.Game {
+	description := detail.DescriptionRaw
+	if description == "" {
+		description = utils.StripHTML(detail.Description)
+	}
+	return models.Game{
+		ID:             uuid.NewString(),
+		Title:          detail.Name,
+		Description:    description,
+		ReleaseDate:    parseReleaseDate(detail.Released),
+		Platforms:      mapPlatformNames(detail.Platforms),
+		Rating:         int(detail.Rating * 100),
+		ExternalID:     strconv.Itoa(detail.ID),
+		CoverImage:     detail.BackgroundImage,
+		ExternalSource: rawg.SourceName,
+		ExternalSlug:   detail.Slug,
+		Website:        detail.Website,
+		Metacritic:     detail.Metacritic,
+		Playtime:       detail.Playtime,
+		Genres:         mapTaxonomies(detail.Genres),
+		Tags:           mapTags(detail.Tags),
+		Developers:     mapTaxonomies(detail.Developers),
+		Publishers:     mapTaxonomies(detail.Publishers),
+	}
+}
+
+// mapTaxonomies converts named RAWG entities to taxonomies, keeping a nil slice nil so
+// taxonomies missing from a response are not cleared.
+func mapTaxonomies(entities []rawg.Genre) []models.Taxonomy {
+	if entities == nil {
+		return nil
+	}
+	taxonomies := make([]models.Taxonomy, len(entities))
+	for i, entity := range entities {
+		taxonomies[i] = models.Taxonomy{Slug: entity.Slug, Name: entity.Name}
+	}
+	return taxonomies
+}
+
+// mapTags converts RAWG tags to taxonomies, dropping tags in languages other than English.
+func mapTags(tags []rawg.Tag) []models.Taxonomy {
+	if tags == nil {
+		return nil
+	}
+	taxonomies := make([]models.Taxonomy, 0, len(tags))
+	for _, tag := range tags {
+		if tag.Language == "" || tag.Language == rawg.Eng {
+			taxonomies = append(taxonomies, models.Taxonomy{Slug: tag.Slug, Name: tag.Name})
+		}
+	}
+	return taxonomies
+}
+
+func mapPlatformNames(platforms []rawg.Platform) []string {
+	names := make([]string, len(platforms))
+	for i, p := range platforms {
+		names[i] = p.Platform.Name
+	}
+	return names
+}
+
+func parseReleaseDate(released string) time.Time {
+	if released == "" {
+		return time.Time{}
+	}
+	parsedTime, err := time.Parse(time.DateOnly, released)
+	if err != nil {
+		log.Printf("Warning: Error parsing release date '%s': %v. Falling back to empty date.", released, err)
+		return time.Time{} // Zero value for time
 	}
+	return parsedTime
 }
 
 // MapGamesJSONToModel converts a slice of game results from the external RAWG API to a slice of our internal Game models.
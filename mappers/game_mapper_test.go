@@ -0,0 +1,20 @@
+package mappers
+
+import (
+	"testing"
+
+	"github.com/melkdesousa/gamgo/dao/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMapGamesModelToOutputDTO(t *testing.T) {
+	// Setup
+	games := []models.Game{{ID: "1", Title: "Half-Life 2", Rating: 448}, {ID: "2", Title: "Unrated"}}
+
+	// Call the mapper
+	dtos := MapGamesModelToOutputDTO(games)
+
+	// Assertions
+	assert.Equal(t, 4.48, dtos[0].Rating, "Ratings should keep their decimals")
+	assert.Zero(t, dtos[1].Rating)
+}
@@ -0,0 +1,11 @@
+package mappers
+
+// PlatformOutputDTO is a platform or platform family. Its name can be passed to the
+// platforms filter of the game listing, where a family matches any of its platforms.
+type PlatformOutputDTO struct {
+	Slug       string              `json:"slug"`
+	Name       string              `json:"name"`
+	IsParent   bool                `json:"isParent"`
+	GamesCount int                 `json:"gamesCount"`
+	Children   []PlatformOutputDTO `json:"children,omitempty"` // only set for families
+}
@@ -0,0 +1,20 @@
+package mappers
+
+import "github.com/melkdesousa/gamgo/dao/models"
+
+// MapPlatformsToOutputDTO converts a platform tree into output DTOs.
+func MapPlatformsToOutputDTO(platforms []models.Platform) []PlatformOutputDTO {
+	dtos := make([]PlatformOutputDTO, len(platforms))
+	for i, platform := range platforms {
+		dtos[i] = PlatformOutputDTO{
+			Slug:       platform.Slug,
+			Name:       platform.Name,
+			IsParent:   platform.IsParent,
+			GamesCount: platform.GamesCount,
+		}
+		if platform.IsParent {
+			dtos[i].Children = MapPlatformsToOutputDTO(platform.Children)
+		}
+	}
+	return dtos
+}
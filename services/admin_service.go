@@ -0,0 +1,100 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"fmt"
+	"log"
+
+	"github.com/melkdesousa/gamgo/cache"
+	"github.com/melkdesousa/gamgo/external/rawg"
+)
+
+// ErrCacheKeyNotFound is returned when inspecting a key missing from the cache.
+var ErrCacheKeyNotFound = errors.New("cache key not found")
+
+// ErrForeignCachePattern is returned when inspecting, listing or purging keys with a key or
+// a pattern that could match keys not owned by the cache, such as the RAWG quotas and the
+// locks.
+var ErrForeignCachePattern = errors.New("pattern or key must start with a schema version, such as v*:, or with tag:")
+
+// AdminService encapsulates the operational reports and the cache management of the
+// administration endpoints.
+type AdminService struct {
+	rawgQuota RawgQuotaReporter
+	cache     cache.Inspector
+}
+
+// NewAdminService creates a new AdminService managing the keys of the cache store.
+func NewAdminService(rawgQuota RawgQuotaReporter, cacheStore cache.Inspector) *AdminService {
+	return &AdminService{rawgQuota: rawgQuota, cache: cacheStore}
+}
+
+// RawgQuotaUsage reports the consumption of each RAWG API key in the current month.
+func (s *AdminService) RawgQuotaUsage(ctx context.Context) ([]rawg.KeyUsage, error) {
+	usage, err := s.rawgQuota.QuotaUsage(ctx)
+	if err != nil {
+		log.Printf("Error reading RAWG quota usage: %v", err)
+		return nil, fmt.Errorf("failed to read RAWG quota usage: %w", err)
+	}
+	return usage, nil
+}
+
+// SearchCacheMetrics reports the counters of the search cache since the start of the
+// instance: hits of fresh and stale pages and of searches without results, misses, cache errors and corrupted entries,
+// entries invalidated by changed games, and background refreshes.
+func (s *AdminService) SearchCacheMetrics() map[string]int64 {
+	metrics := map[string]int64{}
+	searchCacheMetrics.Do(func(kv expvar.KeyValue) {
+		if counter, ok := kv.Value.(*expvar.Int); ok {
+			metrics[kv.Key] = counter.Value()
+		}
+	})
+	return metrics
+}
+
+// CacheKeys lists up to limit keys of the cache store matching a glob pattern, which must
+// only match keys owned by the cache.
+func (s *AdminService) CacheKeys(ctx context.Context, pattern string, limit int) ([]string, error) {
+	if !cache.OwnsPattern(pattern) {
+		return nil, ErrForeignCachePattern
+	}
+	keys, err := s.cache.Keys(ctx, pattern, limit)
+	if err != nil {
+		log.Printf("Error listing cache keys matching %s: %v", pattern, err)
+		return nil, fmt.Errorf("failed to list cache keys: %w", err)
+	}
+	return keys, nil
+}
+
+// CacheEntry returns the entry of a key of the cache store, which must be owned by the cache.
+func (s *AdminService) CacheEntry(ctx context.Context, key string) (cache.Entry, error) {
+	if !cache.OwnsPattern(key) {
+		return cache.Entry{}, ErrForeignCachePattern
+	}
+	entry, found, err := s.cache.Inspect(ctx, key)
+	if err != nil {
+		log.Printf("Error inspecting cache key %s: %v", key, err)
+		return cache.Entry{}, fmt.Errorf("failed to inspect cache key: %w", err)
+	}
+	if !found {
+		return cache.Entry{}, ErrCacheKeyNotFound
+	}
+	return entry, nil
+}
+
+// PurgeCache deletes the keys of the cache store matching a glob pattern, which must only
+// match keys owned by the cache, returning how many.
+func (s *AdminService) PurgeCache(ctx context.Context, pattern string) (int, error) {
+	if !cache.OwnsPattern(pattern) {
+		return 0, ErrForeignCachePattern
+	}
+	purged, err := s.cache.Purge(ctx, pattern)
+	if err != nil {
+		log.Printf("Error purging cache keys matching %s: %v", pattern, err)
+		return purged, fmt.Errorf("failed to purge cache keys: %w", err)
+	}
+	log.Printf("Purged %d cache keys matching %s", purged, pattern)
+	return purged, nil
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/melkdesousa/gamgo/cache"
+	"github.com/melkdesousa/gamgo/dao/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdminServiceCache(t *testing.T) {
+	ctx := context.Background()
+	newStore := func() (*cache.MemoryStore, string) {
+		store := cache.NewMemoryStore()
+		games := cache.New[models.Game](store, nil)
+		games.Set(ctx, "game:id:1", models.Game{Title: "Celeste"}, time.Minute)
+		// keys of the same Redis database not owned by the cache
+		store.Set(ctx, "rawg:quota:key:2025-06", []byte("42"), 0)
+		store.Set(ctx, "lock:warmup", []byte("1"), time.Minute)
+		return store, games.Key("game:id:1")
+	}
+
+	t.Run("TestRejectsPatternsMatchingKeysOutsideOfTheCache", func(t *testing.T) {
+		// Setup
+		store, _ := newStore()
+		adminService := NewAdminService(nil, store)
+
+		// Call the service
+		purged, purgeErr := adminService.PurgeCache(ctx, "*")
+		keys, listErr := adminService.CacheKeys(ctx, "rawg:*", 10)
+
+		// Assertions
+		assert.ErrorIs(t, purgeErr, ErrForeignCachePattern)
+		assert.Zero(t, purged)
+		assert.ErrorIs(t, listErr, ErrForeignCachePattern)
+		assert.Empty(t, keys)
+		remaining, _ := store.Keys(ctx, "*", 0)
+		assert.Len(t, remaining, 3, "No key should be purged")
+	})
+
+	t.Run("TestInspectsCacheKeysOnly", func(t *testing.T) {
+		// Setup
+		store, gameKey := newStore()
+		adminService := NewAdminService(nil, store)
+
+		// Call the service
+		quota, quotaErr := adminService.CacheEntry(ctx, "rawg:quota:key:2025-06")
+		_, lockErr := adminService.CacheEntry(ctx, "lock:warmup")
+		game, gameErr := adminService.CacheEntry(ctx, gameKey)
+
+		// Assertions
+		assert.ErrorIs(t, quotaErr, ErrForeignCachePattern)
+		assert.Zero(t, quota)
+		assert.ErrorIs(t, lockErr, ErrForeignCachePattern)
+		assert.NoError(t, gameErr)
+		assert.Equal(t, gameKey, game.Key)
+	})
+
+	t.Run("TestPurgesCacheKeysOnly", func(t *testing.T) {
+		// Setup
+		store, gameKey := newStore()
+		adminService := NewAdminService(nil, store)
+
+		// Call the service
+		purged, err := adminService.PurgeCache(ctx, "v*:*")
+
+		// Assertions
+		assert.NoError(t, err)
+		assert.Equal(t, 1, purged)
+		remaining, _ := store.Keys(ctx, "*", 0)
+		assert.NotContains(t, remaining, gameKey)
+		assert.ElementsMatch(t, []string{"rawg:quota:key:2025-06", "lock:warmup"}, remaining)
+	})
+}
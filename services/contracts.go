@@ -6,20 +6,54 @@ import (
 
 	"github.com/melkdesousa/gamgo/dao/models"
 	"github.com/melkdesousa/gamgo/external/rawg"
-	"github.com/redis/go-redis/v9"
 )
 
 type GameDAO interface {
-	SearchGames(ctx context.Context, title string) ([]models.Game, error)
-	InsertManyGames(ctx context.Context, games []models.Game) error
-	ListGames(ctx context.Context, page int, platforms []string, title string) ([]models.Game, int, error)
+	SearchGames(ctx context.Context, title string, sort models.GameSort, page models.PageRequest) (models.GamePage, error)
+	UpsertManyGames(ctx context.Context, games []models.Game) error
+	BulkImportGames(ctx context.Context, games []models.Game) (models.ImportResult, error)
+	ListGames(ctx context.Context, filter models.GameFilter, sort models.GameSort, page models.PageRequest) (models.GamePage, error)
+	GetSearchIngestion(ctx context.Context, term, source string) (models.SearchIngestion, error)
+	RecordSearchIngestion(ctx context.Context, ingestion models.SearchIngestion) error
+	GetGameByID(ctx context.Context, id string) (models.Game, error)
+	GetGameByExternalID(ctx context.Context, source, externalID string) (models.Game, error)
+	SaveGameDetails(ctx context.Context, game *models.Game) error
+	ListTaxonomies(ctx context.Context, kind models.TaxonomyKind, page models.PageRequest) (models.TaxonomyPage, error)
 }
 
-type RawgAPI interface {
-	SearchGames(ctx context.Context, query string, page int) (*rawg.GameListResponse, error)
+type PlatformDAO interface {
+	UpsertPlatforms(ctx context.Context, platforms []models.Platform) error
+	ListPlatforms(ctx context.Context) ([]models.Platform, error)
 }
 
-type Cache interface {
-	Get(ctx context.Context, key string) *redis.StringCmd
-	Set(ctx context.Context, key string, value any, expiration time.Duration) *redis.StatusCmd
+// RawgPlatformAPI lists the platform catalog of RAWG.
+type RawgPlatformAPI interface {
+	ListPlatforms(ctx context.Context, page int) (*rawg.PlatformListResponse, error)
+	ListParentPlatforms(ctx context.Context, page int) (*rawg.ParentPlatformListResponse, error)
+}
+
+// RawgQuotaReporter reports the consumption of the RAWG API keys.
+type RawgQuotaReporter interface {
+	QuotaUsage(ctx context.Context) ([]rawg.KeyUsage, error)
+}
+
+// CacheHealth reports whether the cache is down, the service then running in degraded mode.
+type CacheHealth interface {
+	Degraded() bool
+}
+
+// Locker holds short-lived locks shared by the instances of the service.
+type Locker interface {
+	// TryLock acquires the lock of key for ttl unless it is held, returning the function
+	// releasing it when acquired.
+	TryLock(ctx context.Context, key string, ttl time.Duration) (unlock func(), locked bool, err error)
+}
+
+// Popularity counts requests by popularity sets, such as the searches, to find the most
+// popular ones.
+type Popularity interface {
+	// Add counts the hits of members of set at once.
+	Add(ctx context.Context, set string, hits map[string]int) error
+	// Top returns up to n members of set, the most popular first.
+	Top(ctx context.Context, set string, n int) ([]string, error)
 }
@@ -1,32 +1,67 @@
 package services
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"os"
+	"slices"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/melkdesousa/gamgo/cache"
+	"github.com/melkdesousa/gamgo/config"
 	"github.com/melkdesousa/gamgo/dao/models"
+	"github.com/melkdesousa/gamgo/dao/search"
 	"github.com/melkdesousa/gamgo/database"
-	"github.com/melkdesousa/gamgo/external/rawg"
-	"github.com/melkdesousa/gamgo/mappers"
-	"github.com/melkdesousa/gamgo/utils"
-	"github.com/redis/go-redis/v9"
+	"github.com/melkdesousa/gamgo/external/providers"
+	"golang.org/x/sync/singleflight"
 )
 
 // GameService encapsulates business logic related to games.
 type GameService struct {
-	gameDAO  GameDAO
-	cache    Cache
-	rawgAPI  RawgAPI
-	cacheTTL time.Duration
+	gameDAO GameDAO
+	// searchPages and games hold the search pages and games by cache key
+	searchPages *cache.Cache[cachedSearchPage]
+	games       *cache.Cache[models.Game]
+	// catalogPages holds the pages of game listings, for catalogTTL
+	catalogPages *cache.Cache[models.GamePage]
+	catalogTTL   time.Duration
+	// popular counts the first pages served, when not nil
+	popular *popularityCounter
+	// emptySearches holds the queries without results, for negativeCacheTTL
+	emptySearches    *cache.Cache[emptySearch]
+	negativeCacheTTL time.Duration
+	// store holds both caches, whose entries are invalidated by the tags of their games
+	store cache.Store
+	// providers are the enabled external catalogs, in order of priority
+	providers *providers.Registry
+	cacheTTL  time.Duration
+	// cacheSoftTTL is how long cached search pages are served without being refreshed
+	cacheSoftTTL time.Duration
+	refresher    *refresher
+	// detailsTTL is how long game details fetched from the external API are considered fresh
+	detailsTTL time.Duration
+	// maxBackfillPages bounds the external API pages imported by a single search
+	maxBackfillPages int
+	// searches coalesces the concurrent searches of a cache key
+	searches singleflight.Group
+	// locker locks the searches of a cache key across instances, when not nil
+	locker         Locker
+	searchLockTTL  time.Duration
+	searchLockWait time.Duration
+	searchLockPoll time.Duration
 }
 
-// NewGameService creates a new GameService.
-func NewGameService(gameDAO GameDAO, cache Cache, rawgAPI RawgAPI) *GameService {
+// NewGameService creates a new GameService caching search pages and games in store. Searches
+// are only coalesced within the instance when locker is nil, and popular requests are only
+// counted when popularity is not nil.
+func NewGameService(gameDAO GameDAO, store cache.Store, locker Locker, popularity Popularity, providers *providers.Registry) *GameService {
 	cacheTTLStr := os.Getenv("CACHE_TTL_HOURS")
 	cacheTTLHours, err := strconv.Atoi(cacheTTLStr)
 	if err != nil || cacheTTLHours <= 0 {
@@ -34,95 +69,706 @@ func NewGameService(gameDAO GameDAO, cache Cache, rawgAPI RawgAPI) *GameService
 		cacheTTLHours = 24 // Default TTL
 	}
 	cacheTTLValue := time.Duration(cacheTTLHours) * time.Hour
-	return &GameService{
-		gameDAO:  gameDAO,
-		cache:    cache,
-		rawgAPI:  rawgAPI,
-		cacheTTL: cacheTTLValue,
+	s := &GameService{
+		gameDAO:      gameDAO,
+		store:        store,
+		searchPages:  cache.New[cachedSearchPage](store, nil).TaggedBy(searchPageTags),
+		games:        cache.New[models.Game](store, nil).TaggedBy(gameTags),
+		catalogPages: cache.New[models.GamePage](store, nil).TaggedBy(gamePageTags),
+		catalogTTL:   min(time.Duration(config.GetEnvOrDefault("CACHE_CATALOG_TTL_MINUTES", 10))*time.Minute, cacheTTLValue),
+		emptySearches: cache.New[emptySearch](store, nil).TaggedBy(func(empty emptySearch) []string {
+			return titleTags(empty.TitlePrefixes...)
+		}),
+		negativeCacheTTL: min(time.Duration(config.GetEnvOrDefault("CACHE_NEGATIVE_TTL_MINUTES", 10))*time.Minute, cacheTTLValue),
+		providers:        providers,
+		cacheTTL:         cacheTTLValue,
+		cacheSoftTTL:     min(time.Duration(config.GetEnvOrDefault("CACHE_SOFT_TTL_HOURS", 6))*time.Hour, cacheTTLValue),
+		refresher: newRefresher(
+			config.GetEnvOrDefault("SEARCH_REFRESH_WORKERS", 2),
+			config.GetEnvOrDefault("SEARCH_REFRESH_QUEUE_SIZE", 100),
+			time.Duration(config.GetEnvOrDefault("SEARCH_REFRESH_TIMEOUT_SECONDS", 30))*time.Second,
+		),
+		detailsTTL:       time.Duration(config.GetEnvOrDefault("GAME_DETAILS_TTL_HOURS", 168)) * time.Hour,
+		maxBackfillPages: config.GetEnvOrDefault("SEARCH_MAX_BACKFILL_PAGES", 3),
+		locker:           locker,
+		searchLockTTL:    time.Duration(config.GetEnvOrDefault("SEARCH_LOCK_TTL_SECONDS", 30)) * time.Second,
+		searchLockWait:   time.Duration(config.GetEnvOrDefault("SEARCH_LOCK_WAIT_MS", 5000)) * time.Millisecond,
+		searchLockPoll:   time.Duration(config.GetEnvOrDefault("SEARCH_LOCK_POLL_MS", 100)) * time.Millisecond,
 	}
+	if popularity != nil {
+		s.popular = newPopularityCounter(
+			popularity,
+			config.GetEnvOrDefault("POPULARITY_MAX_PENDING", 1000),
+			time.Duration(config.GetEnvOrDefault("POPULARITY_FLUSH_SECONDS", 10))*time.Second,
+			5*time.Second,
+		)
+	}
+	return s
 }
 
-// SearchGames searches for games based on title and page.
-// It checks cache, then database, then external API.
-func (s *GameService) SearchGames(ctx context.Context, sanitizedTitle string, page int, pageStr string) ([]models.Game, error) {
-	cacheKey := database.GetCacheKey(database.CACHE_SEARCH_GAME_KEY_PREFIX, sanitizedTitle, pageStr)
-	gamesCached, err := s.cache.Get(ctx, cacheKey).Result()
-	if err != nil && err != redis.Nil {
-		log.Printf("Error fetching from cache for key %s: %v", cacheKey, err)
-		return nil, fmt.Errorf("failed to fetch from cache: %w", err)
+// SearchGames searches for games based on title, returning the requested page ordered by sort.
+// It checks cache, then database. Pages are always read from the database: result pages of
+// the providers are imported for the term on demand, the first one on the first search and
+// the following ones whenever the local store runs out of matches for the requested page.
+// Search fans out to the enabled providers, whose results are merged dropping the games
+// already found by a provider of higher priority. Only stored games are returned while the
+// providers are unavailable, and the pages found while only some of them are unavailable
+// are cached stale, so the next request retries the others in the background. Cache
+// failures are treated as misses, and unreadable cache entries are deleted and recomputed.
+// Queries without any result are cached apart for negativeCacheTTL, until games matching
+// them are imported.
+// Malformed search expressions are reported as *search.SyntaxError.
+func (s *GameService) SearchGames(ctx context.Context, sanitizedTitle string, page models.PageRequest, sort models.GameSort) (models.GamePage, error) {
+	query, err := search.Parse(sanitizedTitle)
+	if err != nil {
+		return models.GamePage{}, fmt.Errorf("failed to parse search query: %w", err)
 	}
-	if gamesCached != "" {
-		var games []models.Game
-		if err := json.Unmarshal([]byte(gamesCached), &games); err != nil {
-			log.Printf("Error unmarshalling cached games for key %s: %v", cacheKey, err)
-			return nil, fmt.Errorf("failed to unmarshal cached games: %w", err)
-		}
-		log.Printf("Cache hit for key %s, returning cached games", cacheKey)
-		return games, nil
+	result, err := s.searchGames(ctx, sanitizedTitle, query, page, sort)
+	// only first pages that were served with games are worth warming up
+	if err == nil && len(result.Games) > 0 && page.Cursor == "" && page.Number == 1 {
+		s.trackPopular(popularSearches, popularRequest{Title: query.String(), Sort: sort.Field, Order: sort.Order(), Limit: page.Limit})
+	}
+	return result, err
+}
+
+// searchGames runs SearchGames without counting the request among the popular ones.
+func (s *GameService) searchGames(ctx context.Context, sanitizedTitle string, query *search.Query, page models.PageRequest, sort models.GameSort) (models.GamePage, error) {
+	cacheKey := database.GetCacheKey(database.CACHE_SEARCH_GAME_KEY_PREFIX, query.String(), sort.String(), pageCacheKey(page))
+	result, state := s.cachedPage(ctx, cacheKey, query)
+	switch state {
+	case cacheFresh:
+		return result, nil
+	case cacheStale:
+		// served as it is while a worker refreshes it
+		s.refresher.schedule(cacheKey, func(ctx context.Context) error {
+			return s.refreshSearch(ctx, cacheKey, sanitizedTitle, query, page, sort)
+		})
+		return result, nil
 	}
 	log.Printf("Cache miss for key %s", cacheKey)
-	gamesInDB, err := s.gameDAO.SearchGames(ctx, sanitizedTitle)
+	searchCacheMetrics.Add("misses", 1)
+	for {
+		// concurrent misses of a key share a single search
+		flight := s.searches.DoChan(cacheKey, func() (any, error) {
+			return s.searchUncached(ctx, cacheKey, sanitizedTitle, query, page, sort)
+		})
+		select {
+		case <-ctx.Done():
+			return models.GamePage{}, ctx.Err()
+		case res := <-flight:
+			if res.Shared && errors.Is(res.Err, context.Canceled) && ctx.Err() == nil {
+				continue // the caller running the search gave up, search again
+			}
+			if res.Err != nil {
+				return models.GamePage{}, res.Err
+			}
+			return res.Val.(models.GamePage), nil
+		}
+	}
+}
+
+// The popularity sets of the first pages of searches and of game listings.
+const (
+	popularSearches     = "searches"
+	popularCatalogPages = "catalog"
+)
+
+// popularRequest is a request for a first page, counted to warm the cache up with the most
+// popular ones. Searches have a title, catalogue pages a filter.
+type popularRequest struct {
+	Title  string             `json:"title,omitempty"`
+	Filter *models.GameFilter `json:"filter,omitempty"`
+	// Sort and Order are the query parameters of the sort, see models.ParseGameSort
+	Sort  models.SortField `json:"sort"`
+	Order string           `json:"order"`
+	Limit int              `json:"limit"`
+}
+
+// trackPopular counts a request in a popularity set, added to the shared counts in the
+// background.
+func (s *GameService) trackPopular(set string, request popularRequest) {
+	if s.popular == nil {
+		return
+	}
+	member, err := json.Marshal(request)
 	if err != nil {
-		log.Printf("Error searching games in database for title '%s': %v", sanitizedTitle, err)
-		return nil, fmt.Errorf("failed to search games in database: %w", err)
+		log.Printf("Error encoding popular request: %v", err)
+		return
+	}
+	s.popular.count(set, string(member))
+}
+
+// cacheState tells whether a search page was found in the cache and whether it is fresh.
+type cacheState int
+
+const (
+	cacheMiss cacheState = iota
+	cacheFresh
+	// cacheStale pages are past their soft expiry but not evicted yet
+	cacheStale
+)
+
+// cachedSearchPage is a search page as cached. Past FreshUntil it is still served, while
+// being refreshed, until the cache entry expires.
+type cachedSearchPage struct {
+	FreshUntil time.Time       `json:"freshUntil"`
+	Page       models.GamePage `json:"page"`
+}
+
+// cachedPage returns the search page cached under cacheKey, if any, or an empty page when
+// the query is cached as having no results. Unreadable entries are reported as misses.
+func (s *GameService) cachedPage(ctx context.Context, cacheKey string, query *search.Query) (models.GamePage, cacheState) {
+	cached, found, err := s.searchPages.Get(ctx, cacheKey)
+	if errors.Is(err, cache.ErrCorrupted) {
+		log.Printf("Deleted corrupted cache key %s: %v", cacheKey, err)
+		searchCacheMetrics.Add("corrupted", 1)
+		return models.GamePage{}, cacheMiss
+	}
+	if err != nil {
+		log.Printf("Error fetching from cache for key %s, treating it as a miss: %v", cacheKey, err)
+		searchCacheMetrics.Add("errors", 1)
+		return models.GamePage{}, cacheMiss
+	}
+	if !found {
+		return s.cachedEmptySearch(ctx, query)
+	}
+	if time.Now().After(cached.FreshUntil) {
+		log.Printf("Stale cache hit for key %s, returning cached games", cacheKey)
+		searchCacheMetrics.Add("hits_stale", 1)
+		return cached.Page, cacheStale
+	}
+	log.Printf("Cache hit for key %s, returning cached games", cacheKey)
+	searchCacheMetrics.Add("hits_fresh", 1)
+	return cached.Page, cacheFresh
+}
+
+// emptySearch is a search cached as having no results, with the title prefixes of the
+// games that would match it, whose import invalidates it.
+type emptySearch struct {
+	Query         string   `json:"query"`
+	TitlePrefixes []string `json:"titlePrefixes"`
+}
+
+// emptySearchKey is the cache key of a query cached as having no results, whatever the
+// requested page and sort.
+func emptySearchKey(query *search.Query) string {
+	return database.GetCacheKey(database.CACHE_SEARCH_EMPTY_KEY_PREFIX, query.String())
+}
+
+// cachedEmptySearch returns an empty fresh page when the query is cached as having no
+// results. Unreadable entries are reported as misses.
+func (s *GameService) cachedEmptySearch(ctx context.Context, query *search.Query) (models.GamePage, cacheState) {
+	if s.negativeCacheTTL <= 0 {
+		return models.GamePage{}, cacheMiss
+	}
+	cacheKey := emptySearchKey(query)
+	_, found, err := s.emptySearches.Get(ctx, cacheKey)
+	if errors.Is(err, cache.ErrCorrupted) {
+		log.Printf("Deleted corrupted cache key %s: %v", cacheKey, err)
+		searchCacheMetrics.Add("corrupted", 1)
+		return models.GamePage{}, cacheMiss
+	}
+	if err != nil {
+		log.Printf("Error fetching from cache for key %s, treating it as a miss: %v", cacheKey, err)
+		searchCacheMetrics.Add("errors", 1)
+		return models.GamePage{}, cacheMiss
+	}
+	if !found {
+		return models.GamePage{}, cacheMiss
 	}
-	if len(gamesInDB) > 0 {
-		log.Printf("Found %d games in DB for title '%s'", len(gamesInDB), sanitizedTitle)
-		gamesJSON, err := utils.SerializerJSON(gamesInDB)
+	log.Printf("Negative cache hit for key %s, returning no games", cacheKey)
+	searchCacheMetrics.Add("hits_negative", 1)
+	return models.GamePage{Games: []models.Game{}}, cacheFresh
+}
+
+// refreshSearch reloads a stale search page into the cache, unless another instance holds
+// the lock of the page, which means it is already refreshing or loading it.
+func (s *GameService) refreshSearch(ctx context.Context, cacheKey, sanitizedTitle string, query *search.Query, page models.PageRequest, sort models.GameSort) error {
+	if s.locker != nil {
+		lockKey := database.GetCacheKey(database.CACHE_LOCK_KEY_PREFIX, cacheKey)
+		unlock, locked, err := s.locker.TryLock(ctx, lockKey, s.searchLockTTL)
 		if err != nil {
-			log.Printf("Error serializing games from DB for caching (key %s): %v", cacheKey, err)
+			log.Printf("Error acquiring lock %s, refreshing without it: %v", lockKey, err)
+		} else if !locked {
+			return fmt.Errorf("%w: lock %s held by another instance", errRefreshSkipped, lockKey)
 		} else {
-			if err := s.cache.Set(ctx, cacheKey, gamesJSON.String(), s.cacheTTL).Err(); err != nil {
-				log.Printf("Error setting cache for DB results (key %s): %v", cacheKey, err)
-			} else {
-				log.Printf("Successfully cached DB results for key %s with TTL %v", cacheKey, s.cacheTTL)
+			defer unlock()
+		}
+	}
+	_, err, _ := s.searches.Do(cacheKey, func() (any, error) {
+		return s.loadSearch(ctx, cacheKey, sanitizedTitle, query, page, sort)
+	})
+	return err
+}
+
+// searchUncached runs a search missing from the cache while holding its lock, so a single
+// instance runs it while the others wait for the cache to be filled. Without a locker, when
+// the lock is unavailable, or when it is still held after searchLockWait, such as by a
+// stuck instance, the search runs without the lock. Locks of crashed instances expire
+// after searchLockTTL.
+func (s *GameService) searchUncached(ctx context.Context, cacheKey, sanitizedTitle string, query *search.Query, page models.PageRequest, sort models.GameSort) (models.GamePage, error) {
+	if s.locker == nil {
+		return s.loadSearch(ctx, cacheKey, sanitizedTitle, query, page, sort)
+	}
+	lockKey := database.GetCacheKey(database.CACHE_LOCK_KEY_PREFIX, cacheKey)
+	deadline := time.Now().Add(s.searchLockWait)
+	for {
+		unlock, locked, err := s.locker.TryLock(ctx, lockKey, s.searchLockTTL)
+		if err != nil {
+			log.Printf("Error acquiring lock %s, searching without it: %v", lockKey, err)
+			break
+		}
+		if locked {
+			defer unlock()
+			// the previous holder may have filled the cache since the miss
+			if result, state := s.cachedPage(ctx, cacheKey, query); state == cacheFresh {
+				return result, nil
 			}
+			break
+		}
+		if !time.Now().Before(deadline) {
+			log.Printf("Lock %s still held after %v, searching without it", lockKey, s.searchLockWait)
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return models.GamePage{}, ctx.Err()
+		case <-time.After(s.searchLockPoll):
+		}
+		if result, state := s.cachedPage(ctx, cacheKey, query); state == cacheFresh {
+			return result, nil
 		}
-		return gamesInDB, nil
 	}
-	log.Printf("No games found in DB for title '%s'. Fetching from RAWG API.", sanitizedTitle)
-	resp, err := s.rawgAPI.SearchGames(ctx, sanitizedTitle, page)
-	if err != nil {
-		log.Printf("Error searching games in external API for title '%s', page %d: %v", sanitizedTitle, page, err)
-		return nil, fmt.Errorf("failed to search games in external API: %w", err)
+	return s.loadSearch(ctx, cacheKey, sanitizedTitle, query, page, sort)
+}
+
+// loadSearch reads a search page from the database, importing the pages of the providers
+// it needs, and caches it.
+func (s *GameService) loadSearch(ctx context.Context, cacheKey, sanitizedTitle string, query *search.Query, page models.PageRequest, sort models.GameSort) (models.GamePage, error) {
+	var err error
+	term := query.Plain()
+	enabled := s.providers.Providers()
+	ingestions := make([]models.SearchIngestion, len(enabled))
+	for i, provider := range enabled {
+		ingestions[i], err = s.gameDAO.GetSearchIngestion(ctx, term, provider.Source())
+		if err != nil {
+			log.Printf("Error reading search ingestion of %s for title '%s': %v", provider.Source(), sanitizedTitle, err)
+			return models.GamePage{}, fmt.Errorf("failed to read search ingestion: %w", err)
+		}
 	}
-	if resp == nil || resp.Count == 0 {
-		log.Printf("No games found in external API for title '%s', page %d", sanitizedTitle, page)
-		return []models.Game{}, nil
+	// games fetched from the providers that could not be stored, served when nothing else is available
+	var unstored []models.Game
+	var ingestErr, failedErr error
+	// partial is set when some providers failed while others answered
+	partial := false
+	fetched := 0
+	if pending := pendingIngestions(ingestions, func(ingestion models.SearchIngestion) bool { return !ingestion.Started() }); len(pending) > 0 {
+		unstored, failedErr, ingestErr = s.ingestNextSearchPages(ctx, enabled, ingestions, pending)
+		partial = partial || failedErr != nil
+		fetched++
 	}
-	gamesFromAPI := make([]rawg.Result, len(resp.Results))
-	for i, result := range resp.Results {
-		gamesFromAPI[i] = result
+	result, err := s.gameDAO.SearchGames(ctx, sanitizedTitle, sort, page)
+	for err == nil && ingestErr == nil && len(result.Games) < page.Limit && fetched < s.maxBackfillPages {
+		pending := pendingIngestions(ingestions, func(ingestion models.SearchIngestion) bool { return ingestion.HasNext })
+		if len(pending) == 0 {
+			break
+		}
+		log.Printf("Found %d of %d games in DB for title '%s', importing the next page of %d providers", len(result.Games), page.Limit, sanitizedTitle, len(pending))
+		unstored, failedErr, ingestErr = s.ingestNextSearchPages(ctx, enabled, ingestions, pending)
+		partial = partial || failedErr != nil
+		fetched++
+		if ingestErr == nil {
+			result, err = s.gameDAO.SearchGames(ctx, sanitizedTitle, sort, page)
+		}
 	}
-	gamesModel := mappers.MapGamesJSONToModel(gamesFromAPI)
-	gamesJSON, err := utils.SerializerJSON(gamesModel)
 	if err != nil {
-		log.Printf("Error serializing games from API for caching (key %s): %v", cacheKey, err)
-	} else {
-		if err := s.cache.Set(ctx, cacheKey, gamesJSON.String(), s.cacheTTL).Err(); err != nil {
-			log.Printf("Error setting cache for API results (key %s): %v", cacheKey, err)
-		} else {
-			log.Printf("Successfully cached API results for key %s with TTL %v", cacheKey, s.cacheTTL)
+		log.Printf("Error searching games in database for title '%s': %v", sanitizedTitle, err)
+		return models.GamePage{}, fmt.Errorf("failed to search games in database: %w", err)
+	}
+	// pages that were not imported yet still count towards the total, by the provider with
+	// the most results as the others may find the same games
+	remoteCount := 0
+	for _, ingestion := range ingestions {
+		if ingestion.HasNext {
+			remoteCount = max(remoteCount, ingestion.RemoteCount)
+		}
+	}
+	result.Total = max(result.Total, remoteCount)
+	if ingestErr != nil {
+		if len(result.Games) > 0 {
+			log.Printf("Returning %d games from DB for title '%s' without caching them", len(result.Games), sanitizedTitle)
+			return result, nil
+		}
+		if len(unstored) > 0 {
+			sortGames(unstored, sort)
+			if len(unstored) > page.Limit {
+				unstored = unstored[:page.Limit]
+			}
+			return models.GamePage{Games: unstored, Total: max(remoteCount, len(unstored))}, nil
+		}
+		if errors.Is(ingestErr, providers.ErrUnavailable) {
+			log.Printf("Game providers unavailable, returning DB results for title '%s' without caching them", sanitizedTitle)
+			return result, nil
+		}
+		return models.GamePage{}, fmt.Errorf("failed to search games in external API: %w", ingestErr)
+	}
+	log.Printf("Found %d of %d games in DB for title '%s'", len(result.Games), result.Total, sanitizedTitle)
+	if partial {
+		// the failed providers may have found games, so nothing is cached as empty, and
+		// pages are cached stale to be refreshed by the next request
+		if len(result.Games) > 0 {
+			s.cacheSearchPage(ctx, cacheKey, result, 0)
+		}
+		return result, nil
+	}
+	if len(result.Games) == 0 && result.Total == 0 {
+		s.cacheEmptySearch(ctx, cacheKey, query)
+		return result, nil
+	}
+	s.cacheSearchPage(ctx, cacheKey, result, s.cacheSoftTTL)
+	return result, nil
+}
+
+// pendingIngestions returns the indexes of the ingestions whose next page should be imported.
+func pendingIngestions(ingestions []models.SearchIngestion, pending func(models.SearchIngestion) bool) []int {
+	var indexes []int
+	for i, ingestion := range ingestions {
+		if pending(ingestion) {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
+// ingestNextSearchPages imports the next result page of a search term from the pending
+// providers concurrently, merging their games by priority, and records the ingestions in
+// place. Providers that failed are left as they were, to be retried by a later search,
+// and their errors are joined in failed while the games of the others are imported: err
+// is only returned when every provider failed, or when the games were fetched but could
+// not be stored, those being returned with it.
+func (s *GameService) ingestNextSearchPages(ctx context.Context, enabled []providers.Provider, ingestions []models.SearchIngestion, pending []int) (unstored []models.Game, failed error, err error) {
+	results := make([]providers.SearchResult, len(pending))
+	errs := make([]error, len(pending))
+	var wg sync.WaitGroup
+	for j, i := range pending {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			nextPage := ingestions[i].LastPage + 1
+			results[j], errs[j] = enabled[i].SearchGames(ctx, ingestions[i].Term, nextPage)
+			if errs[j] != nil {
+				log.Printf("Error searching games in %s for title '%s', page %d: %v", enabled[i].Source(), ingestions[i].Term, nextPage, errs[j])
+			}
+		}()
+	}
+	wg.Wait()
+
+	found := make([][]models.Game, 0, len(pending))
+	for j := range pending {
+		if errs[j] == nil {
+			found = append(found, results[j].Games)
+		}
+	}
+	failed = errors.Join(errs...)
+	if len(found) == 0 {
+		return nil, failed, failed
+	}
+	if len(found) < len(pending) {
+		log.Printf("Importing the games of the %d of %d providers that answered for title '%s'", len(found), len(pending), ingestions[pending[0]].Term)
+	}
+	gamesModel := providers.MergeGames(found...)
+	if len(gamesModel) > 0 {
+		if err := s.gameDAO.UpsertManyGames(ctx, gamesModel); err != nil {
+			log.Printf("Error storing games from providers into database (title '%s'): %v", ingestions[pending[0]].Term, err)
+			return gamesModel, failed, err
 		}
+		log.Printf("Successfully stored %d games from providers into database", len(gamesModel))
+		s.invalidateGames(ctx, gamesModel...)
 	}
-	if err := s.gameDAO.InsertManyGames(ctx, gamesModel); err != nil {
-		log.Printf("Error inserting games from API into database (title '%s'): %v", sanitizedTitle, err)
-	} else {
-		log.Printf("Successfully inserted %d games from API into database for title '%s'", len(gamesModel), sanitizedTitle)
+	for j, i := range pending {
+		if errs[j] != nil {
+			continue
+		}
+		next := models.SearchIngestion{
+			Term:           ingestions[i].Term,
+			ExternalSource: ingestions[i].ExternalSource,
+			LastPage:       ingestions[i].LastPage + 1,
+			RemoteCount:    results[j].Total,
+			HasNext:        results[j].HasNext,
+		}
+		if err := s.gameDAO.RecordSearchIngestion(ctx, next); err != nil {
+			log.Printf("Error recording search ingestion of %s for title '%s', page %d: %v", next.ExternalSource, next.Term, next.LastPage, err)
+		}
+		ingestions[i] = next
+	}
+	return nil, failed, nil
+}
+
+// cacheSearchPage stores a search page served without being refreshed for freshFor,
+// logging instead of failing on errors.
+func (s *GameService) cacheSearchPage(ctx context.Context, cacheKey string, result models.GamePage, freshFor time.Duration) {
+	cached := cachedSearchPage{FreshUntil: time.Now().Add(freshFor), Page: result}
+	if err := s.searchPages.Set(ctx, cacheKey, cached, s.cacheTTL); err != nil {
+		log.Printf("Error setting cache for DB results (key %s): %v", cacheKey, err)
+		return
 	}
-	return gamesModel, nil
+	log.Printf("Successfully cached DB results for key %s with TTL %v", cacheKey, s.cacheTTL)
 }
 
-// ListGames retrieves a list of games from the database, with optional filters.
-func (s *GameService) ListGames(ctx context.Context, page int, platforms []string, title string) (games []models.Game, total int, err error) {
-	games, total, err = s.gameDAO.ListGames(ctx, page, platforms, title)
+// ImportGames stores a large batch of external games at once, such as a catalog sync, then
+// invalidates the cache entries of the imported games like searches do.
+func (s *GameService) ImportGames(ctx context.Context, games []models.Game) (models.ImportResult, error) {
+	result, err := s.gameDAO.BulkImportGames(ctx, games)
+	if err != nil {
+		return result, err
+	}
+	// in batches, bounding the tags invalidated at once
+	for batch := range slices.Chunk(games, 500) {
+		s.invalidateGames(ctx, batch...)
+	}
+	return result, nil
+}
+
+// invalidateGames evicts the cached games and search pages containing games, which were
+// changed in the database, along with the cached empty searches their titles may match,
+// logging instead of failing on errors.
+func (s *GameService) invalidateGames(ctx context.Context, games ...models.Game) {
+	var tags []string
+	for _, game := range games {
+		tags = append(tags, gameTags(game)...)
+		tags = append(tags, titleTags(search.WordPrefixes(game.Title)...)...)
+	}
+	slices.Sort(tags)
+	invalidated, err := s.store.InvalidateTags(ctx, slices.Compact(tags)...)
+	if err != nil {
+		searchCacheMetrics.Add("errors", 1)
+		log.Printf("Error invalidating cache entries of %d changed games: %v", len(games), err)
+		return
+	}
+	if invalidated > 0 {
+		searchCacheMetrics.Add("invalidated", int64(invalidated))
+		log.Printf("Invalidated %d cache entries of %d changed games", invalidated, len(games))
+	}
+}
+
+// gameTags returns the cache tag of a game, by external id since the games fetched from
+// providers are cached before their internal id is known.
+func gameTags(game models.Game) []string {
+	if game.ExternalID != "" {
+		return []string{database.GetCacheKey("game", game.ExternalSource, game.ExternalID)}
+	}
+	if game.ID != "" {
+		return []string{database.GetCacheKey("game", "id", game.ID)}
+	}
+	return nil
+}
+
+// titleTags returns the cache tags of title word prefixes, relating the empty searches to
+// the titles that would match them.
+func titleTags(prefixes ...string) []string {
+	tags := make([]string, len(prefixes))
+	for i, prefix := range prefixes {
+		tags[i] = database.GetCacheKey("title", prefix)
+	}
+	return tags
+}
+
+// searchPageTags returns the cache tags of the games of a search page.
+func searchPageTags(cached cachedSearchPage) []string {
+	return gamePageTags(cached.Page)
+}
+
+// gamePageTags returns the cache tags of the games of a page.
+func gamePageTags(page models.GamePage) []string {
+	var tags []string
+	for _, game := range page.Games {
+		tags = append(tags, gameTags(game)...)
+	}
+	return tags
+}
+
+// cacheEmptySearch caches a query as having no results for negativeCacheTTL, replacing
+// the search page previously cached under cacheKey, and logging instead of failing on
+// errors. Queries that could not be invalidated by the import of matching games are not
+// cached.
+func (s *GameService) cacheEmptySearch(ctx context.Context, cacheKey string, query *search.Query) {
+	if err := s.searchPages.Delete(ctx, cacheKey); err != nil {
+		log.Printf("Error deleting cached search page (key %s): %v", cacheKey, err)
+	}
+	if s.negativeCacheTTL <= 0 {
+		return
+	}
+	prefixes, ok := query.TitlePrefixes()
+	if !ok {
+		log.Printf("Not caching the empty results of '%s', matching titles by any word", query)
+		return
+	}
+	emptyKey := emptySearchKey(query)
+	if err := s.emptySearches.Set(ctx, emptyKey, emptySearch{Query: query.String(), TitlePrefixes: prefixes}, s.negativeCacheTTL); err != nil {
+		log.Printf("Error setting cache for empty results (key %s): %v", emptyKey, err)
+		return
+	}
+	log.Printf("Successfully cached empty results for key %s with TTL %v", emptyKey, s.negativeCacheTTL)
+}
+
+// pageCacheKey identifies a page request within a cache key.
+func pageCacheKey(page models.PageRequest) string {
+	if page.Cursor != "" {
+		return database.GetCacheKey(strconv.Itoa(page.Limit), "cursor", page.Cursor)
+	}
+	return database.GetCacheKey(strconv.Itoa(page.Limit), strconv.Itoa(page.Number))
+}
+
+// ListGames retrieves a page of games from the database matching every condition of the
+// filter. Pages are cached for catalogTTL, until a game they contain changes.
+func (s *GameService) ListGames(ctx context.Context, filter models.GameFilter, sort models.GameSort, page models.PageRequest) (models.GamePage, error) {
+	result, err := s.listGames(ctx, filter, sort, page)
+	if err == nil && len(result.Games) > 0 && page.Cursor == "" && page.Number == 1 {
+		s.trackPopular(popularCatalogPages, popularRequest{Filter: &filter, Sort: sort.Field, Order: sort.Order(), Limit: page.Limit})
+	}
+	return result, err
+}
+
+// listGames runs ListGames without counting the request among the popular ones.
+func (s *GameService) listGames(ctx context.Context, filter models.GameFilter, sort models.GameSort, page models.PageRequest) (models.GamePage, error) {
+	if s.catalogTTL <= 0 {
+		return s.loadCatalogPage(ctx, filter, sort, page)
+	}
+	// filters are keyed by their JSON encoding, stable for equal filters
+	filterKey, err := json.Marshal(filter)
+	if err != nil {
+		return models.GamePage{}, fmt.Errorf("failed to encode game filter: %w", err)
+	}
+	cacheKey := database.GetCacheKey(database.CACHE_CATALOG_KEY_PREFIX, string(filterKey), sort.String(), pageCacheKey(page))
+	return s.catalogPages.GetOrLoad(ctx, cacheKey, func(ctx context.Context) (models.GamePage, time.Duration, error) {
+		result, err := s.loadCatalogPage(ctx, filter, sort, page)
+		return result, s.catalogTTL, err
+	})
+}
+
+// loadCatalogPage reads a page of games matching filter from the database.
+func (s *GameService) loadCatalogPage(ctx context.Context, filter models.GameFilter, sort models.GameSort, page models.PageRequest) (models.GamePage, error) {
+	result, err := s.gameDAO.ListGames(ctx, filter, sort, page)
 	if err != nil {
 		log.Printf("Error listing games from database: %v", err)
-		return nil, 0, fmt.Errorf("failed to list games: %w", err)
+		return models.GamePage{}, fmt.Errorf("failed to list games: %w", err)
 	}
-	if len(games) == 0 {
+	if len(result.Games) == 0 {
 		log.Println("No games found in database.")
-		return nil, 0, nil
 	}
-	return games, total, nil
+	return result, nil
+}
+
+// ListTaxonomies retrieves a page of the genres, tags, developers or publishers with their number of games.
+func (s *GameService) ListTaxonomies(ctx context.Context, kind models.TaxonomyKind, page models.PageRequest) (models.TaxonomyPage, error) {
+	result, err := s.gameDAO.ListTaxonomies(ctx, kind, page)
+	if err != nil {
+		log.Printf("Error listing %s from database: %v", kind, err)
+		return models.TaxonomyPage{}, fmt.Errorf("failed to list %s: %w", kind, err)
+	}
+	return result, nil
+}
+
+// GetGame returns a game by its internal id along with its details, fetching them from
+// the external API when they were never fetched or are stale. It checks cache, then database.
+func (s *GameService) GetGame(ctx context.Context, id string) (models.Game, error) {
+	cacheKey := database.GetCacheKey(database.CACHE_GAME_KEY_PREFIX, "id", id)
+	return s.getGame(ctx, cacheKey, "", "", func() (models.Game, error) {
+		return s.gameDAO.GetGameByID(ctx, id)
+	})
+}
+
+// GetGameByExternalID returns a game by the id of its external source along with its details.
+// Games of the RAWG source that were never imported are fetched from the external API.
+func (s *GameService) GetGameByExternalID(ctx context.Context, source, externalID string) (models.Game, error) {
+	cacheKey := database.GetCacheKey(database.CACHE_GAME_KEY_PREFIX, "external", source, externalID)
+	return s.getGame(ctx, cacheKey, source, externalID, func() (models.Game, error) {
+		return s.gameDAO.GetGameByExternalID(ctx, source, externalID)
+	})
+}
+
+// getGame runs the cache, database and external API flow of a single game. source and
+// externalID identify the game in the external API when it is not stored yet.
+func (s *GameService) getGame(ctx context.Context, cacheKey, source, externalID string, load func() (models.Game, error)) (models.Game, error) {
+	return s.games.GetOrLoad(ctx, cacheKey, func(ctx context.Context) (models.Game, time.Duration, error) {
+		return s.loadGame(ctx, cacheKey, source, externalID, load)
+	})
+}
+
+// loadGame runs the database and external API flow of a game missing from the cache,
+// returning how long to cache it.
+func (s *GameService) loadGame(ctx context.Context, cacheKey, source, externalID string, load func() (models.Game, error)) (models.Game, time.Duration, error) {
+	game, err := load()
+	if err != nil && !errors.Is(err, models.ErrGameNotFound) {
+		log.Printf("Error fetching game from database (key %s): %v", cacheKey, err)
+		return models.Game{}, 0, fmt.Errorf("failed to fetch game from database: %w", err)
+	}
+	stored := err == nil
+	if stored {
+		source, externalID = game.ExternalSource, game.ExternalID
+	}
+	provider, ok := s.providers.Get(source)
+	if !ok || externalID == "" {
+		// details can only be fetched for games of an enabled provider
+		if !stored {
+			return models.Game{}, 0, models.ErrGameNotFound
+		}
+		return game, s.cacheTTL, nil
+	}
+	now := time.Now().UTC()
+	if stored && !game.DetailsStale(s.detailsTTL, now) {
+		return game, s.cacheTTL, nil
+	}
+
+	log.Printf("Fetching details of game %s from %s", externalID, source)
+	fetched, err := provider.GetGame(ctx, externalID)
+	if err != nil {
+		if stored {
+			log.Printf("Error refreshing details of game %s, returning stored game without caching it: %v", externalID, err)
+			return game, 0, nil
+		}
+		if errors.Is(err, models.ErrGameNotFound) {
+			return models.Game{}, 0, models.ErrGameNotFound
+		}
+		log.Printf("Error fetching details of game %s from %s: %v", externalID, source, err)
+		return models.Game{}, 0, fmt.Errorf("failed to fetch game from external API: %w", err)
+	}
+	fetched.DetailsFetchedAt = &now
+	err = s.gameDAO.SaveGameDetails(ctx, &fetched)
+	if errors.Is(err, models.ErrDuplicateGame) && !stored {
+		log.Printf("Game %s of %s is already stored from another source, returning it without storing or caching it", externalID, source)
+		return fetched, 0, nil
+	}
+	if err != nil {
+		log.Printf("Error saving details of game %s to database: %v", externalID, err)
+		if !stored {
+			return models.Game{}, 0, fmt.Errorf("failed to save game to database: %w", err)
+		}
+		fetched.ID = game.ID
+		return fetched, 0, nil
+	}
+	s.invalidateGames(ctx, fetched)
+	return fetched, s.cacheTTL, nil
+}
+
+// sortGames orders games that did not come from the database, such as external API
+// results. Relevance keeps the order given by the API, which is already ranked.
+func sortGames(games []models.Game, sort models.GameSort) {
+	var compare func(a, b models.Game) int
+	switch sort.Field {
+	case models.SortRating:
+		compare = func(a, b models.Game) int { return cmp.Compare(a.Rating, b.Rating) }
+	case models.SortReleaseDate:
+		compare = func(a, b models.Game) int { return a.ReleaseDate.Compare(b.ReleaseDate) }
+	case models.SortTitle:
+		compare = func(a, b models.Game) int { return strings.Compare(a.Title, b.Title) }
+	default:
+		return
+	}
+	slices.SortStableFunc(games, func(a, b models.Game) int {
+		if sort.Desc {
+			return compare(b, a)
+		}
+		return compare(a, b)
+	})
 }
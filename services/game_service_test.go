@@ -2,17 +2,22 @@ package services
 
 import (
 	"context"
+	"errors"
 	"os"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/joho/godotenv"
+	"github.com/melkdesousa/gamgo/cache"
 	"github.com/melkdesousa/gamgo/dao"
 	"github.com/melkdesousa/gamgo/dao/models"
 	"github.com/melkdesousa/gamgo/database"
+	"github.com/melkdesousa/gamgo/external/providers"
 	"github.com/melkdesousa/gamgo/external/rawg"
-	"github.com/redis/go-redis/v9"
+	"github.com/melkdesousa/gamgo/external/rawg/rawgtest"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -22,22 +27,57 @@ type MockGameDAO struct {
 	mock.Mock
 }
 
-func (m *MockGameDAO) SearchGames(ctx context.Context, title string) ([]models.Game, error) {
-	args := m.Called(ctx, title)
-	return args.Get(0).([]models.Game), args.Error(1)
+func (m *MockGameDAO) SearchGames(ctx context.Context, title string, sort models.GameSort, page models.PageRequest) (models.GamePage, error) {
+	args := m.Called(ctx, title, sort, page)
+	return args.Get(0).(models.GamePage), args.Error(1)
 }
 
-func (m *MockGameDAO) InsertManyGames(ctx context.Context, games []models.Game) error {
+func (m *MockGameDAO) UpsertManyGames(ctx context.Context, games []models.Game) error {
 	args := m.Called(ctx, games)
 	return args.Error(0)
 }
 
-func (m *MockGameDAO) ListGames(ctx context.Context, page int, platforms []string, title string) ([]models.Game, int, error) {
-	args := m.Called(ctx, page, platforms, title)
-	return args.Get(0).([]models.Game), args.Int(1), args.Error(2)
+func (m *MockGameDAO) BulkImportGames(ctx context.Context, games []models.Game) (models.ImportResult, error) {
+	args := m.Called(ctx, games)
+	return args.Get(0).(models.ImportResult), args.Error(1)
+}
+
+func (m *MockGameDAO) ListGames(ctx context.Context, filter models.GameFilter, sort models.GameSort, page models.PageRequest) (models.GamePage, error) {
+	args := m.Called(ctx, filter, sort, page)
+	return args.Get(0).(models.GamePage), args.Error(1)
+}
+
+func (m *MockGameDAO) GetSearchIngestion(ctx context.Context, term, source string) (models.SearchIngestion, error) {
+	args := m.Called(ctx, term, source)
+	return args.Get(0).(models.SearchIngestion), args.Error(1)
+}
+
+func (m *MockGameDAO) RecordSearchIngestion(ctx context.Context, ingestion models.SearchIngestion) error {
+	args := m.Called(ctx, ingestion)
+	return args.Error(0)
+}
+
+func (m *MockGameDAO) GetGameByID(ctx context.Context, id string) (models.Game, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(models.Game), args.Error(1)
+}
+
+func (m *MockGameDAO) GetGameByExternalID(ctx context.Context, source, externalID string) (models.Game, error) {
+	args := m.Called(ctx, source, externalID)
+	return args.Get(0).(models.Game), args.Error(1)
+}
+
+func (m *MockGameDAO) SaveGameDetails(ctx context.Context, game *models.Game) error {
+	args := m.Called(ctx, game)
+	return args.Error(0)
+}
+
+func (m *MockGameDAO) ListTaxonomies(ctx context.Context, kind models.TaxonomyKind, page models.PageRequest) (models.TaxonomyPage, error) {
+	args := m.Called(ctx, kind, page)
+	return args.Get(0).(models.TaxonomyPage), args.Error(1)
 }
 
-// MockRawgAPI is a mock implementation of the RawgAPI
+// MockRawgAPI is a mock implementation of the RAWG client
 type MockRawgAPI struct {
 	mock.Mock
 }
@@ -50,28 +90,93 @@ func (m *MockRawgAPI) SearchGames(ctx context.Context, query string, page int) (
 	return args.Get(0).(*rawg.GameListResponse), args.Error(1)
 }
 
-// MockRedisClient is a mock implementation of the Redis client
-type MockRedisClient struct {
+func (m *MockRawgAPI) GetGame(ctx context.Context, id string) (*rawg.GameDetailResponse, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*rawg.GameDetailResponse), args.Error(1)
+}
+
+// MockProvider is a mock implementation of a game provider
+type MockProvider struct {
 	mock.Mock
+	source string
+}
+
+func (m *MockProvider) Source() string {
+	return m.source
 }
 
-func (m *MockRedisClient) Get(ctx context.Context, key string) *redis.StringCmd {
-	args := m.Called(ctx, key)
-	return args.Get(0).(*redis.StringCmd)
+func (m *MockProvider) SearchGames(ctx context.Context, term string, page int) (providers.SearchResult, error) {
+	args := m.Called(ctx, term, page)
+	return args.Get(0).(providers.SearchResult), args.Error(1)
 }
 
-func (m *MockRedisClient) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) *redis.StatusCmd {
-	args := m.Called(ctx, key, value, expiration)
-	return args.Get(0).(*redis.StatusCmd)
+func (m *MockProvider) GetGame(ctx context.Context, externalID string) (models.Game, error) {
+	args := m.Called(ctx, externalID)
+	return args.Get(0).(models.Game), args.Error(1)
 }
 
-type MockStatusCmd struct {
+// MockLocker is a mock implementation of the Locker
+type MockLocker struct {
 	mock.Mock
 }
 
-func (m *MockStatusCmd) Err() error {
-	args := m.Called()
-	return args.Error(0)
+func (m *MockLocker) TryLock(ctx context.Context, key string, ttl time.Duration) (func(), bool, error) {
+	args := m.Called(ctx, key, ttl)
+	unlock, _ := args.Get(0).(func())
+	return unlock, args.Bool(1), args.Error(2)
+}
+
+// testStore is an in-memory cache store counting the values set by cache key. Every call
+// fails with err when it is set.
+type testStore struct {
+	*cache.MemoryStore
+	err   error
+	onSet func(key string)
+	mu    sync.Mutex
+	sets  map[string]int
+}
+
+func newTestStore() *testStore {
+	return &testStore{MemoryStore: cache.NewMemoryStore(), sets: map[string]int{}}
+}
+
+func (s *testStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	if s.err != nil {
+		return nil, false, s.err
+	}
+	return s.MemoryStore.Get(ctx, key)
+}
+
+func (s *testStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if s.err != nil {
+		return s.err
+	}
+	// counted by cache key, without the schema version
+	_, cacheKey, _ := strings.Cut(key, ":")
+	s.mu.Lock()
+	s.sets[cacheKey]++
+	s.mu.Unlock()
+	if s.onSet != nil {
+		s.onSet(cacheKey)
+	}
+	return s.MemoryStore.Set(ctx, key, value, ttl)
+}
+
+func (s *testStore) Delete(ctx context.Context, keys ...string) error {
+	if s.err != nil {
+		return s.err
+	}
+	return s.MemoryStore.Delete(ctx, keys...)
+}
+
+// setCalls returns the number of values set for cacheKey.
+func (s *testStore) setCalls(cacheKey string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sets[cacheKey]
 }
 
 func TestGameService(t *testing.T) {
@@ -83,31 +188,63 @@ func TestGameService(t *testing.T) {
 	}
 	gameDAO := dao.NewGameDAO(database.GetDBConnection())
 	redisClient := database.GetCacheConnection()
-	rawgAPI := rawg.NewRawgAPI()
-	gameService := NewGameService(gameDAO, redisClient, rawgAPI)
+	// RAWG is faked by the stub, only the database and cache are needed
+	stub := rawgtest.NewServer(t, rawgtest.Fixtures(), rawgtest.Faults{})
+	rawgConfig := rawg.ConfigFromEnv()
+	rawgConfig.BaseURL = stub.URL
+	rawgAPI := rawg.NewRawgAPIWithConfig(rawgConfig, nil, rawg.NewRedisUsageStore(redisClient))
+	gameService := NewGameService(gameDAO, cache.NewRedisStore(redisClient), database.NewRedisLocker(redisClient), nil, providers.NewRegistry(providers.NewRawgProvider(rawgAPI)))
 	ctx := context.Background()
+	relevance := models.GameSort{Field: models.SortRelevance, Desc: true}
+	byTitle := models.GameSort{Field: models.SortTitle}
+	firstPage := models.NewPageRequest(1, models.DefaultPageSize, "")
 	t.Run("TestSearchGames", func(t *testing.T) {
-		games, err := gameService.SearchGames(ctx, "zelda", 1, "1")
+		result, err := gameService.SearchGames(ctx, "zelda", firstPage, relevance)
 		assert.NoError(t, err, "Expected no error when searching for games")
-		assert.NotEmpty(t, games, "Expected to find games with title 'zelda'")
+		assert.NotEmpty(t, result.Games, "Expected to find games with title 'zelda'")
 
 		// Verify some properties of the returned games
-		for _, game := range games {
+		for _, game := range result.Games {
 			assert.NotEmpty(t, game.Title, "Game title should not be empty")
 			assert.NotZero(t, game.ID, "Game ID should not be zero")
 		}
 	})
 
+	t.Run("TestUpsertManyGamesDeduplicates", func(t *testing.T) {
+		game := models.Game{
+			Title:          "Gamgo Upsert Test",
+			Platforms:      []string{"PC"},
+			ReleaseDate:    time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+			Rating:         400,
+			ExternalID:     "upsert-test",
+			ExternalSource: rawg.SourceName,
+			ExternalSlug:   "gamgo-upsert-test",
+		}
+		first := []models.Game{game}
+		assert.NoError(t, gameDAO.UpsertManyGames(ctx, first), "Expected no error inserting a game")
+		game.Rating = 450
+		second := []models.Game{game}
+		assert.NoError(t, gameDAO.UpsertManyGames(ctx, second), "Expected no error upserting the same game")
+		assert.Equal(t, first[0].ID, second[0].ID, "Upserting the same external game should keep a single row")
+
+		result, err := gameService.ListGames(ctx, models.GameFilter{Title: "\"Gamgo Upsert Test\"", ExternalSource: rawg.SourceName}, byTitle, firstPage)
+		assert.NoError(t, err, "Expected no error listing the upserted game")
+		assert.Equal(t, 1, result.Total, "Expected a single stored copy of the game")
+		if assert.NotEmpty(t, result.Games) {
+			assert.Equal(t, 450, result.Games[0].Rating, "Upsert should refresh changed fields")
+		}
+	})
+
 	t.Run("TestListGames", func(t *testing.T) {
-		games, total, err := gameService.ListGames(ctx, 1, []string{}, "")
+		result, err := gameService.ListGames(ctx, models.GameFilter{}, byTitle, firstPage)
 		assert.NoError(t, err, "Expected no error when listing games")
-		assert.GreaterOrEqual(t, total, 0, "Total should be non-negative")
+		assert.GreaterOrEqual(t, result.Total, 0, "Total should be non-negative")
 
-		if total > 0 {
-			assert.NotEmpty(t, games, "Expected to find games when total > 0")
+		if result.Total > 0 {
+			assert.NotEmpty(t, result.Games, "Expected to find games when total > 0")
 
 			// Verify some properties of the returned games
-			for _, game := range games {
+			for _, game := range result.Games {
 				assert.NotEmpty(t, game.Title, "Game title should not be empty")
 				assert.NotZero(t, game.ID, "Game ID should not be zero")
 			}
@@ -115,55 +252,68 @@ func TestGameService(t *testing.T) {
 	})
 
 	t.Run("TestListGamesWithPlatformFilter", func(t *testing.T) {
-		platforms := []string{"PC", "PlayStation"}
-		games, total, err := gameService.ListGames(ctx, 1, platforms, "")
+		filter := models.GameFilter{Platforms: []string{"PC", "PlayStation"}, PlatformMatch: models.PlatformMatchAny}
+		result, err := gameService.ListGames(ctx, filter, byTitle, firstPage)
 		assert.NoError(t, err, "Expected no error when listing games with platform filter")
 
-		if total > 0 {
-			assert.NotEmpty(t, games, "Expected to find games when total > 0")
+		if result.Total > 0 {
+			assert.NotEmpty(t, result.Games, "Expected to find games when total > 0")
 		}
 	})
 
 	t.Run("TestListGamesWithTitleFilter", func(t *testing.T) {
 		title := "mario"
-		games, total, err := gameService.ListGames(ctx, 1, []string{}, title)
+		result, err := gameService.ListGames(ctx, models.GameFilter{Title: title}, relevance, firstPage)
 		assert.NoError(t, err, "Expected no error when listing games with title filter")
 
-		if total > 0 {
-			assert.NotEmpty(t, games, "Expected to find games when total > 0")
-			for _, game := range games {
+		if result.Total > 0 {
+			assert.NotEmpty(t, result.Games, "Expected to find games when total > 0")
+			for _, game := range result.Games {
 				assert.Contains(t, game.Title, title, "Game title should contain search term")
 			}
 		}
 	})
+
+	t.Run("TestListGamesWithPlatformAndTitleFilter", func(t *testing.T) {
+		filter := models.GameFilter{Title: "witcher", Platforms: []string{"PC"}, PlatformMatch: models.PlatformMatchAll}
+		result, err := gameService.ListGames(ctx, filter, relevance, firstPage)
+		assert.NoError(t, err, "Expected no error when listing games with platform and title filters")
+		for _, game := range result.Games {
+			assert.Contains(t, strings.ToLower(game.Title), "witcher", "Game title should match the search term")
+			assert.Contains(t, game.Platforms, "PC", "Game should run on every requested platform")
+		}
+	})
 }
 
 func TestGameServiceUnit(t *testing.T) {
 	// Create mocks
 	mockGameDAO := &MockGameDAO{}
-	mockRedisClient := &MockRedisClient{}
+	store := newTestStore()
 	mockRawgAPI := &MockRawgAPI{}
 
 	// Create game service with mocks
-	gameService := NewGameService(mockGameDAO, mockRedisClient, mockRawgAPI)
+	gameService := NewGameService(mockGameDAO, store, nil, nil, providers.NewRegistry(providers.NewRawgProvider(mockRawgAPI)))
 
 	ctx := context.Background()
+	sort := models.GameSort{Field: models.SortRelevance, Desc: true}
+	page := models.NewPageRequest(1, models.DefaultPageSize, "")
 
 	t.Run("TestSearchGamesCacheHit", func(t *testing.T) {
 		// Setup
-		cacheKey := database.GetCacheKey(database.CACHE_SEARCH_GAME_KEY_PREFIX, "mario", "1")
-		mockRedisClient.On("Get", ctx, cacheKey).Return(&redis.StringCmd{})
+		cacheKey := database.GetCacheKey(database.CACHE_SEARCH_GAME_KEY_PREFIX, "mario", sort.String(), "10", "1")
+		cachedPage := models.GamePage{Games: []models.Game{{ID: uuid.NewString(), Title: "Super Mario Bros"}}, Total: 1}
+		gameService.searchPages.Set(ctx, cacheKey, cachedSearchPage{FreshUntil: time.Now().Add(time.Hour), Page: cachedPage}, time.Hour)
 
 		// Call the service
-		games, err := gameService.SearchGames(ctx, "mario", 1, "1")
+		result, err := gameService.SearchGames(ctx, "mario", page, sort)
 
 		// Assertions
 		assert.NoError(t, err)
-		assert.Len(t, games, 1)
-		assert.Equal(t, "Super Mario Bros", games[0].Title)
+		assert.Len(t, result.Games, 1)
+		assert.Equal(t, 1, result.Total)
+		assert.Equal(t, "Super Mario Bros", result.Games[0].Title)
 
 		// Verify mocks
-		mockRedisClient.AssertExpectations(t)
 		// The DAO and API should not be called when cache hits
 		mockGameDAO.AssertNotCalled(t, "SearchGames")
 		mockRawgAPI.AssertNotCalled(t, "SearchGames")
@@ -171,41 +321,41 @@ func TestGameServiceUnit(t *testing.T) {
 
 	t.Run("TestSearchGamesDBHit", func(t *testing.T) {
 		// Setup
-		cacheKey := database.GetCacheKey(database.CACHE_SEARCH_GAME_KEY_PREFIX, "zelda", "1")
+		cacheKey := database.GetCacheKey(database.CACHE_SEARCH_GAME_KEY_PREFIX, "zelda", sort.String(), "10", "1")
 
-		mockRedisClient.On("Get", ctx, cacheKey).Return(&redis.StringCmd{})
+		// Every page of the term was already imported
+		ingestion := models.SearchIngestion{Term: "zelda", ExternalSource: rawg.SourceName, LastPage: 1, RemoteCount: 1}
+		mockGameDAO.On("GetSearchIngestion", ctx, "zelda", rawg.SourceName).Return(ingestion, nil)
 
 		// DB hit
-		dbGames := []models.Game{{ID: uuid.NewString(), Title: "Legend of Zelda"}}
-		mockGameDAO.On("SearchGames", ctx, "zelda").Return(dbGames, nil)
+		dbPage := models.GamePage{Games: []models.Game{{ID: uuid.NewString(), Title: "Legend of Zelda"}}, Total: 1}
+		mockGameDAO.On("SearchGames", ctx, "zelda", sort, page).Return(dbPage, nil)
 
 		// Mock caching DB results
-		mockStatusCmd := new(MockStatusCmd)
-		mockStatusCmd.On("Err").Return(nil)
-		mockRedisClient.On("Set", ctx, cacheKey, mock.Anything, gameService.cacheTTL).Return(mockStatusCmd)
 
 		// Call the service
-		games, err := gameService.SearchGames(ctx, "zelda", 1, "1")
+		result, err := gameService.SearchGames(ctx, "zelda", page, sort)
 
 		// Assertions
 		assert.NoError(t, err)
-		assert.Len(t, games, 1)
-		assert.Equal(t, "Legend of Zelda", games[0].Title)
+		assert.Len(t, result.Games, 1)
+		assert.Equal(t, "Legend of Zelda", result.Games[0].Title)
 
 		// Verify mocks
-		mockRedisClient.AssertExpectations(t)
+		assert.Equal(t, 1, store.setCalls(cacheKey))
 		mockGameDAO.AssertExpectations(t)
-		mockStatusCmd.AssertExpectations(t)
 		mockRawgAPI.AssertNotCalled(t, "SearchGames") // API should not be called when DB hits
 	})
 
 	t.Run("TestSearchGamesAPIHit", func(t *testing.T) {
 		// Setup
-		cacheKey := database.GetCacheKey(database.CACHE_SEARCH_GAME_KEY_PREFIX, "metroid", "1")
-		mockRedisClient.On("Get", ctx, cacheKey).Return(&redis.StringCmd{})
+		cacheKey := database.GetCacheKey(database.CACHE_SEARCH_GAME_KEY_PREFIX, "metroid", sort.String(), "10", "1")
+		// The term was never imported
+		mockGameDAO.On("GetSearchIngestion", ctx, "metroid", rawg.SourceName).Return(models.SearchIngestion{Term: "metroid", ExternalSource: rawg.SourceName}, nil)
 
-		// DB miss
-		mockGameDAO.On("SearchGames", ctx, "metroid").Return([]models.Game{}, nil)
+		// The imported games are read back from the DB
+		storedPage := models.GamePage{Games: []models.Game{{ID: uuid.NewString(), Title: "Metroid Prime"}}, Total: 1}
+		mockGameDAO.On("SearchGames", ctx, "metroid", sort, page).Return(storedPage, nil).Once()
 
 		// API hit
 		apiResponse := &rawg.GameListResponse{
@@ -220,48 +370,994 @@ func TestGameServiceUnit(t *testing.T) {
 		mockRawgAPI.On("SearchGames", ctx, "metroid", 1).Return(apiResponse, nil)
 
 		// Mock saving to DB
-		mockGameDAO.On("InsertManyGames", ctx, mock.Anything).Return(nil)
+		mockGameDAO.On("UpsertManyGames", ctx, mock.Anything).Return(nil)
+		imported := models.SearchIngestion{Term: "metroid", ExternalSource: rawg.SourceName, LastPage: 1, RemoteCount: 1}
+		mockGameDAO.On("RecordSearchIngestion", ctx, imported).Return(nil)
 
 		// Mock caching API results
-		mockStatusCmd := new(MockStatusCmd)
-		mockStatusCmd.On("Err").Return(nil)
-		mockRedisClient.On("Set", ctx, cacheKey, mock.Anything, gameService.cacheTTL).Return(mockStatusCmd)
 
 		// Call the service
-		games, err := gameService.SearchGames(ctx, "metroid", 1, "1")
+		result, err := gameService.SearchGames(ctx, "metroid", page, sort)
 
 		// Assertions
 		assert.NoError(t, err)
-		assert.Len(t, games, 1)
-		assert.Contains(t, games[0].Title, "Metroid")
+		assert.Len(t, result.Games, 1)
+		assert.Contains(t, result.Games[0].Title, "Metroid")
+		assert.Equal(t, storedPage.Games[0].ID, result.Games[0].ID)
 
 		// Verify mocks
-		mockRedisClient.AssertExpectations(t)
+		assert.Equal(t, 1, store.setCalls(cacheKey))
 		mockGameDAO.AssertExpectations(t)
 		mockRawgAPI.AssertExpectations(t)
-		mockStatusCmd.AssertExpectations(t)
+	})
+
+	t.Run("TestSearchGamesBackfill", func(t *testing.T) {
+		// Setup
+		secondPage := models.NewPageRequest(2, models.DefaultPageSize, "")
+		cacheKey := database.GetCacheKey(database.CACHE_SEARCH_GAME_KEY_PREFIX, "portal", sort.String(), "10", "2")
+
+		// Only the first of several remote pages was imported
+		ingestion := models.SearchIngestion{Term: "portal", ExternalSource: rawg.SourceName, LastPage: 1, RemoteCount: 25, HasNext: true}
+		mockGameDAO.On("GetSearchIngestion", ctx, "portal", rawg.SourceName).Return(ingestion, nil)
+
+		// The local store runs out of matches for the second page until the next remote page is imported
+		mockGameDAO.On("SearchGames", ctx, "portal", sort, secondPage).Return(models.GamePage{Total: 10}, nil).Once()
+		next := "https://api.rawg.io/api/games?page=3"
+		apiResponse := &rawg.GameListResponse{
+			Count:   25,
+			Next:    next,
+			Results: []rawg.Result{{ID: 7, Name: "Portal 2"}},
+		}
+		mockRawgAPI.On("SearchGames", ctx, "portal", 2).Return(apiResponse, nil).Once()
+		mockGameDAO.On("UpsertManyGames", ctx, mock.Anything).Return(nil)
+		imported := models.SearchIngestion{Term: "portal", ExternalSource: rawg.SourceName, LastPage: 2, RemoteCount: 25, HasNext: true}
+		mockGameDAO.On("RecordSearchIngestion", ctx, imported).Return(nil).Once()
+		games := make([]models.Game, models.DefaultPageSize)
+		for i := range games {
+			games[i] = models.Game{ID: uuid.NewString(), Title: "Portal"}
+		}
+		mockGameDAO.On("SearchGames", ctx, "portal", sort, secondPage).Return(models.GamePage{Games: games, Total: 20}, nil).Once()
+
+		// Call the service
+		result, err := gameService.SearchGames(ctx, "portal", secondPage, sort)
+
+		// Assertions
+		assert.NoError(t, err)
+		assert.Len(t, result.Games, models.DefaultPageSize)
+		assert.Equal(t, 25, result.Total, "Total should include the pages not imported yet")
+
+		// Verify mocks
+		assert.Equal(t, 1, store.setCalls(cacheKey))
+		mockGameDAO.AssertExpectations(t)
+		mockRawgAPI.AssertExpectations(t)
+	})
+
+	t.Run("TestSearchGamesQuotaExceeded", func(t *testing.T) {
+		// Setup
+		cacheKey := database.GetCacheKey(database.CACHE_SEARCH_GAME_KEY_PREFIX, "halo", sort.String(), "10", "1")
+		// The term was never imported and the API refuses to be called
+		mockGameDAO.On("GetSearchIngestion", ctx, "halo", rawg.SourceName).Return(models.SearchIngestion{Term: "halo", ExternalSource: rawg.SourceName}, nil)
+		mockRawgAPI.On("SearchGames", ctx, "halo", 1).Return(nil, rawg.ErrQuotaExceeded).Once()
+		mockGameDAO.On("SearchGames", ctx, "halo", sort, page).Return(models.GamePage{}, nil).Once()
+
+		// Call the service
+		result, err := gameService.SearchGames(ctx, "halo", page, sort)
+
+		// Assertions
+		assert.NoError(t, err, "DB results should be returned while the quota is exceeded")
+		assert.Empty(t, result.Games)
+
+		// Verify mocks
+		assert.Zero(t, store.setCalls(cacheKey))
+		mockGameDAO.AssertExpectations(t)
+		mockRawgAPI.AssertExpectations(t)
+	})
+
+	t.Run("TestGetGameFreshDetails", func(t *testing.T) {
+		// Setup
+		id := uuid.NewString()
+		cacheKey := database.GetCacheKey(database.CACHE_GAME_KEY_PREFIX, "id", id)
+
+		// DB hit with details fetched recently
+		fetchedAt := time.Now().UTC().Add(-time.Hour)
+		stored := models.Game{ID: id, Title: "Hades", ExternalID: "274755", ExternalSource: rawg.SourceName, Description: "Defy the god of the dead", DetailsFetchedAt: &fetchedAt}
+		mockGameDAO.On("GetGameByID", ctx, id).Return(stored, nil)
+
+		// Call the service
+		result, err := gameService.GetGame(ctx, id)
+
+		// Assertions
+		assert.NoError(t, err)
+		assert.Equal(t, stored, result)
+
+		// Verify mocks
+		assert.Equal(t, 1, store.setCalls(cacheKey))
+		mockGameDAO.AssertExpectations(t)
+		mockRawgAPI.AssertNotCalled(t, "GetGame", ctx, "274755") // API should not be called when details are fresh
+	})
+
+	t.Run("TestGetGameStaleDetails", func(t *testing.T) {
+		// Setup
+		id := uuid.NewString()
+		cacheKey := database.GetCacheKey(database.CACHE_GAME_KEY_PREFIX, "id", id)
+
+		// DB hit whose details were never fetched
+		stored := models.Game{ID: id, Title: "Celeste", ExternalID: "28", ExternalSource: rawg.SourceName}
+		mockGameDAO.On("GetGameByID", ctx, id).Return(stored, nil)
+
+		// API hit
+		detail := &rawg.GameDetailResponse{
+			ID: 28, Slug: "celeste", Name: "Celeste", Description: "<p>Help Madeline survive</p>", Website: "http://www.celestegame.com", Metacritic: 88, Playtime: 9,
+			Genres:     []rawg.Genre{{ID: 51, Slug: "indie", Name: "Indie"}},
+			Developers: []rawg.Genre{{ID: 7, Slug: "maddy-makes-games", Name: "Maddy Makes Games"}},
+		}
+		mockRawgAPI.On("GetGame", ctx, "28").Return(detail, nil)
+		mockGameDAO.On("SaveGameDetails", ctx, mock.MatchedBy(func(game *models.Game) bool {
+			return game.ExternalID == "28"
+		})).Run(func(args mock.Arguments) {
+			args.Get(1).(*models.Game).ID = id // the stored row keeps its id
+		}).Return(nil)
+
+		// Call the service
+		result, err := gameService.GetGame(ctx, id)
+
+		// Assertions
+		assert.NoError(t, err)
+		assert.Equal(t, id, result.ID)
+		assert.Equal(t, "Help Madeline survive", result.Description)
+		assert.Equal(t, "http://www.celestegame.com", result.Website)
+		assert.Equal(t, 88, result.Metacritic)
+		assert.Equal(t, 9, result.Playtime)
+		assert.NotNil(t, result.DetailsFetchedAt)
+		assert.Equal(t, []models.Taxonomy{{Slug: "indie", Name: "Indie"}}, result.Genres)
+		assert.Equal(t, []models.Taxonomy{{Slug: "maddy-makes-games", Name: "Maddy Makes Games"}}, result.Developers)
+		assert.Nil(t, result.Tags, "Taxonomies missing from the response should stay unknown")
+
+		// Verify mocks
+		assert.Equal(t, 1, store.setCalls(cacheKey))
+		mockGameDAO.AssertExpectations(t)
+		mockRawgAPI.AssertExpectations(t)
+	})
+
+	t.Run("TestGetGameByExternalIDStoredFromAnotherSource", func(t *testing.T) {
+		// Setup
+		cacheKey := database.GetCacheKey(database.CACHE_GAME_KEY_PREFIX, "external", rawg.SourceName, "3498")
+		mockGameDAO.On("GetGameByExternalID", ctx, rawg.SourceName, "3498").Return(models.Game{}, models.ErrGameNotFound)
+		mockRawgAPI.On("GetGame", ctx, "3498").Return(&rawg.GameDetailResponse{ID: 3498, Slug: "grand-theft-auto-v", Name: "Grand Theft Auto V"}, nil)
+		// the same game was already imported from another provider
+		mockGameDAO.On("SaveGameDetails", ctx, mock.MatchedBy(func(game *models.Game) bool {
+			return game.ExternalID == "3498"
+		})).Return(models.ErrDuplicateGame)
+
+		// Call the service
+		result, err := gameService.GetGameByExternalID(ctx, rawg.SourceName, "3498")
+
+		// Assertions
+		assert.NoError(t, err)
+		assert.Equal(t, "Grand Theft Auto V", result.Title)
+
+		// Verify mocks
+		mockGameDAO.AssertExpectations(t)
+		mockRawgAPI.AssertExpectations(t)
+		assert.Zero(t, store.setCalls(cacheKey), "The game should not be cached without being stored")
+	})
+
+	t.Run("TestGetGameByExternalIDNotFound", func(t *testing.T) {
+		// Setup
+		cacheKey := database.GetCacheKey(database.CACHE_GAME_KEY_PREFIX, "external", rawg.SourceName, "404")
+		// Neither stored nor known by the API
+		mockGameDAO.On("GetGameByExternalID", ctx, rawg.SourceName, "404").Return(models.Game{}, models.ErrGameNotFound)
+		mockRawgAPI.On("GetGame", ctx, "404").Return(nil, rawg.ErrNotFound)
+
+		// Call the service
+		_, err := gameService.GetGameByExternalID(ctx, rawg.SourceName, "404")
+
+		// Assertions
+		assert.ErrorIs(t, err, models.ErrGameNotFound)
+
+		// Verify mocks
+		mockGameDAO.AssertExpectations(t)
+		mockRawgAPI.AssertExpectations(t)
+		assert.Zero(t, store.setCalls(cacheKey))
+	})
+
+	t.Run("TestListTaxonomies", func(t *testing.T) {
+		// Setup
+		expectedPage := models.TaxonomyPage{
+			Items: []models.Taxonomy{{Slug: "action", Name: "Action", GamesCount: 12}},
+			Total: 1,
+		}
+		mockGameDAO.On("ListTaxonomies", ctx, models.TaxonomyGenre, page).Return(expectedPage, nil)
+
+		// Call the service
+		result, err := gameService.ListTaxonomies(ctx, models.TaxonomyGenre, page)
+
+		// Assertions
+		assert.NoError(t, err)
+		assert.Equal(t, expectedPage, result)
+
+		// Verify mocks
+		mockGameDAO.AssertExpectations(t)
 	})
 
 	t.Run("TestListGames", func(t *testing.T) {
 		// Setup
-		page := 1
-		platforms := []string{"PC"}
-		title := "witcher"
+		filter := models.GameFilter{Title: "witcher", Platforms: []string{"PC"}, PlatformMatch: models.PlatformMatchAny}
+		sort := models.GameSort{Field: models.SortTitle}
+
+		expectedPage := models.GamePage{
+			Games: []models.Game{{ID: uuid.NewString(), Title: "The Witcher 3"}},
+			Total: 1,
+		}
+
+		mockGameDAO.On("ListGames", ctx, filter, sort, page).Return(expectedPage, nil)
+
+		// Call the service
+		result, err := gameService.ListGames(ctx, filter, sort, page)
+
+		// Assertions
+		assert.NoError(t, err)
+		assert.Equal(t, expectedPage, result)
+
+		// Verify mocks
+		mockGameDAO.AssertExpectations(t)
+	})
+}
+
+func TestGameServiceProviders(t *testing.T) {
+	// Create mocks
+	mockGameDAO := &MockGameDAO{}
+	store := newTestStore()
+	mockRawgAPI := &MockRawgAPI{}
+	mockStatic := &MockProvider{source: providers.StaticSourceName}
+
+	// Create game service searching RAWG first, then the static catalog
+	gameService := NewGameService(mockGameDAO, store, nil, nil, providers.NewRegistry(providers.NewRawgProvider(mockRawgAPI), mockStatic))
+
+	ctx := context.Background()
+	sort := models.GameSort{Field: models.SortRelevance, Desc: true}
+	page := models.NewPageRequest(1, models.DefaultPageSize, "")
+
+	t.Run("TestSearchGamesMergesProviders", func(t *testing.T) {
+		// Setup
+		cacheKey := database.GetCacheKey(database.CACHE_SEARCH_GAME_KEY_PREFIX, "hollow", sort.String(), "10", "1")
+		// The term was never imported from either provider
+		mockGameDAO.On("GetSearchIngestion", ctx, "hollow", rawg.SourceName).Return(models.SearchIngestion{Term: "hollow", ExternalSource: rawg.SourceName}, nil)
+		mockGameDAO.On("GetSearchIngestion", ctx, "hollow", providers.StaticSourceName).Return(models.SearchIngestion{Term: "hollow", ExternalSource: providers.StaticSourceName}, nil)
+
+		// Both providers know Hollow Knight, only the static one knows its sequel
+		mockRawgAPI.On("SearchGames", ctx, "hollow", 1).Return(&rawg.GameListResponse{
+			Count:   1,
+			Results: []rawg.Result{{ID: 9767, Name: "Hollow Knight", Released: "2017-02-24"}},
+		}, nil)
+		releaseDate := time.Date(2017, 2, 24, 0, 0, 0, 0, time.UTC)
+		mockStatic.On("SearchGames", ctx, "hollow", 1).Return(providers.SearchResult{
+			Games: []models.Game{
+				{ID: uuid.NewString(), Title: "Hollow Knight", ReleaseDate: releaseDate, ExternalID: "hollow-knight", ExternalSource: providers.StaticSourceName},
+				{ID: uuid.NewString(), Title: "Hollow Knight: Silksong", ExternalID: "silksong", ExternalSource: providers.StaticSourceName},
+			},
+			Total: 2,
+		}, nil)
+
+		// The duplicate found by the static provider is dropped before storing
+		var upserted []models.Game
+		mockGameDAO.On("UpsertManyGames", ctx, mock.Anything).Run(func(args mock.Arguments) {
+			upserted = args.Get(1).([]models.Game)
+		}).Return(nil)
+		mockGameDAO.On("RecordSearchIngestion", ctx, models.SearchIngestion{Term: "hollow", ExternalSource: rawg.SourceName, LastPage: 1, RemoteCount: 1}).Return(nil)
+		mockGameDAO.On("RecordSearchIngestion", ctx, models.SearchIngestion{Term: "hollow", ExternalSource: providers.StaticSourceName, LastPage: 1, RemoteCount: 2}).Return(nil)
+		storedPage := models.GamePage{Games: []models.Game{{ID: uuid.NewString(), Title: "Hollow Knight"}, {ID: uuid.NewString(), Title: "Hollow Knight: Silksong"}}, Total: 2}
+		mockGameDAO.On("SearchGames", ctx, "hollow", sort, page).Return(storedPage, nil).Once()
+
+		// Call the service
+		result, err := gameService.SearchGames(ctx, "hollow", page, sort)
+
+		// Assertions
+		assert.NoError(t, err)
+		assert.Equal(t, storedPage, result)
+		if assert.Len(t, upserted, 2) {
+			assert.Equal(t, rawg.SourceName, upserted[0].ExternalSource, "The provider of higher priority should win")
+			assert.Equal(t, "silksong", upserted[1].ExternalID)
+		}
+
+		// Verify mocks
+		assert.Equal(t, 1, store.setCalls(cacheKey))
+		mockGameDAO.AssertExpectations(t)
+		mockRawgAPI.AssertExpectations(t)
+		mockStatic.AssertExpectations(t)
+	})
+
+	t.Run("TestSearchGamesPartialFailure", func(t *testing.T) {
+		// Setup
+		cacheKey := database.GetCacheKey(database.CACHE_SEARCH_GAME_KEY_PREFIX, "celeste", sort.String(), "10", "1")
+		mockGameDAO.On("GetSearchIngestion", ctx, "celeste", rawg.SourceName).Return(models.SearchIngestion{Term: "celeste", ExternalSource: rawg.SourceName}, nil)
+		mockGameDAO.On("GetSearchIngestion", ctx, "celeste", providers.StaticSourceName).Return(models.SearchIngestion{Term: "celeste", ExternalSource: providers.StaticSourceName}, nil)
+
+		// RAWG refuses to be called while the static catalog answers
+		mockRawgAPI.On("SearchGames", ctx, "celeste", 1).Return(nil, rawg.ErrCircuitOpen)
+		celeste := models.Game{ID: uuid.NewString(), Title: "Celeste", ExternalID: "celeste", ExternalSource: providers.StaticSourceName}
+		mockStatic.On("SearchGames", ctx, "celeste", 1).Return(providers.SearchResult{Games: []models.Game{celeste}, Total: 1}, nil)
+		mockGameDAO.On("UpsertManyGames", ctx, []models.Game{celeste}).Return(nil)
+		mockGameDAO.On("RecordSearchIngestion", ctx, models.SearchIngestion{Term: "celeste", ExternalSource: providers.StaticSourceName, LastPage: 1, RemoteCount: 1}).Return(nil)
+		storedPage := models.GamePage{Games: []models.Game{celeste}, Total: 1}
+		mockGameDAO.On("SearchGames", ctx, "celeste", sort, page).Return(storedPage, nil).Once()
+
+		// Call the service
+		result, err := gameService.SearchGames(ctx, "celeste", page, sort)
+
+		// Assertions
+		assert.NoError(t, err)
+		assert.Equal(t, storedPage, result)
+		cached, found, _ := gameService.searchPages.Get(ctx, cacheKey)
+		assert.True(t, found, "The games of the providers that answered should be cached")
+		assert.False(t, cached.FreshUntil.After(time.Now()), "RAWG should be asked again by the next request")
+
+		// Verify mocks
+		mockGameDAO.AssertNotCalled(t, "RecordSearchIngestion", ctx, models.SearchIngestion{Term: "celeste", ExternalSource: rawg.SourceName, LastPage: 1})
+		mockGameDAO.AssertExpectations(t)
+		mockStatic.AssertExpectations(t)
+	})
+
+	t.Run("TestSearchGamesBackfillsFromTheProvidersThatAnswer", func(t *testing.T) {
+		// Setup
+		t.Setenv("SEARCH_MAX_BACKFILL_PAGES", "1")
+		mockGameDAO := &MockGameDAO{}
+		mockFirst := &MockProvider{source: "first"}
+		mockSecond := &MockProvider{source: providers.StaticSourceName}
+		gameService := NewGameService(mockGameDAO, newTestStore(), nil, nil, providers.NewRegistry(mockFirst, mockSecond))
+		cacheKey := database.GetCacheKey(database.CACHE_SEARCH_GAME_KEY_PREFIX, "metroid", sort.String(), "10", "1")
+		// both providers have more pages, sharing most of their games
+		mockGameDAO.On("GetSearchIngestion", ctx, "metroid", "first").Return(models.SearchIngestion{Term: "metroid", ExternalSource: "first", LastPage: 1, RemoteCount: 30, HasNext: true}, nil)
+		mockGameDAO.On("GetSearchIngestion", ctx, "metroid", providers.StaticSourceName).Return(models.SearchIngestion{Term: "metroid", ExternalSource: providers.StaticSourceName, LastPage: 1, RemoteCount: 25, HasNext: true}, nil)
+		stored := models.GamePage{Games: []models.Game{{ID: uuid.NewString(), Title: "Metroid"}}, Total: 1}
+		mockGameDAO.On("SearchGames", ctx, "metroid", sort, page).Return(stored, nil).Once()
+
+		// The first provider fails while the second one answers
+		mockFirst.On("SearchGames", ctx, "metroid", 2).Return(providers.SearchResult{}, errors.New("connection reset")).Once()
+		dread := models.Game{ID: uuid.NewString(), Title: "Metroid Dread", ExternalID: "dread", ExternalSource: providers.StaticSourceName}
+		mockSecond.On("SearchGames", ctx, "metroid", 2).Return(providers.SearchResult{Games: []models.Game{dread}, Total: 25, HasNext: true}, nil).Once()
+		mockGameDAO.On("UpsertManyGames", ctx, []models.Game{dread}).Return(nil)
+		mockGameDAO.On("RecordSearchIngestion", ctx, models.SearchIngestion{Term: "metroid", ExternalSource: providers.StaticSourceName, LastPage: 2, RemoteCount: 25, HasNext: true}).Return(nil)
+		backfilled := models.GamePage{Games: []models.Game{stored.Games[0], dread}, Total: 2}
+		mockGameDAO.On("SearchGames", ctx, "metroid", sort, page).Return(backfilled, nil).Once()
+
+		// Call the service
+		result, err := gameService.SearchGames(ctx, "metroid", page, sort)
+
+		// Assertions
+		assert.NoError(t, err)
+		assert.Equal(t, backfilled.Games, result.Games)
+		assert.Equal(t, 30, result.Total, "The remote counts of providers sharing games should not be added up")
+		_, found, _ := gameService.searchPages.Get(ctx, cacheKey)
+		assert.True(t, found)
+
+		// Verify mocks
+		mockGameDAO.AssertExpectations(t)
+		mockFirst.AssertExpectations(t)
+		mockSecond.AssertExpectations(t)
+	})
+}
+
+func TestGameServiceRawgStub(t *testing.T) {
+	// Create a game service calling the RAWG stub through the real client
+	mockGameDAO := &MockGameDAO{}
+	store := newTestStore()
+	stub := rawgtest.NewServer(t, rawgtest.Fixtures(), rawgtest.Faults{})
+	rawgAPI := rawg.NewRawgAPIWithConfig(rawg.Config{BaseURL: stub.URL, APIKeys: []string{"test-key"}, Timeout: time.Second}, nil, nil)
+	gameService := NewGameService(mockGameDAO, store, nil, nil, providers.NewRegistry(providers.NewRawgProvider(rawgAPI)))
+
+	ctx := context.Background()
+	sort := models.GameSort{Field: models.SortRelevance, Desc: true}
+	page := models.NewPageRequest(1, models.DefaultPageSize, "")
+
+	t.Run("TestSearchGamesImportsMatches", func(t *testing.T) {
+		// Setup
+		cacheKey := database.GetCacheKey(database.CACHE_SEARCH_GAME_KEY_PREFIX, "half life", sort.String(), "10", "1")
+		mockGameDAO.On("GetSearchIngestion", ctx, "half life", rawg.SourceName).Return(models.SearchIngestion{Term: "half life", ExternalSource: rawg.SourceName}, nil)
+		var upserted []models.Game
+		mockGameDAO.On("UpsertManyGames", ctx, mock.Anything).Run(func(args mock.Arguments) {
+			upserted = args.Get(1).([]models.Game)
+		}).Return(nil)
+		mockGameDAO.On("RecordSearchIngestion", ctx, models.SearchIngestion{Term: "half life", ExternalSource: rawg.SourceName, LastPage: 1, RemoteCount: 2}).Return(nil)
+		mockGameDAO.On("SearchGames", ctx, "half life", sort, page).Return(models.GamePage{Total: 2}, nil)
+
+		// Call the service
+		_, err := gameService.SearchGames(ctx, "half life", page, sort)
+
+		// Assertions
+		assert.NoError(t, err)
+		var titles []string
+		for _, game := range upserted {
+			titles = append(titles, game.Title)
+		}
+		assert.ElementsMatch(t, []string{"Half-Life", "Half-Life 2"}, titles)
+
+		// Verify mocks
+		mockGameDAO.AssertExpectations(t)
+		assert.Equal(t, 1, store.setCalls(cacheKey))
+	})
+
+	t.Run("TestGetGameRateLimited", func(t *testing.T) {
+		// Setup
+		stub.SetFaults(rawgtest.Faults{RateLimitEvery: 1})
+		defer stub.SetFaults(rawgtest.Faults{})
+		cacheKey := database.GetCacheKey(database.CACHE_GAME_KEY_PREFIX, "external", rawg.SourceName, "4200")
+		stored := models.Game{ID: uuid.NewString(), Title: "Portal 2", ExternalID: "4200", ExternalSource: rawg.SourceName}
+		mockGameDAO.On("GetGameByExternalID", ctx, rawg.SourceName, "4200").Return(stored, nil)
+
+		// Call the service
+		result, err := gameService.GetGameByExternalID(ctx, rawg.SourceName, "4200")
+
+		// Assertions
+		assert.NoError(t, err)
+		assert.Equal(t, stored, result, "The stored game should be served while RAWG throttles")
+
+		// Verify mocks
+		assert.Zero(t, store.setCalls(cacheKey))
+		mockGameDAO.AssertNotCalled(t, "SaveGameDetails", ctx, mock.Anything)
+	})
+}
+
+func TestGameServiceSearchCoalescing(t *testing.T) {
+	ctx := context.Background()
+	sort := models.GameSort{Field: models.SortRelevance, Desc: true}
+	page := models.NewPageRequest(1, models.DefaultPageSize, "")
+	// Every page of the term was already imported
+	imported := func(term string) models.SearchIngestion {
+		return models.SearchIngestion{Term: term, ExternalSource: rawg.SourceName, LastPage: 1, RemoteCount: 1}
+	}
+	newService := func(locker Locker) (*GameService, *MockGameDAO, *testStore) {
+		mockGameDAO := &MockGameDAO{}
+		store := newTestStore()
+		gameService := NewGameService(mockGameDAO, store, locker, nil, providers.NewRegistry(providers.NewRawgProvider(&MockRawgAPI{})))
+		gameService.searchLockPoll = time.Millisecond
+		return gameService, mockGameDAO, store
+	}
+
+	t.Run("TestConcurrentMissesShareASearch", func(t *testing.T) {
+		// Setup
+		gameService, mockGameDAO, store := newService(nil)
+		cacheKey := database.GetCacheKey(database.CACHE_SEARCH_GAME_KEY_PREFIX, "doom", sort.String(), "10", "1")
+		mockGameDAO.On("GetSearchIngestion", ctx, "doom", rawg.SourceName).Return(imported("doom"), nil)
+		release := make(chan struct{})
+		dbPage := models.GamePage{Games: []models.Game{{ID: uuid.NewString(), Title: "DOOM"}}, Total: 1}
+		mockGameDAO.On("SearchGames", ctx, "doom", sort, page).Run(func(mock.Arguments) { <-release }).Return(dbPage, nil)
+
+		// Call the service concurrently
+		results := make([]models.GamePage, 5)
+		errs := make([]error, len(results))
+		var wg sync.WaitGroup
+		for i := range results {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				results[i], errs[i] = gameService.SearchGames(ctx, "doom", page, sort)
+			}()
+		}
+		time.Sleep(50 * time.Millisecond) // let every call miss the cache
+		close(release)
+		wg.Wait()
+
+		// Assertions
+		for i := range results {
+			assert.NoError(t, errs[i])
+			assert.Equal(t, dbPage, results[i])
+		}
+
+		// Verify mocks
+		mockGameDAO.AssertNumberOfCalls(t, "SearchGames", 1)
+		assert.Equal(t, 1, store.setCalls(cacheKey))
+	})
+
+	t.Run("TestSearchHoldsTheLock", func(t *testing.T) {
+		// Setup
+		mockLocker := &MockLocker{}
+		gameService, mockGameDAO, store := newService(mockLocker)
+		cacheKey := database.GetCacheKey(database.CACHE_SEARCH_GAME_KEY_PREFIX, "quake", sort.String(), "10", "1")
+		lockKey := database.GetCacheKey(database.CACHE_LOCK_KEY_PREFIX, cacheKey)
+		released := false
+		mockLocker.On("TryLock", ctx, lockKey, gameService.searchLockTTL).Return(func() { released = true }, true, nil).Once()
+		mockGameDAO.On("GetSearchIngestion", ctx, "quake", rawg.SourceName).Return(imported("quake"), nil)
+		mockGameDAO.On("SearchGames", ctx, "quake", sort, page).Return(models.GamePage{Games: []models.Game{{ID: uuid.NewString(), Title: "Quake"}}, Total: 1}, nil)
+		store.onSet = func(string) {
+			assert.False(t, released, "The lock should be held until the cache is filled")
+		}
+
+		// Call the service
+		_, err := gameService.SearchGames(ctx, "quake", page, sort)
+
+		// Assertions
+		assert.NoError(t, err)
+		assert.True(t, released, "The lock should be released")
+
+		// Verify mocks
+		mockLocker.AssertExpectations(t)
+		mockGameDAO.AssertExpectations(t)
+	})
+
+	t.Run("TestWaitsForTheLockHolder", func(t *testing.T) {
+		// Setup
+		mockLocker := &MockLocker{}
+		gameService, mockGameDAO, store := newService(mockLocker)
+		cacheKey := database.GetCacheKey(database.CACHE_SEARCH_GAME_KEY_PREFIX, "hexen", sort.String(), "10", "1")
+		lockKey := database.GetCacheKey(database.CACHE_LOCK_KEY_PREFIX, cacheKey)
+
+		// Another instance holds the lock and fills the cache
+		cachedPage := models.GamePage{Games: []models.Game{{ID: uuid.NewString(), Title: "Hexen"}}, Total: 1}
+		mockLocker.On("TryLock", ctx, lockKey, gameService.searchLockTTL).Run(func(mock.Arguments) {
+			gameService.searchPages.Set(ctx, cacheKey, cachedSearchPage{FreshUntil: time.Now().Add(time.Hour), Page: cachedPage}, time.Hour)
+		}).Return(nil, false, nil)
+
+		// Call the service
+		result, err := gameService.SearchGames(ctx, "hexen", page, sort)
+
+		// Assertions
+		assert.NoError(t, err)
+		if assert.Len(t, result.Games, 1) {
+			assert.Equal(t, "Hexen", result.Games[0].Title)
+		}
 
-		expectedGames := []models.Game{{ID: uuid.NewString(), Title: "The Witcher 3"}}
-		expectedTotal := 1
+		// Verify mocks
+		mockGameDAO.AssertNotCalled(t, "SearchGames", ctx, "hexen", sort, page)
+		assert.Equal(t, 1, store.setCalls(cacheKey), "Only the lock holder should fill the cache")
+	})
+
+	t.Run("TestSearchesWithoutAStuckLock", func(t *testing.T) {
+		// Setup
+		mockLocker := &MockLocker{}
+		gameService, mockGameDAO, store := newService(mockLocker)
+		gameService.searchLockWait = 10 * time.Millisecond
+		cacheKey := database.GetCacheKey(database.CACHE_SEARCH_GAME_KEY_PREFIX, "heretic", sort.String(), "10", "1")
+		lockKey := database.GetCacheKey(database.CACHE_LOCK_KEY_PREFIX, cacheKey)
 
-		mockGameDAO.On("ListGames", ctx, page, platforms, title).Return(expectedGames, expectedTotal, nil)
+		// The lock is never released
+		mockLocker.On("TryLock", ctx, lockKey, gameService.searchLockTTL).Return(nil, false, nil)
+		mockGameDAO.On("GetSearchIngestion", ctx, "heretic", rawg.SourceName).Return(imported("heretic"), nil)
+		mockGameDAO.On("SearchGames", ctx, "heretic", sort, page).Return(models.GamePage{Games: []models.Game{{ID: uuid.NewString(), Title: "Heretic"}}, Total: 1}, nil)
 
 		// Call the service
-		games, total, err := gameService.ListGames(ctx, page, platforms, title)
+		result, err := gameService.SearchGames(ctx, "heretic", page, sort)
+
+		// Assertions
+		assert.NoError(t, err)
+		assert.Len(t, result.Games, 1)
+
+		// Verify mocks
+		mockGameDAO.AssertExpectations(t)
+		assert.Equal(t, 1, store.setCalls(cacheKey))
+	})
+
+	t.Run("TestSearchesWithoutAnUnavailableLock", func(t *testing.T) {
+		// Setup
+		mockLocker := &MockLocker{}
+		gameService, mockGameDAO, store := newService(mockLocker)
+		cacheKey := database.GetCacheKey(database.CACHE_SEARCH_GAME_KEY_PREFIX, "wolfenstein", sort.String(), "10", "1")
+		lockKey := database.GetCacheKey(database.CACHE_LOCK_KEY_PREFIX, cacheKey)
+		mockLocker.On("TryLock", ctx, lockKey, gameService.searchLockTTL).Return(nil, false, errors.New("connection refused")).Once()
+		mockGameDAO.On("GetSearchIngestion", ctx, "wolfenstein", rawg.SourceName).Return(imported("wolfenstein"), nil)
+		mockGameDAO.On("SearchGames", ctx, "wolfenstein", sort, page).Return(models.GamePage{Games: []models.Game{{ID: uuid.NewString(), Title: "Wolfenstein 3D"}}, Total: 1}, nil)
+
+		// Call the service
+		_, err := gameService.SearchGames(ctx, "wolfenstein", page, sort)
+
+		// Assertions
+		assert.NoError(t, err)
+
+		// Verify mocks
+		mockLocker.AssertExpectations(t)
+		mockGameDAO.AssertExpectations(t)
+		assert.Equal(t, 1, store.setCalls(cacheKey))
+	})
+}
+
+func TestGameServiceStaleWhileRevalidate(t *testing.T) {
+	ctx := context.Background()
+	sort := models.GameSort{Field: models.SortRelevance, Desc: true}
+	page := models.NewPageRequest(1, models.DefaultPageSize, "")
+	imported := func(term string) models.SearchIngestion {
+		return models.SearchIngestion{Term: term, ExternalSource: rawg.SourceName, LastPage: 1, RemoteCount: 1}
+	}
+	newService := func(locker Locker) (*GameService, *MockGameDAO, *testStore) {
+		mockGameDAO := &MockGameDAO{}
+		store := newTestStore()
+		gameService := NewGameService(mockGameDAO, store, locker, nil, providers.NewRegistry(providers.NewRawgProvider(&MockRawgAPI{})))
+		return gameService, mockGameDAO, store
+	}
+	// cacheStalePage caches a page of a single game past its soft expiry
+	cacheStalePage := func(gameService *GameService, cacheKey, title string) {
+		page := models.GamePage{Games: []models.Game{{ID: uuid.NewString(), Title: title}}, Total: 1}
+		gameService.searchPages.Set(ctx, cacheKey, cachedSearchPage{FreshUntil: time.Now().Add(-time.Minute), Page: page}, time.Hour)
+	}
+
+	t.Run("TestServesStalePagesWhileRefreshing", func(t *testing.T) {
+		// Setup
+		gameService, mockGameDAO, store := newService(nil)
+		cacheKey := database.GetCacheKey(database.CACHE_SEARCH_GAME_KEY_PREFIX, "myst", sort.String(), "10", "1")
+		cacheStalePage(gameService, cacheKey, "Myst")
+		release := make(chan struct{})
+		mockGameDAO.On("GetSearchIngestion", mock.Anything, "myst", rawg.SourceName).Return(imported("myst"), nil)
+		mockGameDAO.On("SearchGames", mock.Anything, "myst", sort, page).Run(func(mock.Arguments) { <-release }).
+			Return(models.GamePage{Games: []models.Game{{ID: uuid.NewString(), Title: "Myst: Masterpiece Edition"}}, Total: 1}, nil)
+		refreshed := make(chan struct{})
+		store.onSet = func(string) { close(refreshed) }
+
+		// Call the service
+		result, err := gameService.SearchGames(ctx, "myst", page, sort)
+
+		// Assertions
+		assert.NoError(t, err)
+		if assert.Len(t, result.Games, 1) {
+			assert.Equal(t, "Myst", result.Games[0].Title, "The stale page should be served without waiting for the refresh")
+		}
+		close(release)
+		select {
+		case <-refreshed:
+		case <-time.After(time.Second):
+			t.Fatal("The stale page was not refreshed")
+		}
+		assert.Eventually(t, func() bool {
+			cached, found, err := gameService.searchPages.Get(ctx, cacheKey)
+			return err == nil && found && cached.FreshUntil.After(time.Now()) &&
+				len(cached.Page.Games) == 1 && cached.Page.Games[0].Title == "Myst: Masterpiece Edition"
+		}, time.Second, time.Millisecond)
+
+		// Verify mocks
+		mockGameDAO.AssertNumberOfCalls(t, "SearchGames", 1)
+	})
+
+	t.Run("TestSkipsRefreshesLockedElsewhere", func(t *testing.T) {
+		// Setup
+		mockLocker := &MockLocker{}
+		gameService, mockGameDAO, store := newService(mockLocker)
+		cacheKey := database.GetCacheKey(database.CACHE_SEARCH_GAME_KEY_PREFIX, "riven", sort.String(), "10", "1")
+		lockKey := database.GetCacheKey(database.CACHE_LOCK_KEY_PREFIX, cacheKey)
+		cacheStalePage(gameService, cacheKey, "Riven")
+		mockLocker.On("TryLock", mock.Anything, lockKey, gameService.searchLockTTL).Return(nil, false, nil).Once()
+
+		// Call the service
+		result, err := gameService.SearchGames(ctx, "riven", page, sort)
+
+		// Assertions
+		assert.NoError(t, err)
+		assert.Len(t, result.Games, 1)
+		assert.Eventually(t, func() bool {
+			gameService.refresher.mu.Lock()
+			defer gameService.refresher.mu.Unlock()
+			return len(gameService.refresher.pending) == 0
+		}, time.Second, time.Millisecond)
+
+		// Verify mocks
+		mockLocker.AssertExpectations(t)
+		mockGameDAO.AssertNotCalled(t, "SearchGames", mock.Anything, "riven", sort, page)
+		assert.Equal(t, 1, store.setCalls(cacheKey), "Only the stale page should have been cached")
+	})
+}
+
+func TestGameServiceCacheFailures(t *testing.T) {
+	ctx := context.Background()
+	sort := models.GameSort{Field: models.SortRelevance, Desc: true}
+	page := models.NewPageRequest(1, models.DefaultPageSize, "")
+	newService := func() (*GameService, *MockGameDAO, *testStore) {
+		mockGameDAO := &MockGameDAO{}
+		store := newTestStore()
+		gameService := NewGameService(mockGameDAO, store, nil, nil, providers.NewRegistry(providers.NewRawgProvider(&MockRawgAPI{})))
+		return gameService, mockGameDAO, store
+	}
+	imported := func(term string) models.SearchIngestion {
+		return models.SearchIngestion{Term: term, ExternalSource: rawg.SourceName, LastPage: 1, RemoteCount: 1}
+	}
+
+	t.Run("TestSearchesWithoutAnUnavailableCache", func(t *testing.T) {
+		// Setup
+		gameService, mockGameDAO, store := newService()
+		store.err = database.ErrCacheUnavailable
+		mockGameDAO.On("GetSearchIngestion", ctx, "tetris", rawg.SourceName).Return(imported("tetris"), nil)
+		mockGameDAO.On("SearchGames", ctx, "tetris", sort, page).Return(models.GamePage{Games: []models.Game{{ID: uuid.NewString(), Title: "Tetris"}}, Total: 1}, nil)
+
+		// Call the service
+		result, err := gameService.SearchGames(ctx, "tetris", page, sort)
+
+		// Assertions
+		assert.NoError(t, err)
+		if assert.Len(t, result.Games, 1) {
+			assert.Equal(t, "Tetris", result.Games[0].Title)
+		}
+
+		// Verify mocks
+		mockGameDAO.AssertExpectations(t)
+	})
+
+	t.Run("TestRecomputesCorruptedSearchPages", func(t *testing.T) {
+		// Setup
+		gameService, mockGameDAO, store := newService()
+		cacheKey := database.GetCacheKey(database.CACHE_SEARCH_GAME_KEY_PREFIX, "lemmings", sort.String(), "10", "1")
+		store.MemoryStore.Set(ctx, gameService.searchPages.Key(cacheKey), []byte(`{"freshUntil":`), time.Hour)
+		mockGameDAO.On("GetSearchIngestion", ctx, "lemmings", rawg.SourceName).Return(imported("lemmings"), nil)
+		mockGameDAO.On("SearchGames", ctx, "lemmings", sort, page).Return(models.GamePage{Games: []models.Game{{ID: uuid.NewString(), Title: "Lemmings"}}, Total: 1}, nil)
+
+		// Call the service
+		result, err := gameService.SearchGames(ctx, "lemmings", page, sort)
+
+		// Assertions
+		assert.NoError(t, err)
+		assert.Len(t, result.Games, 1)
+		_, found, err := gameService.searchPages.Get(ctx, cacheKey)
+		assert.NoError(t, err)
+		assert.True(t, found, "The corrupted page should have been replaced")
+
+		// Verify mocks
+		mockGameDAO.AssertExpectations(t)
+	})
+
+	t.Run("TestRecomputesCorruptedGames", func(t *testing.T) {
+		// Setup
+		gameService, mockGameDAO, store := newService()
+		id := uuid.NewString()
+		cacheKey := database.GetCacheKey(database.CACHE_GAME_KEY_PREFIX, "id", id)
+		store.MemoryStore.Set(ctx, gameService.games.Key(cacheKey), []byte("not json"), time.Hour)
+		stored := models.Game{ID: id, Title: "Prince of Persia"}
+		mockGameDAO.On("GetGameByID", ctx, id).Return(stored, nil)
+
+		// Call the service
+		result, err := gameService.GetGame(ctx, id)
+
+		// Assertions
+		assert.NoError(t, err)
+		assert.Equal(t, stored, result)
+
+		// Verify mocks
+		mockGameDAO.AssertExpectations(t)
+		assert.Equal(t, 1, store.setCalls(cacheKey))
+	})
+
+	t.Run("TestIgnoresPagesOfAnotherSchema", func(t *testing.T) {
+		// Setup
+		gameService, mockGameDAO, store := newService()
+		cacheKey := database.GetCacheKey(database.CACHE_SEARCH_GAME_KEY_PREFIX, "zork", sort.String(), "10", "1")
+		// cached before the soft expiry was added to search pages
+		store.MemoryStore.Set(ctx, cacheKey, []byte(`{"games":[{"title":"Zork"}],"total":1}`), time.Hour)
+		mockGameDAO.On("GetSearchIngestion", ctx, "zork", rawg.SourceName).Return(imported("zork"), nil)
+		mockGameDAO.On("SearchGames", ctx, "zork", sort, page).Return(models.GamePage{Games: []models.Game{{ID: uuid.NewString(), Title: "Zork I"}}, Total: 1}, nil)
+
+		// Call the service
+		result, err := gameService.SearchGames(ctx, "zork", page, sort)
+
+		// Assertions
+		assert.NoError(t, err)
+		if assert.Len(t, result.Games, 1) {
+			assert.Equal(t, "Zork I", result.Games[0].Title)
+		}
+
+		// Verify mocks
+		mockGameDAO.AssertExpectations(t)
+		assert.Equal(t, 1, store.setCalls(cacheKey))
+	})
+}
+
+func TestGameServiceCacheInvalidation(t *testing.T) {
+	ctx := context.Background()
+	sort := models.GameSort{Field: models.SortRelevance, Desc: true}
+	page := models.NewPageRequest(1, models.DefaultPageSize, "")
+	celeste := models.Game{ID: uuid.NewString(), Title: "Celeste", ExternalID: "celeste", ExternalSource: providers.StaticSourceName}
+	hades := models.Game{ID: uuid.NewString(), Title: "Hades", ExternalID: "hades", ExternalSource: providers.StaticSourceName}
+	cachedPage := func(games ...models.Game) cachedSearchPage {
+		return cachedSearchPage{FreshUntil: time.Now().Add(time.Hour), Page: models.GamePage{Games: games, Total: len(games)}}
+	}
+
+	t.Run("TestEvictsSearchPagesOfUpsertedGames", func(t *testing.T) {
+		// Setup
+		mockGameDAO := &MockGameDAO{}
+		mockStatic := &MockProvider{source: providers.StaticSourceName}
+		gameService := NewGameService(mockGameDAO, newTestStore(), nil, nil, providers.NewRegistry(mockStatic))
+		platformerKey := database.GetCacheKey(database.CACHE_SEARCH_GAME_KEY_PREFIX, "platformer", sort.String(), "10", "1")
+		roguelikeKey := database.GetCacheKey(database.CACHE_SEARCH_GAME_KEY_PREFIX, "roguelike", sort.String(), "10", "1")
+		gameService.searchPages.Set(ctx, platformerKey, cachedPage(celeste), time.Hour)
+		gameService.searchPages.Set(ctx, roguelikeKey, cachedPage(hades), time.Hour)
+
+		// A new search imports Celeste again
+		mockGameDAO.On("GetSearchIngestion", ctx, "celeste", providers.StaticSourceName).Return(models.SearchIngestion{Term: "celeste", ExternalSource: providers.StaticSourceName}, nil)
+		mockStatic.On("SearchGames", ctx, "celeste", 1).Return(providers.SearchResult{Games: []models.Game{celeste}, Total: 1}, nil)
+		mockGameDAO.On("UpsertManyGames", ctx, []models.Game{celeste}).Return(nil)
+		mockGameDAO.On("RecordSearchIngestion", ctx, models.SearchIngestion{Term: "celeste", ExternalSource: providers.StaticSourceName, LastPage: 1, RemoteCount: 1}).Return(nil)
+		mockGameDAO.On("SearchGames", ctx, "celeste", sort, page).Return(models.GamePage{Games: []models.Game{celeste}, Total: 1}, nil)
+
+		// Call the service
+		_, err := gameService.SearchGames(ctx, "celeste", page, sort)
+
+		// Assertions
+		assert.NoError(t, err)
+		_, found, _ := gameService.searchPages.Get(ctx, platformerKey)
+		assert.False(t, found, "The page containing the upserted game should be evicted")
+		_, found, _ = gameService.searchPages.Get(ctx, roguelikeKey)
+		assert.True(t, found, "The pages of other games should be kept")
+
+		// Verify mocks
+		mockGameDAO.AssertExpectations(t)
+		mockStatic.AssertExpectations(t)
+	})
+
+	t.Run("TestEvictsCacheEntriesOfBulkImportedGames", func(t *testing.T) {
+		// Setup
+		mockGameDAO := &MockGameDAO{}
+		gameService := NewGameService(mockGameDAO, newTestStore(), nil, nil, providers.NewRegistry())
+		platformerKey := database.GetCacheKey(database.CACHE_SEARCH_GAME_KEY_PREFIX, "platformer", sort.String(), "10", "1")
+		roguelikeKey := database.GetCacheKey(database.CACHE_SEARCH_GAME_KEY_PREFIX, "roguelike", sort.String(), "10", "1")
+		emptyKey := database.GetCacheKey(database.CACHE_SEARCH_EMPTY_KEY_PREFIX, "celeste")
+		gameService.searchPages.Set(ctx, platformerKey, cachedPage(celeste), time.Hour)
+		gameService.searchPages.Set(ctx, roguelikeKey, cachedPage(hades), time.Hour)
+		gameService.emptySearches.Set(ctx, emptyKey, emptySearch{Query: "celeste", TitlePrefixes: []string{"cel"}}, time.Hour)
+		mockGameDAO.On("BulkImportGames", ctx, []models.Game{celeste}).Return(models.ImportResult{Updated: 1}, nil)
+
+		// Call the service
+		result, err := gameService.ImportGames(ctx, []models.Game{celeste})
+
+		// Assertions
+		assert.NoError(t, err)
+		assert.Equal(t, models.ImportResult{Updated: 1}, result)
+		_, found, _ := gameService.searchPages.Get(ctx, platformerKey)
+		assert.False(t, found, "The page containing the imported game should be evicted")
+		_, found, _ = gameService.emptySearches.Get(ctx, emptyKey)
+		assert.False(t, found, "The empty search matching the imported title should be evicted")
+		_, found, _ = gameService.searchPages.Get(ctx, roguelikeKey)
+		assert.True(t, found, "The pages of other games should be kept")
+
+		// Verify mocks
+		mockGameDAO.AssertExpectations(t)
+	})
+
+	t.Run("TestEvictsSearchPagesAfterCatalogPagesOfTheirGamesExpire", func(t *testing.T) {
+		// Setup
+		now := time.Now()
+		store := &testStore{MemoryStore: cache.NewMemoryStoreWithClock(func() time.Time { return now }), sets: map[string]int{}}
+		gameService := NewGameService(&MockGameDAO{}, store, nil, nil, providers.NewRegistry())
+		searchKey := database.GetCacheKey(database.CACHE_SEARCH_GAME_KEY_PREFIX, "platformer", sort.String(), "10", "1")
+		catalogKey := database.GetCacheKey(database.CACHE_CATALOG_KEY_PREFIX, "indie")
+		gameService.searchPages.Set(ctx, searchKey, cachedPage(celeste), gameService.cacheTTL)
+		// the catalog page is cached later and for much shorter
+		gameService.catalogPages.Set(ctx, catalogKey, models.GamePage{Games: []models.Game{celeste}, Total: 1}, gameService.catalogTTL)
+		now = now.Add(gameService.catalogTTL + time.Minute)
+
+		// Call the service
+		gameService.invalidateGames(ctx, celeste)
+
+		// Assertions
+		_, found, _ := gameService.searchPages.Get(ctx, searchKey)
+		assert.False(t, found, "The search page should still be evicted once the catalog page expired")
+	})
+
+	t.Run("TestEvictsCachedCopiesOfGamesWithNewDetails", func(t *testing.T) {
+		// Setup
+		mockGameDAO := &MockGameDAO{}
+		mockStatic := &MockProvider{source: providers.StaticSourceName}
+		store := newTestStore()
+		gameService := NewGameService(mockGameDAO, store, nil, nil, providers.NewRegistry(mockStatic))
+		externalKey := database.GetCacheKey(database.CACHE_GAME_KEY_PREFIX, "external", providers.StaticSourceName, "celeste")
+		idKey := database.GetCacheKey(database.CACHE_GAME_KEY_PREFIX, "id", celeste.ID)
+		searchKey := database.GetCacheKey(database.CACHE_SEARCH_GAME_KEY_PREFIX, "platformer", sort.String(), "10", "1")
+		gameService.games.Set(ctx, externalKey, celeste, 0)
+		gameService.searchPages.Set(ctx, searchKey, cachedPage(celeste, hades), time.Hour)
+
+		// The details of Celeste were never fetched
+		mockGameDAO.On("GetGameByID", ctx, celeste.ID).Return(celeste, nil)
+		detailed := celeste
+		detailed.Description = "Help Madeline survive"
+		mockStatic.On("GetGame", ctx, "celeste").Return(detailed, nil)
+		mockGameDAO.On("SaveGameDetails", ctx, mock.Anything).Return(nil)
+
+		// Call the service
+		result, err := gameService.GetGame(ctx, celeste.ID)
+
+		// Assertions
+		assert.NoError(t, err)
+		assert.Equal(t, "Help Madeline survive", result.Description)
+		_, found, _ := gameService.games.Get(ctx, externalKey)
+		assert.False(t, found, "The copy cached by external id should be evicted")
+		_, found, _ = gameService.searchPages.Get(ctx, searchKey)
+		assert.False(t, found, "The pages containing the game should be evicted")
+		cached, found, _ := gameService.games.Get(ctx, idKey)
+		assert.True(t, found)
+		assert.Equal(t, "Help Madeline survive", cached.Description)
+
+		// Verify mocks
+		mockGameDAO.AssertExpectations(t)
+		mockStatic.AssertExpectations(t)
+	})
+}
+
+func TestGameServiceNegativeCache(t *testing.T) {
+	ctx := context.Background()
+	sort := models.GameSort{Field: models.SortRelevance, Desc: true}
+	page := models.NewPageRequest(1, models.DefaultPageSize, "")
+	newService := func() (*GameService, *MockGameDAO, *MockProvider, *testStore) {
+		mockGameDAO := &MockGameDAO{}
+		mockStatic := &MockProvider{source: providers.StaticSourceName}
+		store := newTestStore()
+		gameService := NewGameService(mockGameDAO, store, nil, nil, providers.NewRegistry(mockStatic))
+		return gameService, mockGameDAO, mockStatic, store
+	}
+	// searchNothing expects a first search of term finding nothing in the provider or the database
+	searchNothing := func(mockGameDAO *MockGameDAO, mockStatic *MockProvider, term string) {
+		mockGameDAO.On("GetSearchIngestion", ctx, term, providers.StaticSourceName).Return(models.SearchIngestion{Term: term, ExternalSource: providers.StaticSourceName}, nil).Once()
+		mockStatic.On("SearchGames", ctx, term, 1).Return(providers.SearchResult{}, nil).Once()
+		mockGameDAO.On("RecordSearchIngestion", ctx, models.SearchIngestion{Term: term, ExternalSource: providers.StaticSourceName, LastPage: 1}).Return(nil).Once()
+		mockGameDAO.On("SearchGames", ctx, term, sort, page).Return(models.GamePage{}, nil).Once()
+	}
+
+	t.Run("TestCachesSearchesWithoutResults", func(t *testing.T) {
+		// Setup
+		gameService, mockGameDAO, mockStatic, store := newService()
+		pageKey := database.GetCacheKey(database.CACHE_SEARCH_GAME_KEY_PREFIX, "xyzzy", sort.String(), "10", "1")
+		emptyKey := database.GetCacheKey(database.CACHE_SEARCH_EMPTY_KEY_PREFIX, "xyzzy")
+		searchNothing(mockGameDAO, mockStatic, "xyzzy")
+
+		// Call the service, then again for another page and sort
+		first, err := gameService.SearchGames(ctx, "xyzzy", page, sort)
+		assert.NoError(t, err)
+		second, err := gameService.SearchGames(ctx, "Xyzzy", models.NewPageRequest(2, 20, ""), models.GameSort{Field: models.SortTitle})
+
+		// Assertions
+		assert.NoError(t, err)
+		assert.Empty(t, first.Games)
+		assert.Empty(t, second.Games)
+		assert.Zero(t, second.Total)
+
+		// Verify mocks
+		assert.Equal(t, 1, store.setCalls(emptyKey))
+		assert.Zero(t, store.setCalls(pageKey), "Empty results should not be cached as search pages")
+		mockGameDAO.AssertExpectations(t)
+		mockStatic.AssertExpectations(t)
+	})
+
+	t.Run("TestInvalidatesEmptySearchesWhenMatchingGamesAreImported", func(t *testing.T) {
+		// Setup
+		gameService, mockGameDAO, mockStatic, _ := newService()
+		searchNothing(mockGameDAO, mockStatic, "xyzzy")
+		searchNothing(mockGameDAO, mockStatic, "plugh")
+		gameService.SearchGames(ctx, "xyzzy", page, sort)
+		gameService.SearchGames(ctx, "plugh", page, sort)
+
+		// Another search imports a game whose title matches the first term
+		adventure := models.Game{ID: uuid.NewString(), Title: "Xyzzy: The Adventure", ExternalID: "xyzzy", ExternalSource: providers.StaticSourceName}
+		mockGameDAO.On("GetSearchIngestion", ctx, "adventure", providers.StaticSourceName).Return(models.SearchIngestion{Term: "adventure", ExternalSource: providers.StaticSourceName}, nil)
+		mockStatic.On("SearchGames", ctx, "adventure", 1).Return(providers.SearchResult{Games: []models.Game{adventure}, Total: 1}, nil)
+		mockGameDAO.On("UpsertManyGames", ctx, []models.Game{adventure}).Return(nil)
+		mockGameDAO.On("RecordSearchIngestion", ctx, models.SearchIngestion{Term: "adventure", ExternalSource: providers.StaticSourceName, LastPage: 1, RemoteCount: 1}).Return(nil)
+		mockGameDAO.On("SearchGames", ctx, "adventure", sort, page).Return(models.GamePage{Games: []models.Game{adventure}, Total: 1}, nil)
+
+		// Call the service
+		_, err := gameService.SearchGames(ctx, "adventure", page, sort)
+
+		// Assertions
+		assert.NoError(t, err)
+		_, found, _ := gameService.emptySearches.Get(ctx, database.GetCacheKey(database.CACHE_SEARCH_EMPTY_KEY_PREFIX, "xyzzy"))
+		assert.False(t, found, "The empty search matching the imported title should be evicted")
+		_, found, _ = gameService.emptySearches.Get(ctx, database.GetCacheKey(database.CACHE_SEARCH_EMPTY_KEY_PREFIX, "plugh"))
+		assert.True(t, found, "The other empty searches should be kept")
+
+		// Verify mocks
+		mockGameDAO.AssertExpectations(t)
+		mockStatic.AssertExpectations(t)
+	})
+
+	t.Run("TestDoesNotCacheEmptySearchesWhenDisabled", func(t *testing.T) {
+		// Setup
+		t.Setenv("CACHE_NEGATIVE_TTL_MINUTES", "0")
+		gameService, mockGameDAO, mockStatic, store := newService()
+		searchNothing(mockGameDAO, mockStatic, "xyzzy")
+		mockGameDAO.On("GetSearchIngestion", ctx, "xyzzy", providers.StaticSourceName).Return(models.SearchIngestion{Term: "xyzzy", ExternalSource: providers.StaticSourceName, LastPage: 1}, nil).Once()
+		mockGameDAO.On("SearchGames", ctx, "xyzzy", sort, page).Return(models.GamePage{}, nil).Once()
+
+		// Call the service twice
+		gameService.SearchGames(ctx, "xyzzy", page, sort)
+		result, err := gameService.SearchGames(ctx, "xyzzy", page, sort)
 
 		// Assertions
 		assert.NoError(t, err)
-		assert.Equal(t, expectedTotal, total)
-		assert.Equal(t, expectedGames, games)
+		assert.Empty(t, result.Games)
 
 		// Verify mocks
+		assert.Zero(t, store.setCalls(database.GetCacheKey(database.CACHE_SEARCH_EMPTY_KEY_PREFIX, "xyzzy")))
 		mockGameDAO.AssertExpectations(t)
+		mockStatic.AssertExpectations(t)
 	})
 }
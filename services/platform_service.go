@@ -0,0 +1,104 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/melkdesousa/gamgo/dao/models"
+)
+
+// maxPlatformPages bounds the pages read from each RAWG platform listing while syncing.
+const maxPlatformPages = 20
+
+// PlatformService encapsulates business logic related to the platform catalog.
+type PlatformService struct {
+	platformDAO PlatformDAO
+	rawgAPI     RawgPlatformAPI
+}
+
+// NewPlatformService creates a new PlatformService.
+func NewPlatformService(platformDAO PlatformDAO, rawgAPI RawgPlatformAPI) *PlatformService {
+	return &PlatformService{
+		platformDAO: platformDAO,
+		rawgAPI:     rawgAPI,
+	}
+}
+
+// SyncPlatforms stores the platforms and platform families of RAWG, linking each platform
+// to its family. It returns the number of platforms and families stored.
+func (s *PlatformService) SyncPlatforms(ctx context.Context) (int, error) {
+	var platforms []models.Platform
+	parentOf := map[string]string{}
+	for page := 1; page <= maxPlatformPages; page++ {
+		resp, err := s.rawgAPI.ListParentPlatforms(ctx, page)
+		if err != nil {
+			log.Printf("Error fetching parent platforms page %d from RAWG API: %v", page, err)
+			return 0, fmt.Errorf("failed to fetch parent platforms: %w", err)
+		}
+		for _, parent := range resp.Results {
+			platforms = append(platforms, models.Platform{Slug: parent.Slug, Name: parent.Name, IsParent: true})
+			for _, child := range parent.Platforms {
+				parentOf[child.Slug] = parent.Slug
+			}
+		}
+		if !resp.HasNext() {
+			break
+		}
+	}
+	for page := 1; page <= maxPlatformPages; page++ {
+		resp, err := s.rawgAPI.ListPlatforms(ctx, page)
+		if err != nil {
+			log.Printf("Error fetching platforms page %d from RAWG API: %v", page, err)
+			return 0, fmt.Errorf("failed to fetch platforms: %w", err)
+		}
+		for _, platform := range resp.Results {
+			platforms = append(platforms, models.Platform{Slug: platform.Slug, Name: platform.Name, ParentSlug: parentOf[platform.Slug]})
+		}
+		if !resp.HasNext() {
+			break
+		}
+	}
+	if err := s.platformDAO.UpsertPlatforms(ctx, platforms); err != nil {
+		log.Printf("Error storing platforms: %v", err)
+		return 0, fmt.Errorf("failed to store platforms: %w", err)
+	}
+	log.Printf("Synced %d platforms and platform families from RAWG API", len(platforms))
+	return len(platforms), nil
+}
+
+// ListPlatforms retrieves the platform catalog as a tree: families with their platforms,
+// followed by the platforms without a family.
+func (s *PlatformService) ListPlatforms(ctx context.Context) ([]models.Platform, error) {
+	platforms, err := s.platformDAO.ListPlatforms(ctx)
+	if err != nil {
+		log.Printf("Error listing platforms from database: %v", err)
+		return nil, fmt.Errorf("failed to list platforms: %w", err)
+	}
+	return buildPlatformTree(platforms), nil
+}
+
+// buildPlatformTree nests platforms under their parents, keeping the given order.
+// Platforms whose parent is missing are kept at the root.
+func buildPlatformTree(platforms []models.Platform) []models.Platform {
+	parents := map[string]int{}
+	tree := []models.Platform{}
+	for _, platform := range platforms {
+		if platform.IsParent {
+			parents[platform.ID] = len(tree)
+			platform.Children = []models.Platform{}
+			tree = append(tree, platform)
+		}
+	}
+	for _, platform := range platforms {
+		if platform.IsParent {
+			continue
+		}
+		if i, ok := parents[platform.ParentID]; ok {
+			tree[i].Children = append(tree[i].Children, platform)
+		} else {
+			tree = append(tree, platform)
+		}
+	}
+	return tree
+}
@@ -0,0 +1,112 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/melkdesousa/gamgo/dao/models"
+	"github.com/melkdesousa/gamgo/external/rawg"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockPlatformDAO is a mock implementation of the PlatformDAO
+type MockPlatformDAO struct {
+	mock.Mock
+}
+
+func (m *MockPlatformDAO) UpsertPlatforms(ctx context.Context, platforms []models.Platform) error {
+	args := m.Called(ctx, platforms)
+	return args.Error(0)
+}
+
+func (m *MockPlatformDAO) ListPlatforms(ctx context.Context) ([]models.Platform, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]models.Platform), args.Error(1)
+}
+
+// MockRawgPlatformAPI is a mock implementation of the RawgPlatformAPI
+type MockRawgPlatformAPI struct {
+	mock.Mock
+}
+
+func (m *MockRawgPlatformAPI) ListPlatforms(ctx context.Context, page int) (*rawg.PlatformListResponse, error) {
+	args := m.Called(ctx, page)
+	return args.Get(0).(*rawg.PlatformListResponse), args.Error(1)
+}
+
+func (m *MockRawgPlatformAPI) ListParentPlatforms(ctx context.Context, page int) (*rawg.ParentPlatformListResponse, error) {
+	args := m.Called(ctx, page)
+	return args.Get(0).(*rawg.ParentPlatformListResponse), args.Error(1)
+}
+
+func TestPlatformServiceUnit(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("TestSyncPlatforms", func(t *testing.T) {
+		// Setup
+		mockPlatformDAO := &MockPlatformDAO{}
+		mockRawgAPI := &MockRawgPlatformAPI{}
+		platformService := NewPlatformService(mockPlatformDAO, mockRawgAPI)
+
+		mockRawgAPI.On("ListParentPlatforms", ctx, 1).Return(&rawg.ParentPlatformListResponse{
+			Next: "https://api.rawg.io/api/platforms/lists/parents?page=2",
+			Results: []rawg.ParentPlatform{
+				{Slug: "playstation", Name: "PlayStation", Platforms: []rawg.Genre{{Slug: "playstation5", Name: "PlayStation 5"}}},
+			},
+		}, nil)
+		mockRawgAPI.On("ListParentPlatforms", ctx, 2).Return(&rawg.ParentPlatformListResponse{
+			Results: []rawg.ParentPlatform{
+				{Slug: "pc", Name: "PC", Platforms: []rawg.Genre{{Slug: "pc", Name: "PC"}}},
+			},
+		}, nil)
+		mockRawgAPI.On("ListPlatforms", ctx, 1).Return(&rawg.PlatformListResponse{
+			Results: []rawg.Genre{{Slug: "pc", Name: "PC"}, {Slug: "playstation5", Name: "PlayStation 5"}, {Slug: "3do", Name: "3DO"}},
+		}, nil)
+		mockPlatformDAO.On("UpsertPlatforms", ctx, []models.Platform{
+			{Slug: "playstation", Name: "PlayStation", IsParent: true},
+			{Slug: "pc", Name: "PC", IsParent: true},
+			{Slug: "pc", Name: "PC", ParentSlug: "pc"},
+			{Slug: "playstation5", Name: "PlayStation 5", ParentSlug: "playstation"},
+			{Slug: "3do", Name: "3DO"},
+		}).Return(nil)
+
+		// Call the service
+		count, err := platformService.SyncPlatforms(ctx)
+
+		// Assertions
+		assert.NoError(t, err)
+		assert.Equal(t, 5, count)
+
+		// Verify mocks
+		mockRawgAPI.AssertExpectations(t)
+		mockPlatformDAO.AssertExpectations(t)
+	})
+
+	t.Run("TestListPlatformsTree", func(t *testing.T) {
+		// Setup
+		mockPlatformDAO := &MockPlatformDAO{}
+		platformService := NewPlatformService(mockPlatformDAO, &MockRawgPlatformAPI{})
+		mockPlatformDAO.On("ListPlatforms", ctx).Return([]models.Platform{
+			{ID: "1", Slug: "playstation", Name: "PlayStation", IsParent: true, GamesCount: 3},
+			{ID: "2", Slug: "3do", Name: "3DO", GamesCount: 1},
+			{ID: "3", Slug: "playstation4", Name: "PlayStation 4", ParentID: "1", GamesCount: 2},
+			{ID: "4", Slug: "playstation5", Name: "PlayStation 5", ParentID: "1", GamesCount: 2},
+		}, nil)
+
+		// Call the service
+		tree, err := platformService.ListPlatforms(ctx)
+
+		// Assertions
+		assert.NoError(t, err)
+		if assert.Len(t, tree, 2) {
+			assert.Equal(t, "playstation", tree[0].Slug)
+			assert.Equal(t, 3, tree[0].GamesCount)
+			assert.Len(t, tree[0].Children, 2)
+			assert.Equal(t, "3do", tree[1].Slug, "Platforms without a family should stay at the root")
+		}
+
+		// Verify mocks
+		mockPlatformDAO.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// popularityCounter counts popular requests in memory and adds them to a Popularity in
+// batches on a timer, so requests neither wait for the shared counts nor contend on them.
+// Up to maxPending distinct requests are counted between flushes, the hits of the others
+// being dropped.
+type popularityCounter struct {
+	popularity Popularity
+	maxPending int
+	timeout    time.Duration
+	mu         sync.Mutex
+	pending    map[string]map[string]int // hits by request by popularity set
+	size       int
+}
+
+// newPopularityCounter starts flushing the requests counted to popularity every interval,
+// each flush being bounded by timeout.
+func newPopularityCounter(popularity Popularity, maxPending int, interval, timeout time.Duration) *popularityCounter {
+	c := &popularityCounter{
+		popularity: popularity,
+		maxPending: maxPending,
+		timeout:    timeout,
+		pending:    map[string]map[string]int{},
+	}
+	go c.run(max(interval, time.Second))
+	return c
+}
+
+// count counts a hit of member in set, dropping it when too many requests are pending.
+func (c *popularityCounter) count(set, member string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	hits := c.pending[set]
+	if hits == nil {
+		hits = map[string]int{}
+		c.pending[set] = hits
+	}
+	if _, ok := hits[member]; !ok {
+		if c.size >= c.maxPending {
+			searchCacheMetrics.Add("popular_dropped", 1)
+			return
+		}
+		c.size++
+	}
+	hits[member]++
+}
+
+func (c *popularityCounter) run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+		c.flush(ctx)
+		cancel()
+	}
+}
+
+// flush adds the pending hits to popularity, logging instead of failing on errors, the
+// hits being lost then.
+func (c *popularityCounter) flush(ctx context.Context) {
+	c.mu.Lock()
+	pending := c.pending
+	c.pending, c.size = map[string]map[string]int{}, 0
+	c.mu.Unlock()
+	for set, hits := range pending {
+		if err := c.popularity.Add(ctx, set, hits); err != nil {
+			log.Printf("Error counting %d popular requests in %s: %v", len(hits), set, err)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// searchCacheMetrics counts the hits and misses of the search cache and the background
+// refreshes of stale pages, published by expvar.
+var searchCacheMetrics = expvar.NewMap("search_cache")
+
+// errRefreshSkipped is returned by the refreshes found unnecessary once started.
+var errRefreshSkipped = errors.New("refresh skipped")
+
+// refreshJob refreshes the cache entry of key.
+type refreshJob struct {
+	key     string
+	refresh func(ctx context.Context) error
+}
+
+// refresher refreshes stale cache entries in the background, with a bounded number of
+// workers and a bounded queue. An entry is refreshed once at a time, and the refreshes
+// scheduled while the queue is full are dropped, the entry being refreshed by a later hit.
+type refresher struct {
+	jobs    chan refreshJob
+	timeout time.Duration
+	mu      sync.Mutex
+	pending map[string]bool
+}
+
+// newRefresher starts the workers of a refresher, each refresh being bounded by timeout.
+func newRefresher(workers, queueSize int, timeout time.Duration) *refresher {
+	r := &refresher{
+		jobs:    make(chan refreshJob, max(queueSize, 0)),
+		timeout: timeout,
+		pending: map[string]bool{},
+	}
+	for range max(workers, 1) {
+		go r.work()
+	}
+	return r
+}
+
+// schedule queues the refresh of the entry of key, unless it is already pending or the
+// queue is full.
+func (r *refresher) schedule(key string, refresh func(ctx context.Context) error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.pending[key] {
+		searchCacheMetrics.Add("refresh_skipped", 1)
+		return
+	}
+	select {
+	case r.jobs <- refreshJob{key: key, refresh: refresh}:
+		r.pending[key] = true
+		searchCacheMetrics.Add("refresh_scheduled", 1)
+		log.Printf("Scheduled the refresh of cache key %s", key)
+	default:
+		searchCacheMetrics.Add("refresh_dropped", 1)
+		log.Printf("Refresh queue full, dropping the refresh of cache key %s", key)
+	}
+}
+
+func (r *refresher) work() {
+	for job := range r.jobs {
+		start := time.Now()
+		err := r.run(job)
+		r.mu.Lock()
+		delete(r.pending, job.key)
+		r.mu.Unlock()
+		if errors.Is(err, errRefreshSkipped) {
+			searchCacheMetrics.Add("refresh_skipped", 1)
+			log.Printf("Skipped the refresh of cache key %s: %v", job.key, err)
+			continue
+		}
+		if err != nil {
+			searchCacheMetrics.Add("refresh_failed", 1)
+			log.Printf("Error refreshing cache key %s: %v", job.key, err)
+			continue
+		}
+		searchCacheMetrics.Add("refresh_succeeded", 1)
+		log.Printf("Refreshed cache key %s in %v", job.key, time.Since(start))
+	}
+}
+
+// run runs a refresh, reporting panics as errors so a worker is not lost.
+func (r *refresher) run(job refreshJob) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("refresh panicked: %v", recovered)
+		}
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+	defer cancel()
+	return job.refresh(ctx)
+}
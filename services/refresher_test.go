@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRefresher(t *testing.T) {
+	t.Run("TestRefreshesAKeyOnceAtATime", func(t *testing.T) {
+		// Setup
+		r := newRefresher(1, 10, time.Second)
+		release := make(chan struct{})
+		done := make(chan struct{})
+		runs := 0
+		refresh := func(context.Context) error {
+			runs++
+			<-release
+			close(done)
+			return nil
+		}
+		skipped := counter("refresh_skipped")
+
+		// Schedule the refresh twice while it is pending
+		r.schedule("key", refresh)
+		r.schedule("key", refresh)
+		close(release)
+		<-done
+
+		// Assertions
+		assert.Equal(t, 1, runs)
+		assert.Equal(t, skipped+1, counter("refresh_skipped"))
+	})
+
+	t.Run("TestDropsRefreshesWhenTheQueueIsFull", func(t *testing.T) {
+		// Setup
+		r := newRefresher(1, 1, time.Second)
+		release := make(chan struct{})
+		started := make(chan struct{})
+		dropped := counter("refresh_dropped")
+
+		// Fill the worker and the queue
+		r.schedule("busy", func(context.Context) error {
+			close(started)
+			<-release
+			return nil
+		})
+		<-started
+		r.schedule("queued", func(context.Context) error { return nil })
+		r.schedule("dropped", func(context.Context) error { return nil })
+		close(release)
+
+		// Assertions
+		assert.Equal(t, dropped+1, counter("refresh_dropped"))
+	})
+
+	t.Run("TestRecoversFromFailingRefreshes", func(t *testing.T) {
+		// Setup
+		r := newRefresher(1, 10, time.Second)
+		done := make(chan error, 1)
+
+		// Schedule a panicking refresh, then another one
+		r.schedule("panic", func(context.Context) error { panic("boom") })
+		r.schedule("error", func(ctx context.Context) error {
+			_, hasDeadline := ctx.Deadline()
+			assert.True(t, hasDeadline, "Refreshes should be bounded")
+			done <- errors.New("refresh failed")
+			return nil
+		})
+
+		// Assertions
+		select {
+		case err := <-done:
+			assert.Error(t, err)
+		case <-time.After(time.Second):
+			t.Fatal("The worker was lost to the panic")
+		}
+	})
+}
+
+// counter returns the value of a search cache counter, zero when it was never incremented.
+func counter(name string) int64 {
+	if value, ok := searchCacheMetrics.Get(name).(*expvar.Int); ok {
+		return value.Value()
+	}
+	return 0
+}
@@ -0,0 +1,168 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/melkdesousa/gamgo/config"
+	"github.com/melkdesousa/gamgo/dao/models"
+	"github.com/melkdesousa/gamgo/dao/search"
+	"github.com/melkdesousa/gamgo/database"
+	"golang.org/x/sync/errgroup"
+)
+
+// ErrWarmupRunning is returned when another instance is already warming the cache up.
+var ErrWarmupRunning = errors.New("cache warm-up already running")
+
+// Warmer fills the cache with the first pages of the most popular searches and game
+// listings, replaying them through a GameService, such as after Redis was flushed or a
+// deploy. The pages still cached are served from the cache, and only the others are read
+// from the database and the providers.
+type Warmer struct {
+	games      *GameService
+	popularity Popularity
+	// locker lets a single instance warm the cache up at a time, when not nil
+	locker          Locker
+	topSearches     int
+	topCatalogPages int
+	// concurrency bounds the pages replayed at once, which bounds the provider calls
+	concurrency int
+	timeout     time.Duration
+}
+
+// WarmupReport counts the pages replayed by a warm-up.
+type WarmupReport struct {
+	Searches     int
+	CatalogPages int
+	Failed       int
+}
+
+// NewWarmer creates a warmer replaying the WARMUP_TOP_SEARCHES most popular searches and
+// WARMUP_TOP_CATALOG_PAGES most popular game listings counted by popularity, at most
+// WARMUP_CONCURRENCY at once and for up to WARMUP_TIMEOUT_SECONDS.
+func NewWarmer(games *GameService, popularity Popularity, locker Locker) *Warmer {
+	return &Warmer{
+		games:           games,
+		popularity:      popularity,
+		locker:          locker,
+		topSearches:     config.GetEnvOrDefault("WARMUP_TOP_SEARCHES", 50),
+		topCatalogPages: config.GetEnvOrDefault("WARMUP_TOP_CATALOG_PAGES", 20),
+		concurrency:     max(config.GetEnvOrDefault("WARMUP_CONCURRENCY", 2), 1),
+		timeout:         time.Duration(config.GetEnvOrDefault("WARMUP_TIMEOUT_SECONDS", 300)) * time.Second,
+	}
+}
+
+// Warm replays the most popular first pages once. Pages failing to load are logged and
+// counted, the others are still replayed.
+func (w *Warmer) Warm(ctx context.Context) (WarmupReport, error) {
+	if w.locker != nil {
+		lockKey := database.GetCacheKey(database.CACHE_LOCK_KEY_PREFIX, "warmup")
+		unlock, locked, err := w.locker.TryLock(ctx, lockKey, w.timeout)
+		if err != nil {
+			log.Printf("Error acquiring lock %s, warming up without it: %v", lockKey, err)
+		} else if !locked {
+			return WarmupReport{}, ErrWarmupRunning
+		} else {
+			defer unlock()
+		}
+	}
+	ctx, cancel := context.WithTimeout(ctx, w.timeout)
+	defer cancel()
+
+	searches, err := w.popularRequests(ctx, popularSearches, w.topSearches)
+	if err != nil {
+		return WarmupReport{}, err
+	}
+	catalogPages, err := w.popularRequests(ctx, popularCatalogPages, w.topCatalogPages)
+	if err != nil {
+		return WarmupReport{}, err
+	}
+	log.Printf("Warming the cache up with %d searches and %d catalogue pages", len(searches), len(catalogPages))
+
+	var warmedSearches, warmedCatalogPages, failed atomic.Int64
+	group := errgroup.Group{}
+	group.SetLimit(w.concurrency)
+	for _, request := range append(searches, catalogPages...) {
+		group.Go(func() error {
+			if err := w.replay(ctx, request); err != nil {
+				log.Printf("Error warming the cache up with %+v: %v", request, err)
+				failed.Add(1)
+				return nil
+			}
+			if request.Filter != nil {
+				warmedCatalogPages.Add(1)
+			} else {
+				warmedSearches.Add(1)
+			}
+			return nil
+		})
+	}
+	group.Wait()
+	report := WarmupReport{
+		Searches:     int(warmedSearches.Load()),
+		CatalogPages: int(warmedCatalogPages.Load()),
+		Failed:       int(failed.Load()),
+	}
+	log.Printf("Warmed the cache up with %d searches and %d catalogue pages, %d failed", report.Searches, report.CatalogPages, report.Failed)
+	return report, ctx.Err()
+}
+
+// Run warms the cache up every interval until ctx is done.
+func (w *Warmer) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := w.Warm(ctx); err != nil {
+				log.Printf("Error warming the cache up: %v", err)
+			}
+		}
+	}
+}
+
+// popularRequests returns the n most popular requests of a popularity set, skipping those
+// that cannot be decoded.
+func (w *Warmer) popularRequests(ctx context.Context, set string, n int) ([]popularRequest, error) {
+	members, err := w.popularity.Top(ctx, set, n)
+	if err != nil {
+		log.Printf("Error reading popular requests of %s: %v", set, err)
+		return nil, fmt.Errorf("failed to read popular requests: %w", err)
+	}
+	requests := make([]popularRequest, 0, len(members))
+	for _, member := range members {
+		var request popularRequest
+		if err := json.Unmarshal([]byte(member), &request); err != nil {
+			log.Printf("Skipping unreadable popular request %q of %s: %v", member, set, err)
+			continue
+		}
+		requests = append(requests, request)
+	}
+	return requests, nil
+}
+
+// replay requests the first page of a popular request, without counting it again.
+func (w *Warmer) replay(ctx context.Context, request popularRequest) error {
+	page := models.NewPageRequest(1, request.Limit, "")
+	sort, err := models.ParseGameSort(string(request.Sort), request.Order, request.Filter == nil || request.Filter.HasTitle())
+	if err != nil {
+		return err
+	}
+	if request.Filter != nil {
+		_, err := w.games.listGames(ctx, *request.Filter, sort, page)
+		return err
+	}
+	query, err := search.Parse(request.Title)
+	if err != nil {
+		return fmt.Errorf("failed to parse search query: %w", err)
+	}
+	_, err = w.games.searchGames(ctx, request.Title, query, page, sort)
+	return err
+}
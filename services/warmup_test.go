@@ -0,0 +1,240 @@
+package services
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/melkdesousa/gamgo/dao/models"
+	"github.com/melkdesousa/gamgo/database"
+	"github.com/melkdesousa/gamgo/external/providers"
+	"github.com/melkdesousa/gamgo/external/rawg"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// fakePopularity counts requests in memory.
+type fakePopularity struct {
+	mu     sync.Mutex
+	counts map[string]map[string]int
+}
+
+func newFakePopularity() *fakePopularity {
+	return &fakePopularity{counts: map[string]map[string]int{}}
+}
+
+func (p *fakePopularity) Add(ctx context.Context, set string, hits map[string]int) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.counts[set] == nil {
+		p.counts[set] = map[string]int{}
+	}
+	for member, count := range hits {
+		p.counts[set][member] += count
+	}
+	return nil
+}
+
+func (p *fakePopularity) Top(ctx context.Context, set string, n int) ([]string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	members := []string{}
+	for member := range p.counts[set] {
+		members = append(members, member)
+	}
+	slices.SortFunc(members, func(a, b string) int {
+		return cmp.Or(cmp.Compare(p.counts[set][b], p.counts[set][a]), cmp.Compare(a, b))
+	})
+	return members[:min(n, len(members))], nil
+}
+
+// count returns the number of requests counted for request in set.
+func (p *fakePopularity) count(set string, request popularRequest) int {
+	member, _ := json.Marshal(request)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.counts[set][string(member)]
+}
+
+func TestGameServicePopularity(t *testing.T) {
+	ctx := context.Background()
+	sort := models.GameSort{Field: models.SortRelevance, Desc: true}
+	page := models.NewPageRequest(1, models.DefaultPageSize, "")
+
+	t.Run("TestCountsFirstPages", func(t *testing.T) {
+		// Setup
+		mockGameDAO := &MockGameDAO{}
+		popularity := newFakePopularity()
+		gameService := NewGameService(mockGameDAO, newTestStore(), nil, popularity, providers.NewRegistry(providers.NewRawgProvider(&MockRawgAPI{})))
+		secondPage := models.NewPageRequest(2, models.DefaultPageSize, "")
+		mockGameDAO.On("GetSearchIngestion", ctx, "zelda", rawg.SourceName).Return(models.SearchIngestion{Term: "zelda", ExternalSource: rawg.SourceName, LastPage: 1, RemoteCount: 1}, nil)
+		zelda := models.GamePage{Games: []models.Game{{ID: uuid.NewString(), Title: "Zelda"}}, Total: 1}
+		mockGameDAO.On("SearchGames", ctx, "Zelda", sort, page).Return(zelda, nil)
+		mockGameDAO.On("SearchGames", ctx, "Zelda", sort, secondPage).Return(zelda, nil)
+
+		// Call the service, the first page twice as it is cached
+		gameService.SearchGames(ctx, "Zelda", page, sort)
+		gameService.SearchGames(ctx, "Zelda", page, sort)
+		gameService.SearchGames(ctx, "Zelda", secondPage, sort)
+		gameService.popular.flush(ctx)
+
+		// Assertions
+		first := popularRequest{Title: "zelda", Sort: sort.Field, Order: sort.Order(), Limit: models.DefaultPageSize}
+		assert.Equal(t, 2, popularity.count(popularSearches, first), "Cached searches should be counted by their canonical query")
+		top, _ := popularity.Top(ctx, popularSearches, 10)
+		assert.Len(t, top, 1, "Only first pages should be counted")
+
+		// Verify mocks
+		mockGameDAO.AssertExpectations(t)
+	})
+
+	t.Run("TestCachesCatalogPages", func(t *testing.T) {
+		// Setup
+		mockGameDAO := &MockGameDAO{}
+		popularity := newFakePopularity()
+		gameService := NewGameService(mockGameDAO, newTestStore(), nil, popularity, providers.NewRegistry())
+		filter := models.GameFilter{Genres: []string{"indie"}}
+		sort := models.GameSort{Field: models.SortRating, Desc: true}
+		expectedPage := models.GamePage{Games: []models.Game{{ID: uuid.NewString(), Title: "Celeste"}}, Total: 1}
+		mockGameDAO.On("ListGames", ctx, filter, sort, page).Return(expectedPage, nil).Once()
+
+		// Call the service twice
+		gameService.ListGames(ctx, filter, sort, page)
+		result, err := gameService.ListGames(ctx, filter, sort, page)
+		gameService.popular.flush(ctx)
+
+		// Assertions
+		assert.NoError(t, err)
+		assert.Equal(t, expectedPage, result)
+		assert.Equal(t, 2, popularity.count(popularCatalogPages, popularRequest{Filter: &filter, Sort: sort.Field, Order: sort.Order(), Limit: page.Limit}))
+
+		// Verify mocks
+		mockGameDAO.AssertExpectations(t)
+	})
+
+	t.Run("TestDoesNotCountFailedOrEmptyRequests", func(t *testing.T) {
+		// Setup
+		mockGameDAO := &MockGameDAO{}
+		popularity := newFakePopularity()
+		gameService := NewGameService(mockGameDAO, newTestStore(), nil, popularity, providers.NewRegistry())
+		broken := models.GameFilter{Genres: []string{"indie"}}
+		empty := models.GameFilter{Genres: []string{"none"}}
+		mockGameDAO.On("ListGames", ctx, broken, sort, page).Return(models.GamePage{}, errors.New("connection refused"))
+		mockGameDAO.On("ListGames", ctx, empty, sort, page).Return(models.GamePage{}, nil)
+
+		// Call the service
+		_, err := gameService.ListGames(ctx, broken, sort, page)
+		assert.Error(t, err)
+		gameService.ListGames(ctx, empty, sort, page)
+		gameService.popular.flush(ctx)
+
+		// Assertions
+		top, _ := popularity.Top(ctx, popularCatalogPages, 10)
+		assert.Empty(t, top)
+
+		// Verify mocks
+		mockGameDAO.AssertExpectations(t)
+	})
+
+	t.Run("TestDropsRequestsBeyondThePendingBound", func(t *testing.T) {
+		// Setup
+		t.Setenv("POPULARITY_MAX_PENDING", "1")
+		mockGameDAO := &MockGameDAO{}
+		popularity := newFakePopularity()
+		gameService := NewGameService(mockGameDAO, newTestStore(), nil, popularity, providers.NewRegistry())
+		indie := models.GameFilter{Genres: []string{"indie"}}
+		rpg := models.GameFilter{Genres: []string{"rpg"}}
+		mockGameDAO.On("ListGames", ctx, indie, sort, page).Return(models.GamePage{Games: []models.Game{{ID: uuid.NewString(), Title: "Celeste"}}, Total: 1}, nil)
+		mockGameDAO.On("ListGames", ctx, rpg, sort, page).Return(models.GamePage{Games: []models.Game{{ID: uuid.NewString(), Title: "Baldur's Gate 3"}}, Total: 1}, nil)
+
+		// Call the service
+		gameService.ListGames(ctx, indie, sort, page)
+		gameService.ListGames(ctx, rpg, sort, page)
+		gameService.ListGames(ctx, indie, sort, page)
+		gameService.popular.flush(ctx)
+
+		// Assertions
+		assert.Equal(t, 2, popularity.count(popularCatalogPages, popularRequest{Filter: &indie, Sort: sort.Field, Order: sort.Order(), Limit: page.Limit}), "Pending requests should still be counted")
+		assert.Zero(t, popularity.count(popularCatalogPages, popularRequest{Filter: &rpg, Sort: sort.Field, Order: sort.Order(), Limit: page.Limit}))
+	})
+}
+
+func TestWarmer(t *testing.T) {
+	ctx := context.Background()
+	relevance := models.GameSort{Field: models.SortRelevance, Desc: true}
+	byTitle := models.GameSort{Field: models.SortTitle}
+	// member encodes a popular request as counted
+	member := func(request popularRequest) string {
+		encoded, _ := json.Marshal(request)
+		return string(encoded)
+	}
+
+	t.Run("TestReplaysTheMostPopularFirstPages", func(t *testing.T) {
+		// Setup
+		t.Setenv("WARMUP_TOP_SEARCHES", "2")
+		mockGameDAO := &MockGameDAO{}
+		popularity := newFakePopularity()
+		store := newTestStore()
+		gameService := NewGameService(mockGameDAO, store, nil, popularity, providers.NewRegistry(providers.NewRawgProvider(&MockRawgAPI{})))
+		filter := models.GameFilter{Platforms: []string{"pc"}, PlatformMatch: models.PlatformMatchAny}
+		for request, count := range map[string]int{
+			member(popularRequest{Title: "zelda", Sort: models.SortRelevance, Order: "desc", Limit: 10}): 5,
+			member(popularRequest{Title: "mario", Sort: models.SortRelevance, Order: "desc", Limit: 20}): 3,
+			member(popularRequest{Title: "myst", Sort: models.SortRelevance, Order: "desc", Limit: 10}):  1,
+			"not json": 4,
+		} {
+			popularity.Add(ctx, popularSearches, map[string]int{request: count})
+		}
+		popularity.Add(ctx, popularCatalogPages, map[string]int{member(popularRequest{Filter: &filter, Sort: models.SortTitle, Order: "asc", Limit: 10}): 1})
+		// the unreadable request counts towards the top searches, leaving out Mario and Myst
+		mockGameDAO.On("GetSearchIngestion", mock.Anything, "zelda", rawg.SourceName).Return(models.SearchIngestion{Term: "zelda", ExternalSource: rawg.SourceName, LastPage: 1, RemoteCount: 1}, nil).Once()
+		mockGameDAO.On("SearchGames", mock.Anything, "zelda", relevance, models.NewPageRequest(1, 10, "")).Return(models.GamePage{Games: []models.Game{{ID: uuid.NewString(), Title: "Zelda"}}, Total: 1}, nil).Once()
+		mockGameDAO.On("ListGames", mock.Anything, filter, byTitle, models.NewPageRequest(1, 10, "")).Return(models.GamePage{Games: []models.Game{{ID: uuid.NewString(), Title: "Doom"}}, Total: 1}, nil).Once()
+
+		// Call the warmer
+		report, err := NewWarmer(gameService, popularity, nil).Warm(ctx)
+
+		// Assertions
+		assert.NoError(t, err)
+		assert.Equal(t, WarmupReport{Searches: 1, CatalogPages: 1}, report, "Unreadable requests should be skipped")
+		assert.Equal(t, 1, store.setCalls(database.GetCacheKey(database.CACHE_SEARCH_GAME_KEY_PREFIX, "zelda", relevance.String(), "10", "1")))
+
+		// Warm again, serving the pages from the cache
+		report, err = NewWarmer(gameService, popularity, nil).Warm(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, WarmupReport{Searches: 1, CatalogPages: 1}, report)
+
+		// Verify mocks
+		mockGameDAO.AssertExpectations(t)
+		gameService.popular.flush(ctx)
+		assert.Equal(t, 5, popularity.count(popularSearches, popularRequest{Title: "zelda", Sort: models.SortRelevance, Order: "desc", Limit: 10}), "Replays should not be counted")
+	})
+
+	t.Run("TestSkipsWhileAnotherInstanceWarmsUp", func(t *testing.T) {
+		// Setup
+		mockGameDAO := &MockGameDAO{}
+		mockLocker := &MockLocker{}
+		popularity := newFakePopularity()
+		popularity.Add(ctx, popularSearches, map[string]int{member(popularRequest{Title: "zelda", Sort: models.SortRelevance, Order: "desc", Limit: 10}): 1})
+		gameService := NewGameService(mockGameDAO, newTestStore(), mockLocker, popularity, providers.NewRegistry())
+		lockKey := database.GetCacheKey(database.CACHE_LOCK_KEY_PREFIX, "warmup")
+		mockLocker.On("TryLock", ctx, lockKey, 300*time.Second).Return(nil, false, nil).Once()
+
+		// Call the warmer
+		report, err := NewWarmer(gameService, popularity, mockLocker).Warm(ctx)
+
+		// Assertions
+		assert.ErrorIs(t, err, ErrWarmupRunning)
+		assert.Zero(t, report)
+
+		// Verify mocks
+		mockLocker.AssertExpectations(t)
+		mockGameDAO.AssertNotCalled(t, "SearchGames", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
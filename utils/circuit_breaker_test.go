@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	newBreaker := func() *CircuitBreaker {
+		breaker := NewCircuitBreaker(2, time.Minute)
+		breaker.now = func() time.Time { return now }
+		return breaker
+	}
+
+	t.Run("OpensAfterConsecutiveFailures", func(t *testing.T) {
+		breaker := newBreaker()
+		assert.NoError(t, breaker.Allow())
+		breaker.Failure()
+		assert.NoError(t, breaker.Allow(), "A single failure should not open the circuit")
+		breaker.Failure()
+		assert.ErrorIs(t, breaker.Allow(), ErrCircuitOpen)
+		assert.True(t, breaker.Open())
+	})
+
+	t.Run("SuccessResetsFailures", func(t *testing.T) {
+		breaker := newBreaker()
+		breaker.Failure()
+		breaker.Success()
+		breaker.Failure()
+		assert.NoError(t, breaker.Allow(), "Failures should have to be consecutive")
+	})
+
+	t.Run("HalfOpenAfterCooldown", func(t *testing.T) {
+		breaker := newBreaker()
+		breaker.Failure()
+		breaker.Failure()
+		now = now.Add(time.Minute)
+		assert.NoError(t, breaker.Allow(), "A trial call should be let through after the cooldown")
+		assert.ErrorIs(t, breaker.Allow(), ErrCircuitOpen, "Only one trial call should be in flight")
+
+		breaker.Failure()
+		assert.ErrorIs(t, breaker.Allow(), ErrCircuitOpen, "A failed trial should reopen the circuit")
+
+		now = now.Add(time.Minute)
+		assert.NoError(t, breaker.Allow())
+		breaker.Success()
+		assert.NoError(t, breaker.Allow(), "A successful trial should close the circuit")
+		assert.False(t, breaker.Open())
+	})
+
+	t.Run("AbortedTrialAllowsAnotherOne", func(t *testing.T) {
+		breaker := newBreaker()
+		breaker.Failure()
+		breaker.Failure()
+		now = now.Add(time.Minute)
+		assert.NoError(t, breaker.Allow())
+		breaker.Abort()
+		assert.NoError(t, breaker.Allow(), "An aborted trial should not hold the circuit half-open")
+	})
+}
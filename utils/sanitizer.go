@@ -1,10 +1,16 @@
 package utils
 
 import (
+	"html"
 	"regexp"
 	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
 )
 
+var htmlTagPattern = regexp.MustCompile(`<[^>]*>`)
+
 // Sanitize removes leading/trailing spaces and non-alphanumeric characters from the input string.
 func Sanitize(input string) string {
 	input = strings.TrimSpace(input)
@@ -13,10 +19,47 @@ func Sanitize(input string) string {
 	return re.ReplaceAllString(input, "")
 }
 
+// SanitizeArrayStrings splits a comma-separated list and sanitizes each item, dropping empty ones.
 func SanitizeArrayStrings(input string) []string {
 	sanitized := make([]string, 0)
 	for _, str := range strings.Split(input, ",") {
-		sanitized = append(sanitized, Sanitize(str))
+		if value := Sanitize(str); value != "" {
+			sanitized = append(sanitized, value)
+		}
 	}
 	return sanitized
 }
+
+var slugPattern = regexp.MustCompile(`[^a-z0-9-]`)
+
+// SanitizeSlugs splits a comma-separated list of slugs, lowercasing them and dropping
+// characters other than letters, digits and hyphens as well as empty items.
+func SanitizeSlugs(input string) []string {
+	slugs := make([]string, 0)
+	for _, str := range strings.Split(input, ",") {
+		if slug := slugPattern.ReplaceAllString(strings.ToLower(strings.TrimSpace(str)), ""); slug != "" {
+			slugs = append(slugs, slug)
+		}
+	}
+	return slugs
+}
+
+// StripHTML converts an HTML fragment to plain text, dropping tags and unescaping entities.
+func StripHTML(input string) string {
+	return strings.TrimSpace(html.UnescapeString(htmlTagPattern.ReplaceAllString(input, " ")))
+}
+
+var titleSeparatorPattern = regexp.MustCompile(`[^\p{L}\p{N}]+`)
+
+// NormalizeTitle reduces a title to lowercase letters and digits without accents,
+// separated by single spaces, so spelling variants of a title compare equal.
+func NormalizeTitle(title string) string {
+	decomposed := norm.NFKD.String(strings.ToLower(title))
+	var stripped strings.Builder
+	for _, r := range decomposed {
+		if !unicode.Is(unicode.Mn, r) {
+			stripped.WriteRune(r)
+		}
+	}
+	return strings.TrimSpace(titleSeparatorPattern.ReplaceAllString(stripped.String(), " "))
+}
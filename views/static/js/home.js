@@ -6,11 +6,24 @@ document.addEventListener('DOMContentLoaded', function () {
     const searchInput = document.getElementById('searchInput');
     const loading = document.getElementById('loading');
     const noMore = document.getElementById('noMore');
-    let page = 1;
+    let nextCursor = '';
     let loadingGames = false;
     let noMoreGames = false;
     let currentQuery = '';
 
+    function escapeHtml(text) {
+        const div = document.createElement('div');
+        div.textContent = text;
+        return div.innerHTML;
+    }
+
+    // Snippets come with matched terms wrapped in <mark> tags, everything else is escaped.
+    function highlightSnippet(snippet) {
+        return escapeHtml(snippet)
+            .replaceAll('&lt;mark&gt;', '<mark>')
+            .replaceAll('&lt;/mark&gt;', '</mark>');
+    }
+
     function createGameCard(game) {
         return /* html */`
         <div class="flex items-start gap-4 p-4 rounded-xl bg-base-200 shadow">
@@ -21,17 +34,21 @@ document.addEventListener('DOMContentLoaded', function () {
             </div>
             <div>
             <div class="font-bold text-lg">${game.title || 'No Title'}</div>
-            <div class="text-sm text-base-content/70">${game.description || 'No description.'}</div>
+            <div class="text-sm text-base-content/70">${game.snippet ? highlightSnippet(game.snippet) : (game.description || 'No description.')}</div>
             </div>
         </div>`;
     }
 
-    async function fetchGames(query, pageNum) {
+    // fetchGames loads the first page, or the page after nextCursor when one is known.
+    async function fetchGames(query, cursor) {
         loading.classList.remove('hidden');
+        const isFirstPage = !cursor;
+        const params = new URLSearchParams({ title: query });
+        if (cursor) params.set('cursor', cursor);
         try {
-            const res = await fetch(`/games/search?title=${encodeURIComponent(query)}&page=${pageNum}`);
+            const res = await fetch(`/games/search?${params}`);
             if (res.status === 404) {
-                if (pageNum === 1) gamesList.innerHTML = '';
+                if (isFirstPage) gamesList.innerHTML = '';
                 noMore.classList.remove('hidden');
                 loading.classList.add('hidden');
                 noMoreGames = true;
@@ -41,10 +58,12 @@ document.addEventListener('DOMContentLoaded', function () {
             const data = await res.json();
             loading.classList.add('hidden');
             noMore.classList.add('hidden');
+            nextCursor = data.next || '';
+            if (!nextCursor) noMoreGames = true;
             return data.data || data.Data || [];
         } catch (e) {
             loading.classList.add('hidden');
-            if (pageNum === 1) gamesList.innerHTML = /* html */`<div class="text-center text-error">Error loading games.</div>`;
+            if (isFirstPage) gamesList.innerHTML = /* html */`<div class="text-center text-error">Error loading games.</div>`;
             return [];
         }
     }
@@ -52,20 +71,20 @@ document.addEventListener('DOMContentLoaded', function () {
     async function loadMoreGames() {
         if (loadingGames || noMoreGames || !currentQuery) return;
         loadingGames = true;
-        const games = await fetchGames(currentQuery, page);
+        const isFirstPage = !nextCursor;
+        const games = await fetchGames(currentQuery, nextCursor);
         if (games.length === 0) {
             noMoreGames = true;
-            if (page === 1) noMore.classList.remove('hidden');
+            if (isFirstPage) noMore.classList.remove('hidden');
         } else {
             gamesList.insertAdjacentHTML('beforeend', games.map(createGameCard).join(''));
-            page++;
         }
         loadingGames = false;
     }
 
     function resetList() {
         gamesList.innerHTML = '';
-        page = 1;
+        nextCursor = '';
         noMoreGames = false;
         noMore.classList.add('hidden');
     }